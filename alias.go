@@ -0,0 +1,95 @@
+package vervet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// VersionAliasLatest is the alias of the latest version released, which may
+// be qualified with a stability, as in "latest~beta".
+const VersionAliasLatest = "latest"
+
+// ParseVersionAliases parses the versions pinned by named aliases, such as
+// "sdk-v3" for "2024-06-01", in the given version scheme. A nil scheme is the
+// date scheme.
+func ParseVersionAliases(scheme VersionScheme, aliases map[string]string) (map[string]Version, error) {
+	if scheme == nil {
+		scheme = DateScheme
+	}
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make(map[string]Version, len(aliases))
+	for _, name := range names {
+		if err := validateAliasName(scheme, name); err != nil {
+			return nil, err
+		}
+		v, err := scheme.ParseVersion(aliases[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version for alias %q: %w", name, err)
+		}
+		result[name] = v
+	}
+	return result, nil
+}
+
+// validateAliasName returns an error if an alias name could be mistaken for
+// a version or stability requested.
+func validateAliasName(scheme VersionScheme, name string) error {
+	if name == "" {
+		return fmt.Errorf("empty alias name")
+	}
+	if name == VersionAliasLatest {
+		return fmt.Errorf("alias %q is reserved", name)
+	}
+	if strings.Contains(name, "~") {
+		return fmt.Errorf("invalid alias %q: aliases may not have a stability", name)
+	}
+	if _, err := scheme.ParseVersion(name); err == nil {
+		return fmt.Errorf("invalid alias %q: aliases may not be versions", name)
+	}
+	if _, err := ParseStability(name); err == nil {
+		return fmt.Errorf("invalid alias %q: aliases may not be stabilities", name)
+	}
+	return nil
+}
+
+// IsAlias returns whether a requested version is an alias: the latest
+// version, optionally qualified with a stability, or an alias pinned by the
+// policy.
+func (p *VersioningPolicy) IsAlias(s string) bool {
+	if _, ok := p.Aliases[s]; ok {
+		return true
+	}
+	release, _, err := splitStability(s)
+	return err == nil && release == VersionAliasLatest
+}
+
+// ParseRequestedVersion parses a version requested by an API client, which
+// may be a version in the policy's scheme or an alias. Aliases pinned by the
+// policy are replaced by the version pinned. The latest version, optionally
+// qualified with a stability as in "latest~beta", is replaced by the latest
+// release in the index at that stability.
+//
+// The version returned is requested rather than resolved, so that it may be
+// resolved in the same way as any other version requested. Returns
+// ErrNoMatchingVersion if the latest version is requested from an empty
+// index.
+func (p *VersioningPolicy) ParseRequestedVersion(s string, index *VersionIndex) (Version, error) {
+	if v, ok := p.Aliases[s]; ok {
+		return v, nil
+	}
+	release, stability, err := splitStability(s)
+	if err == nil && release == VersionAliasLatest {
+		if len(index.versions) == 0 {
+			return Version{}, ErrNoMatchingVersion
+		}
+		latest := index.versions[len(index.versions)-1].release()
+		latest.Stability = stability
+		return latest, nil
+	}
+	return p.ParseVersion(s)
+}
@@ -0,0 +1,87 @@
+package vervet_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	. "github.com/snyk/vervet/v8"
+)
+
+func TestParseVersionAliases(t *testing.T) {
+	c := qt.New(t)
+	aliases, err := ParseVersionAliases(nil, map[string]string{
+		"sdk-v3":  "2024-06-01",
+		"preview": "2024-07-01~beta",
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(aliases, qt.DeepEquals, map[string]Version{
+		"sdk-v3":  MustParseVersion("2024-06-01"),
+		"preview": MustParseVersion("2024-07-01~beta"),
+	})
+
+	tests := []struct {
+		aliases map[string]string
+		err     string
+	}{{
+		map[string]string{"": "2024-06-01"},
+		`empty alias name`,
+	}, {
+		map[string]string{"latest": "2024-06-01"},
+		`alias "latest" is reserved`,
+	}, {
+		map[string]string{"sdk~beta": "2024-06-01"},
+		`invalid alias "sdk~beta": aliases may not have a stability`,
+	}, {
+		map[string]string{"2024-01-01": "2024-06-01"},
+		`invalid alias "2024-01-01": aliases may not be versions`,
+	}, {
+		map[string]string{"beta": "2024-06-01"},
+		`invalid alias "beta": aliases may not be stabilities`,
+	}, {
+		map[string]string{"sdk-v3": "v3"},
+		`invalid version for alias "sdk-v3": .*`,
+	}}
+	for _, test := range tests {
+		_, err := ParseVersionAliases(DateScheme, test.aliases)
+		c.Assert(err, qt.ErrorMatches, test.err)
+	}
+}
+
+func TestParseRequestedVersion(t *testing.T) {
+	c := qt.New(t)
+	policy := DefaultVersioningPolicy()
+	var err error
+	policy.Aliases, err = ParseVersionAliases(policy.Scheme, map[string]string{"sdk-v3": "2024-06-01"})
+	c.Assert(err, qt.IsNil)
+	index := NewVersionIndex(VersionSlice{
+		MustParseVersion("2024-01-01~beta"),
+		MustParseVersion("2024-05-01"),
+		MustParseVersion("2024-08-01~experimental"),
+	})
+	tests := []struct {
+		requested, parsed string
+		alias             bool
+	}{{
+		"sdk-v3", "2024-06-01", true,
+	}, {
+		"latest", "2024-08-01", true,
+	}, {
+		"latest~beta", "2024-08-01~beta", true,
+	}, {
+		"2024-02-01~beta", "2024-02-01~beta", false,
+	}}
+	for _, test := range tests {
+		c.Run(test.requested, func(c *qt.C) {
+			c.Assert(policy.IsAlias(test.requested), qt.Equals, test.alias)
+			v, err := policy.ParseRequestedVersion(test.requested, &index)
+			c.Assert(err, qt.IsNil)
+			c.Assert(v.String(), qt.Equals, test.parsed)
+		})
+	}
+
+	_, err = policy.ParseRequestedVersion("latest", &VersionIndex{})
+	c.Assert(err, qt.ErrorIs, ErrNoMatchingVersion)
+	_, err = policy.ParseRequestedVersion("sdk-v4", &index)
+	c.Assert(err, qt.IsNotNil)
+}
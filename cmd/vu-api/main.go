@@ -47,7 +47,10 @@ func main() {
 		log.Fatal().Err(err).Msg("unable to initialize storage client")
 	}
 
-	h := handler.New(cfg, st, handler.UseDefaultMiddleware)
+	h, err := handler.New(cfg, st, handler.UseDefaultMiddleware)
+	if err != nil {
+		log.Fatal().Err(err).Msg("unable to initialize handler")
+	}
 
 	srv := &http.Server{
 		Addr: fmt.Sprintf("%s:8080", cfg.Host),
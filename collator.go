@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
+	"strings"
 	"unicode"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -108,12 +110,15 @@ func (c *Collator) mergeTags(rv *ResourceVersion) error {
 		m[t.Name] = t
 	}
 	var errs error
-	for _, t := range rv.T.Tags {
+	for i, t := range rv.T.Tags {
 		if current, ok := m[t.Name]; ok && !tagsEqual(current, t) && c.strictTags {
 			// If there is a conflict and we're collating with strict tags, indicate an error.
 			errs = multierr.Append(
 				errs,
-				fmt.Errorf("conflict in #/tags %s: %s and %s differ", t.Name, rv.path, c.tagSources[t.Name]),
+				rv.Diagnostic(
+					fmt.Errorf("conflict in #/tags %s: %s and %s differ", t.Name, rv.path, c.tagSources[t.Name]),
+					"tags", strconv.Itoa(i),
+				),
 			)
 		} else {
 			// Otherwise last tag with this key wins.
@@ -229,7 +234,10 @@ func (c *Collator) mergeComponents(rv *ResourceVersion) error {
 			c.componentSources[ref] = rv.path
 		}
 	}
-	return inliner.Inline(rv.T)
+	if err := inliner.Inline(rv.T); err != nil {
+		return rv.Diagnostic(err, "components")
+	}
+	return nil
 }
 
 var cmpComponents = cmp.Options{
@@ -280,7 +288,10 @@ func (c *Collator) mergePaths(rv *ResourceVersion) error {
 				} else {
 					errs = multierr.Append(
 						errs,
-						fmt.Errorf("conflict in #/paths %s: declared in both %s and %s", k, rv.path, c.pathSources[k]),
+						rv.Diagnostic(
+							fmt.Errorf("conflict in #/paths %s: declared in both %s and %s", k, rv.path, c.pathSources[k]),
+							"paths", k, strings.ToLower(opName),
+						),
 					)
 				}
 			} else {
@@ -1,6 +1,7 @@
 package vervet_test
 
 import (
+	"sort"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
@@ -191,6 +192,19 @@ func TestCollatePathConflict(t *testing.T) {
 	err = collator.Collate(examples2v)
 	c.Assert(err, qt.ErrorMatches, `.*conflict in #/paths /examples/hello-world/{id2}: declared in both.*`)
 	c.Assert(err, qt.ErrorMatches, `.*conflict in #/paths /examples/hello-world: declared in both.*`)
+
+	// Conflicts are located in the source of the conflicting resource version.
+	diags := vervet.AsDiagnostics(err)
+	c.Assert(diags, qt.HasLen, 2)
+	sort.Slice(diags, func(i, j int) bool { return diags[i].Line < diags[j].Line })
+	specPath := testdata.Path("conflict/_examples2/2021-06-15/spec.yaml")
+	c.Assert(diags[0].File, qt.Equals, specPath)
+	c.Assert(diags[0].Pointer, qt.Equals, "/paths/~1examples~1hello-world/post")
+	c.Assert(diags[0].Line, qt.Equals, 11)
+	c.Assert(diags[0].Column, qt.Equals, 5)
+	c.Assert(diags[1].File, qt.Equals, specPath)
+	c.Assert(diags[1].Pointer, qt.Equals, "/paths/~1examples~1hello-world~1{id2}/get")
+	c.Assert(diags[1].Line, qt.Equals, 42)
 }
 
 func TestCollateMergingResources(t *testing.T) {
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/bmatcuk/doublestar/v4"
+
+	"github.com/snyk/vervet/v8"
 )
 
 // APIs defines a named map of API instances.
@@ -14,10 +16,16 @@ type APIs map[string]*API
 // source collection of individual resource specifications and additional
 // overlay content to merge.
 type API struct {
-	Name      string         `json:"-"`
-	Resources []*ResourceSet `json:"resources"`
-	Overlays  []*Overlay     `json:"overlays"`
-	Output    *Output        `json:"output"`
+	Name       string         `json:"-"`
+	Resources  []*ResourceSet `json:"resources"`
+	Overlays   []*Overlay     `json:"overlays"`
+	Output     *Output        `json:"output"`
+	Document   *Document      `json:"document,omitempty"`
+	Versioning *Versioning    `json:"versioning,omitempty"`
+
+	// VersionScheme is the version scheme of the project, if one is
+	// configured.
+	VersionScheme vervet.VersionScheme `json:"-"`
 }
 
 // A ResourceSet defines a set of versioned resources that adhere to the same
@@ -47,6 +55,10 @@ type ResourceSet struct {
 	Description string   `json:"description"`
 	Path        string   `json:"path"`
 	Excludes    []string `json:"excludes"`
+
+	// VersionScheme is the version scheme of the project, if one is
+	// configured. Resource version directories are named in this scheme.
+	VersionScheme vervet.VersionScheme `json:"-"`
 }
 
 func (r *ResourceSet) validate() error {
@@ -114,6 +126,14 @@ func (a APIs) init() error {
 				return fmt.Errorf("%w (apis.%s.resources[%d])", err, api.Name, rcIndex)
 			}
 		}
+		if api.Document != nil {
+			if err := api.Document.validate(); err != nil {
+				return fmt.Errorf("%w (apis.%s.document)", err, api.Name)
+			}
+		}
+		if _, err := api.VersioningPolicy(); err != nil {
+			return fmt.Errorf("%w (apis.%s.versioning)", err, api.Name)
+		}
 	}
 	return nil
 }
@@ -3,9 +3,11 @@ package config_test
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	qt "github.com/frankban/quicktest"
 
+	"github.com/snyk/vervet/v8"
 	"github.com/snyk/vervet/v8/config"
 )
 
@@ -93,6 +95,63 @@ apis:
 		err: "failed to unmarshal project configuration: " +
 			"error unmarshaling JSON: " +
 			"output should specify one of 'path' or 'paths', not both",
+	}, {
+		conf: `
+version: "1"
+apis:
+  testapi:
+    resources:
+      - path: resources
+    document:
+      info:
+        version: 1.0.0
+`[1:],
+		err: `invalid info: value of title must be a non-empty string \(apis\.testapi\.document\)`,
+	}, {
+		conf: `
+version: "1"
+apis:
+  testapi:
+    resources:
+      - path: resources
+    versioning:
+      stabilities: [ga, beta]
+`[1:],
+		err: `stabilities must be in order from least to most stable: ga before beta \(apis\.testapi\.versioning\)`,
+	}, {
+		conf: `
+version: "1"
+apis:
+  testapi:
+    resources:
+      - path: resources
+    versioning:
+      sunsetDays:
+        stable: 90
+`[1:],
+		err: `invalid sunsetDays: .* \(apis\.testapi\.versioning\)`,
+	}, {
+		conf: `
+version: "1"
+versionScheme: calver
+apis:
+  testapi:
+    resources:
+      - path: resources
+`[1:],
+		err: `invalid version scheme "calver" \(versionScheme\)`,
+	}, {
+		conf: `
+version: "1"
+apis:
+  testapi:
+    resources:
+      - path: resources
+    versioning:
+      aliases:
+        latest: "2024-06-01"
+`[1:],
+		err: `alias "latest" is reserved \(apis\.testapi\.versioning\)`,
 	}, {
 		err: `no apis defined`,
 	}}
@@ -102,3 +161,171 @@ apis:
 		c.Assert(err, qt.ErrorMatches, tests[i].err)
 	}
 }
+
+func TestGeneratorsOrder(t *testing.T) {
+	c := qt.New(t)
+	gens, err := config.LoadGenerators(bytes.NewBufferString(`
+docs:
+  template: docs.tmpl
+  filename: docs.md
+  dependsOn: [server, client]
+server:
+  template: server.tmpl
+  filename: server.go
+client:
+  template: client.tmpl
+  filename: client.go
+  dependsOn: [server]
+`))
+	c.Assert(err, qt.IsNil)
+	order, err := gens.Order()
+	c.Assert(err, qt.IsNil)
+	c.Assert(order, qt.DeepEquals, []string{"server", "client", "docs"})
+
+	_, err = config.LoadGenerators(bytes.NewBufferString(`
+client:
+  template: client.tmpl
+  filename: client.go
+  dependsOn: [server]
+`))
+	c.Assert(err, qt.ErrorMatches, `unknown generator "server" \(generators.client.dependsOn\)`)
+
+	_, err = config.LoadGenerators(bytes.NewBufferString(`
+client:
+  template: client.tmpl
+  filename: client.go
+  dependsOn: [server]
+server:
+  template: server.tmpl
+  filename: server.go
+  dependsOn: [client]
+`))
+	c.Assert(err, qt.ErrorMatches, `dependency cycle client -> server -> client \(generators.client.dependsOn\)`)
+}
+
+func TestLoadDocument(t *testing.T) {
+	c := qt.New(t)
+	conf := bytes.NewBufferString(`
+version: "1"
+apis:
+  test:
+    resources:
+      - path: testdata/resources
+    document:
+      openapi: 3.0.3
+      info:
+        title: Things API
+        version: 3.0.0
+        contact:
+          name: Things team
+          email: things@example.com
+        license:
+          name: Apache-2.0
+      servers:
+        - url: https://api.example.com/things
+      securitySchemes:
+        BearerAuth:
+          type: http
+          scheme: bearer
+      security:
+        - BearerAuth: []
+      tags:
+        - name: Things
+          description: Things and stuff
+`)
+	proj, err := config.Load(conf)
+	c.Assert(err, qt.IsNil)
+	doc := proj.APIs["test"].Document
+	c.Assert(doc.OpenAPI, qt.Equals, "3.0.3")
+	c.Assert(doc.Info.Title, qt.Equals, "Things API")
+	c.Assert(doc.Info.Contact.Email, qt.Equals, "things@example.com")
+	c.Assert(doc.Info.License.Name, qt.Equals, "Apache-2.0")
+	c.Assert(doc.Servers[0].URL, qt.Equals, "https://api.example.com/things")
+	c.Assert(doc.SecuritySchemes["BearerAuth"].Value.Scheme, qt.Equals, "bearer")
+	c.Assert(doc.Security, qt.HasLen, 1)
+	c.Assert(doc.Tags[0].Name, qt.Equals, "Things")
+}
+
+func TestLoadVersioning(t *testing.T) {
+	c := qt.New(t)
+	conf := bytes.NewBufferString(`
+version: "1"
+apis:
+  default:
+    resources:
+      - path: testdata/resources
+  partner:
+    resources:
+      - path: testdata/resources
+    versioning:
+      pivotVersion: "2025-01-01"
+      stabilities: [beta, ga]
+      experimentalTTLDays: 30
+      sunsetDays:
+        beta: 30
+        ga: 365
+      aliases:
+        sdk-v3: "2024-06-01"
+`)
+	proj, err := config.Load(conf)
+	c.Assert(err, qt.IsNil)
+
+	policy, err := proj.APIs["default"].VersioningPolicy()
+	c.Assert(err, qt.IsNil)
+	c.Assert(policy, qt.DeepEquals, vervet.DefaultVersioningPolicy())
+
+	policy, err = proj.APIs["partner"].VersioningPolicy()
+	c.Assert(err, qt.IsNil)
+	c.Assert(policy.PivotDate, qt.Equals, vervet.MustParseVersion("2025-01-01"))
+	c.Assert(policy.Stabilities, qt.DeepEquals, []vervet.Stability{vervet.StabilityBeta, vervet.StabilityGA})
+	c.Assert(policy.ExperimentalTTL, qt.Equals, 30*24*time.Hour)
+	c.Assert(policy.SunsetBeta, qt.Equals, 30*24*time.Hour)
+	c.Assert(policy.SunsetGA, qt.Equals, 365*24*time.Hour)
+	c.Assert(policy.SunsetExperimental, qt.Equals, vervet.SunsetExperimental)
+	c.Assert(policy.Aliases, qt.DeepEquals, map[string]vervet.Version{
+		"sdk-v3": vervet.MustParseVersion("2024-06-01"),
+	})
+}
+
+func TestPivotVersion(t *testing.T) {
+	c := qt.New(t)
+	given := vervet.MustParseVersion("2024-06-01")
+
+	api := &config.API{Name: "default"}
+	pivot, err := api.PivotVersion(given)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pivot, qt.Equals, given)
+
+	// The configured pivot version takes precedence over the default.
+	api = &config.API{Name: "partner", Versioning: &config.Versioning{PivotVersion: "2025-01-01"}}
+	pivot, err = api.PivotVersion(vervet.DefaultPivotDate)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pivot, qt.Equals, vervet.MustParseVersion("2025-01-01"))
+	pivot, err = api.PivotVersion(vervet.MustParseVersion("2025-01-01"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(pivot, qt.Equals, vervet.MustParseVersion("2025-01-01"))
+
+	_, err = api.PivotVersion(given)
+	c.Assert(err, qt.ErrorMatches,
+		"pivot version 2024-06-01 conflicts with pivotVersion 2025-01-01 configured for API partner")
+}
+
+func TestLoadVersionScheme(t *testing.T) {
+	c := qt.New(t)
+	conf := bytes.NewBufferString(`
+version: "1"
+versionScheme: semver
+apis:
+  test:
+    resources:
+      - path: testdata/resources
+`)
+	proj, err := config.Load(conf)
+	c.Assert(err, qt.IsNil)
+	api := proj.APIs["test"]
+	c.Assert(api.VersionScheme, qt.Equals, vervet.SemverScheme)
+	c.Assert(api.Resources[0].VersionScheme, qt.Equals, vervet.SemverScheme)
+	policy, err := api.VersioningPolicy()
+	c.Assert(err, qt.IsNil)
+	c.Assert(policy.Scheme, qt.Equals, vervet.SemverScheme)
+}
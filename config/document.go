@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// A Document defines the top-level metadata of the OpenAPI documents built
+// for an API. Fields follow the structure of the OpenAPI document object.
+type Document struct {
+	// OpenAPI is the OpenAPI specification version of the documents.
+	OpenAPI string `json:"openapi,omitempty"`
+
+	// Info describes the API: its title, description, contact, license and
+	// version.
+	Info *openapi3.Info `json:"info,omitempty"`
+
+	// Servers are the servers the API is available at. If not specified,
+	// servers are taken from the first resource spec of the API.
+	Servers openapi3.Servers `json:"servers,omitempty"`
+
+	// SecuritySchemes are the security schemes which may be used to
+	// authenticate with the API.
+	SecuritySchemes openapi3.SecuritySchemes `json:"securitySchemes,omitempty"`
+
+	// Security are the security requirements which apply to all operations.
+	Security openapi3.SecurityRequirements `json:"security,omitempty"`
+
+	// Tags are the tags used to group operations.
+	Tags openapi3.Tags `json:"tags,omitempty"`
+}
+
+func (d *Document) validate() error {
+	ctx := context.Background()
+	if d.Info != nil {
+		if err := d.Info.Validate(ctx); err != nil {
+			return fmt.Errorf("invalid info: %w", err)
+		}
+	}
+	if err := d.Servers.Validate(ctx); err != nil {
+		return fmt.Errorf("invalid servers: %w", err)
+	}
+	for name, scheme := range d.SecuritySchemes {
+		if scheme == nil || scheme.Value == nil {
+			return fmt.Errorf("security scheme %q is empty", name)
+		}
+		if err := scheme.Value.Validate(ctx); err != nil {
+			return fmt.Errorf("invalid security scheme %q: %w", name, err)
+		}
+	}
+	if err := d.Tags.Validate(ctx); err != nil {
+		return fmt.Errorf("invalid tags: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // Generators defines a named map of Generator instances.
@@ -15,15 +17,35 @@ type Generator struct {
 	Template  string         `json:"template"`
 	Files     string         `json:"files,omitempty"`
 	Functions string         `json:"functions,omitempty"`
+
+	// Builtin selects a generator built into vervet, rather than a template.
+	Builtin string `json:"builtin,omitempty"`
+
+	// DependsOn names the generators which must run before this one, such
+	// as those generating files its templates read.
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
+// GeneratorBuiltinGoServer is the built-in generator of Go server code for
+// each resource version: typed request and response structs, a handler
+// interface and the versionware.VersionHandler of each operation.
+const GeneratorBuiltinGoServer = "go-server"
+
+// defaultGoServerFilename is the default filename of code generated by the
+// go-server built-in generator, in each resource version directory.
+const defaultGoServerFilename = "{{ .Path }}/server_gen.go"
+
 func (g *Generator) validate() error {
 	switch g.Scope {
 	case GeneratorScopeVersion:
 	case GeneratorScopeResource:
+	case GeneratorScopeAPI:
 	default:
 		return fmt.Errorf("invalid scope %q (generators.%s.scope)", g.Scope, g.Name)
 	}
+	if g.Builtin != "" {
+		return g.validateBuiltin()
+	}
 	if g.Template == "" {
 		return fmt.Errorf("required field not specified (generators.%s.contents)", g.Name)
 	}
@@ -33,6 +55,20 @@ func (g *Generator) validate() error {
 	return nil
 }
 
+func (g *Generator) validateBuiltin() error {
+	if g.Builtin != GeneratorBuiltinGoServer {
+		return fmt.Errorf("unknown builtin generator %q (generators.%s.builtin)", g.Builtin, g.Name)
+	}
+	if g.Scope != GeneratorScopeVersion {
+		return fmt.Errorf("builtin generator %q requires scope %q (generators.%s.scope)",
+			g.Builtin, GeneratorScopeVersion, g.Name)
+	}
+	if g.Template != "" || g.Files != "" {
+		return fmt.Errorf("builtin generators do not use a template or files (generators.%s)", g.Name)
+	}
+	return nil
+}
+
 // GeneratorScope determines the template context when running the generator.
 // Different scopes allow templates to operate over a single resource version,
 // or all versions in a resource, for example.
@@ -51,6 +87,11 @@ const (
 	// in a resource. This is useful for generating version routers, for
 	// example.
 	GeneratorScopeResource = "resource"
+
+	// GeneratorScopeAPI indicates the generator operates on each compiled
+	// output version of an API. This is useful for generating documentation
+	// and SDKs from what customers actually see, for example.
+	GeneratorScopeAPI = "api"
 )
 
 func (g Generators) init() error {
@@ -59,9 +100,55 @@ func (g Generators) init() error {
 		if gen.Scope == GeneratorScopeDefault {
 			gen.Scope = GeneratorScopeVersion
 		}
+		if gen.Builtin == GeneratorBuiltinGoServer && gen.Filename == "" {
+			gen.Filename = defaultGoServerFilename
+		}
 		if err := gen.validate(); err != nil {
 			return err
 		}
 	}
-	return nil
+	_, err := g.Order()
+	return err
+}
+
+// Order returns the names of the generators in the order they should run:
+// each generator follows those it depends on, and is otherwise ordered by
+// name.
+func (g Generators) Order() ([]string, error) {
+	names := make([]string, 0, len(g))
+	for name := range g {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var order []string
+	done := map[string]bool{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if done[name] {
+			return nil
+		}
+		for i := range path {
+			if path[i] == name {
+				return fmt.Errorf("dependency cycle %s (generators.%s.dependsOn)",
+					strings.Join(append(path[i:], name), " -> "), name)
+			}
+		}
+		for _, dep := range g[name].DependsOn {
+			if _, ok := g[dep]; !ok {
+				return fmt.Errorf("unknown generator %q (generators.%s.dependsOn)", dep, name)
+			}
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		done[name] = true
+		order = append(order, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
 }
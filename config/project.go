@@ -6,6 +6,8 @@ import (
 	"sort"
 
 	"github.com/ghodss/yaml"
+
+	"github.com/snyk/vervet/v8"
 )
 
 // Project defines collection of APIs and the standards they adhere to.
@@ -13,6 +15,11 @@ type Project struct {
 	Version    string     `json:"version"`
 	Generators Generators `json:"generators,omitempty"`
 	APIs       APIs       `json:"apis"`
+
+	// VersionScheme is the scheme in which the versions of all APIs in the
+	// project are written: "date" (the default), "date-sequence" or
+	// "semver".
+	VersionScheme string `json:"versionScheme,omitempty"`
 }
 
 // APINames returns the API names in deterministic ascending order.
@@ -45,6 +52,23 @@ func (p *Project) validate() error {
 	if err != nil {
 		return err
 	}
+	if p.VersionScheme != "" {
+		scheme, err := vervet.ParseVersionScheme(p.VersionScheme)
+		if err != nil {
+			return fmt.Errorf("%w (versionScheme)", err)
+		}
+		for _, api := range p.APIs {
+			if api == nil {
+				continue
+			}
+			api.VersionScheme = scheme
+			for _, rc := range api.Resources {
+				if rc != nil {
+					rc.VersionScheme = scheme
+				}
+			}
+		}
+	}
 	err = p.APIs.init()
 	if err != nil {
 		return err
@@ -25,6 +25,15 @@ type ServerConfig struct {
 	Services []ServiceConfig
 	Storage  StorageConfig
 	Merging  MergeConfig
+
+	// VersionScheme is the scheme in which versions are requested and
+	// aliases are pinned, such as "date-sequence". Default is the date
+	// scheme.
+	VersionScheme string
+
+	// Aliases are named versions which may be requested, such as "sdk-v3"
+	// for "2024-06-01".
+	Aliases map[string]string
 }
 
 // ServiceFilter provides a map of service names to quickly filter old services.
@@ -47,9 +56,42 @@ func (c *ServerConfig) validate() error {
 		}
 		serviceNames[svc.Name] = struct{}{}
 	}
+	if _, err := c.VersioningPolicy(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// VersioningPolicy returns the versioning policy with which requested
+// versions are parsed, in the configured version scheme and with the
+// configured aliases.
+func (c *ServerConfig) VersioningPolicy() (*vervet.VersioningPolicy, error) {
+	policy := vervet.DefaultVersioningPolicy()
+	if c.VersionScheme != "" {
+		scheme, err := vervet.ParseVersionScheme(c.VersionScheme)
+		if err != nil {
+			return nil, fmt.Errorf("%w (versionScheme)", err)
+		}
+		policy.Scheme = scheme
+	}
+	aliases, err := vervet.ParseVersionAliases(policy.Scheme, c.Aliases)
+	if err != nil {
+		return nil, err
+	}
+	policy.Aliases = aliases
+	return policy, nil
+}
+
+// VersionAliases returns the versions pinned by the configured aliases, in
+// the configured version scheme.
+func (c *ServerConfig) VersionAliases() (map[string]vervet.Version, error) {
+	policy, err := c.VersioningPolicy()
+	if err != nil {
+		return nil, err
+	}
+	return policy.Aliases, nil
+}
+
 // ServiceConfig defines configuration options on a service.
 type ServiceConfig struct {
 	Name string
@@ -212,6 +212,50 @@ func TestLoadServerConfig(t *testing.T) {
 		c.Assert(*conf, qt.DeepEquals, expected)
 	})
 
+	c.Run("aliases", func(c *qt.C) {
+		f := createTestFile(c, []byte(`{
+			"aliases": {"sdk-v3": "2024-06-01"}
+		}`))
+
+		conf, err := config.LoadServerConfig(f.Name())
+		c.Assert(err, qt.IsNil)
+		aliases, err := conf.VersionAliases()
+		c.Assert(err, qt.IsNil)
+		c.Assert(aliases, qt.DeepEquals, map[string]vervet.Version{
+			"sdk-v3": vervet.MustParseVersion("2024-06-01"),
+		})
+	})
+
+	c.Run("aliases in version scheme", func(c *qt.C) {
+		f := createTestFile(c, []byte(`{
+			"versionScheme": "date-sequence",
+			"aliases": {"sdk-v3": "2024-06-01.2"}
+		}`))
+
+		conf, err := config.LoadServerConfig(f.Name())
+		c.Assert(err, qt.IsNil)
+		policy, err := conf.VersioningPolicy()
+		c.Assert(err, qt.IsNil)
+		c.Assert(policy.Scheme, qt.Equals, vervet.DateSequenceScheme)
+		c.Assert(policy.Aliases["sdk-v3"].String(), qt.Equals, "2024-06-01.2")
+	})
+
+	c.Run("invalid version scheme", func(c *qt.C) {
+		f := createTestFile(c, []byte(`{
+			"versionScheme": "calver"
+		}`))
+		_, err := config.LoadServerConfig(f.Name())
+		c.Assert(err, qt.ErrorMatches, `invalid version scheme "calver" \(versionScheme\)`)
+	})
+
+	c.Run("invalid aliases", func(c *qt.C) {
+		f := createTestFile(c, []byte(`{
+			"aliases": {"sdk-v3": "v3"}
+		}`))
+		_, err := config.LoadServerConfig(f.Name())
+		c.Assert(err, qt.ErrorMatches, `invalid version for alias "sdk-v3": .*`)
+	})
+
 	c.Run("invalid service config - no name", func(c *qt.C) {
 		cfg := createTestFile(c, []byte(`{
 			"host": "0.0.0.0",
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/snyk/vervet/v8"
+)
+
+// Versioning defines the versioning policy of an API: which stabilities its
+// versions may have, when deprecated versions may be sunset, and the pivot
+// version on which simplified versioning begins. Anything not specified
+// defaults to vervet.DefaultVersioningPolicy.
+type Versioning struct {
+	// PivotVersion is the version on and after which simplified versioning
+	// is used, such as "2024-10-15".
+	PivotVersion string `json:"pivotVersion,omitempty"`
+
+	// Stabilities is the stability ladder of the API, from least to most
+	// stable, such as [wip, experimental, beta, ga].
+	Stabilities []string `json:"stabilities,omitempty"`
+
+	// ExperimentalTTLDays is the number of days after which experimental
+	// versions are considered sunset.
+	ExperimentalTTLDays *int `json:"experimentalTTLDays,omitempty"`
+
+	// SunsetDays is the number of days after deprecation that a version may
+	// be sunset, by stability.
+	SunsetDays map[string]int `json:"sunsetDays,omitempty"`
+
+	// Aliases are named versions which API clients may request, such as
+	// "sdk-v3" for "2024-06-01".
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+const day = 24 * time.Hour
+
+// PivotVersion returns the version on and after which simplified versioning
+// is used for the API: the pivot version configured for the API if there is
+// one, otherwise the given version, such as one given on the command line.
+//
+// The configured pivot version takes precedence over the default pivot
+// version. It is an error to give any other version when the API configures
+// a different one, as it would be ambiguous which applies.
+func (a *API) PivotVersion(given vervet.Version) (vervet.Version, error) {
+	if a.Versioning == nil || a.Versioning.PivotVersion == "" {
+		return given, nil
+	}
+	pivot, err := vervet.ParseVersion(a.Versioning.PivotVersion)
+	if err != nil {
+		return vervet.Version{}, fmt.Errorf("invalid pivotVersion: %w", err)
+	}
+	if given.Compare(vervet.DefaultPivotDate) != 0 && given.Compare(pivot) != 0 {
+		return vervet.Version{}, fmt.Errorf("pivot version %s conflicts with pivotVersion %s configured for API %s",
+			given, pivot, a.Name)
+	}
+	return pivot, nil
+}
+
+// VersioningPolicy returns the versioning policy of the API, including the
+// version scheme of its project.
+func (a *API) VersioningPolicy() (*vervet.VersioningPolicy, error) {
+	policy := vervet.DefaultVersioningPolicy()
+	if a.VersionScheme != nil {
+		policy.Scheme = a.VersionScheme
+	}
+	v := a.Versioning
+	if v == nil {
+		return policy, nil
+	}
+	if v.PivotVersion != "" {
+		pivot, err := vervet.ParseVersion(v.PivotVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pivotVersion: %w", err)
+		}
+		policy.PivotDate = pivot
+	}
+	if len(v.Stabilities) > 0 {
+		policy.Stabilities = make([]vervet.Stability, len(v.Stabilities))
+		for i := range v.Stabilities {
+			stability, err := vervet.ParseStability(v.Stabilities[i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid stabilities: %w", err)
+			}
+			policy.Stabilities[i] = stability
+		}
+	}
+	if v.ExperimentalTTLDays != nil {
+		policy.ExperimentalTTL = time.Duration(*v.ExperimentalTTLDays) * day
+	}
+	for s, days := range v.SunsetDays {
+		stability, err := vervet.ParseStability(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sunsetDays: %w", err)
+		}
+		period := time.Duration(days) * day
+		switch stability {
+		case vervet.StabilityWIP:
+			policy.SunsetWIP = period
+		case vervet.StabilityExperimental:
+			policy.SunsetExperimental = period
+		case vervet.StabilityBeta:
+			policy.SunsetBeta = period
+		case vervet.StabilityGA:
+			policy.SunsetGA = period
+		}
+	}
+	if len(v.Aliases) > 0 {
+		aliases, err := vervet.ParseVersionAliases(policy.Scheme, v.Aliases)
+		if err != nil {
+			return nil, err
+		}
+		policy.Aliases = aliases
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
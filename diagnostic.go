@@ -0,0 +1,425 @@
+package vervet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// DiagnosticError is an error located in a source file, so that it may be
+// reported where the problem was found.
+type DiagnosticError struct {
+	// File is the path of the source file.
+	File string `json:"file,omitempty"`
+
+	// Line is the 1-based line number in the source file, or 0 if unknown.
+	Line int `json:"line,omitempty"`
+
+	// Column is the 1-based column number in the source file, or 0 if
+	// unknown.
+	Column int `json:"column,omitempty"`
+
+	// Pointer is a JSON pointer (RFC 6901) to the element in the source file,
+	// such as "/paths/~1things/get".
+	Pointer string `json:"pointer,omitempty"`
+
+	// Err is the error found. It is serialized in JSON as the "error" string.
+	Err error `json:"-"`
+}
+
+// diagnosticJSON is the JSON representation of a DiagnosticError.
+type diagnosticJSON struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Pointer string `json:"pointer,omitempty"`
+	Error   string `json:"error"`
+}
+
+// MarshalJSON implements json.Marshaler, serializing the error found as its
+// message.
+func (d *DiagnosticError) MarshalJSON() ([]byte, error) {
+	v := diagnosticJSON{File: d.File, Line: d.Line, Column: d.Column, Pointer: d.Pointer}
+	if d.Err != nil {
+		v.Error = d.Err.Error()
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The error found is restored as
+// an error with the serialized message.
+func (d *DiagnosticError) UnmarshalJSON(buf []byte) error {
+	var v diagnosticJSON
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return err
+	}
+	*d = DiagnosticError{File: v.File, Line: v.Line, Column: v.Column, Pointer: v.Pointer, Err: errors.New(v.Error)}
+	return nil
+}
+
+// Error implements error.
+func (d *DiagnosticError) Error() string {
+	if loc := d.Location(); loc != "" {
+		return loc + ": " + d.Err.Error()
+	}
+	return d.Err.Error()
+}
+
+// Unwrap returns the error found.
+func (d *DiagnosticError) Unwrap() error {
+	return d.Err
+}
+
+// Location returns the location of the diagnostic as "file:line:column",
+// falling back to "file#pointer" if the line is not known.
+func (d *DiagnosticError) Location() string {
+	switch {
+	case d.File == "":
+		return ""
+	case d.Line > 0 && d.Column > 0:
+		return d.File + ":" + strconv.Itoa(d.Line) + ":" + strconv.Itoa(d.Column)
+	case d.Line > 0:
+		return d.File + ":" + strconv.Itoa(d.Line)
+	case d.Pointer != "":
+		return d.File + "#" + d.Pointer
+	}
+	return d.File
+}
+
+// Diagnostic returns a diagnostic locating err at the element of the
+// document's source file addressed by the given JSON pointer reference
+// tokens. With no tokens, the diagnostic refers to the file as a whole.
+//
+// The line and column are determined by reading the source file again, so
+// that documents need not retain their contents once loaded.
+func (d *Document) Diagnostic(err error, tokens ...string) *DiagnosticError {
+	diag := &DiagnosticError{File: d.path, Pointer: JSONPointer(tokens...), Err: err}
+	if len(tokens) == 0 || d.url == nil {
+		return diag
+	}
+	l := d.loader()
+	contents, readErr := l.ReadFromURIFunc(l, d.url)
+	if readErr == nil {
+		diag.Line, diag.Column = locate(contents, tokens)
+	}
+	return diag
+}
+
+// JSONPointer returns the JSON pointer (RFC 6901) made of the given
+// reference tokens.
+func JSONPointer(tokens ...string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(jsonPointerEscaper.Replace(token))
+	}
+	return sb.String()
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// locate returns the line and column of the element addressed by the given
+// JSON pointer reference tokens in a YAML or JSON document. If the element is
+// not found, the location of its nearest ancestor is returned.
+func locate(contents []byte, tokens []string) (line, column int) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(contents, &root); err != nil || len(root.Content) == 0 {
+		return 0, 0
+	}
+	node := root.Content[0]
+	line, column = node.Line, node.Column
+	for _, token := range tokens {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					// Locate the key rather than its value, which may be on
+					// the following line.
+					line, column = node.Content[i].Line, node.Content[i].Column
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if index, err := strconv.Atoi(token); err == nil && index >= 0 && index < len(node.Content) {
+				next = node.Content[index]
+				line, column = next.Line, next.Column
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return line, column
+}
+
+// unresolvedRef returns the JSON pointer reference tokens of the first $ref in
+// a document's contents which cannot be resolved, either because the element
+// it refers to is not found or because a reference within that element cannot
+// be resolved in turn. Returns nil if every reference is resolved.
+func (d *Document) unresolvedRef(contents []byte) []string {
+	var root yaml.Node
+	if err := yaml.Unmarshal(contents, &root); err != nil || len(root.Content) == 0 || d.url == nil {
+		return nil
+	}
+	rc := &refChecker{
+		loader:   d.loader(),
+		docs:     map[string]*yaml.Node{d.url.String(): root.Content[0]},
+		resolved: map[string]bool{},
+	}
+	var unresolved []string
+	walkRefs(root.Content[0], nil, func(tokens []string, ref string) bool {
+		if rc.resolves(d.url, ref) {
+			return true
+		}
+		unresolved = tokens
+		return false
+	})
+	return unresolved
+}
+
+// refChecker checks whether references can be resolved, reading each
+// referenced document once.
+type refChecker struct {
+	loader   *openapi3.Loader
+	docs     map[string]*yaml.Node
+	resolved map[string]bool
+}
+
+// resolves returns whether a reference in the document at base can be
+// resolved, along with all the references in the element it refers to.
+func (rc *refChecker) resolves(base *url.URL, ref string) bool {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return false
+	}
+	target := *base
+	if refURL.Scheme != "" || refURL.Path != "" {
+		target = *base.ResolveReference(&url.URL{Scheme: refURL.Scheme, Host: refURL.Host, Path: refURL.Path})
+	}
+	target.Fragment = ""
+	key := target.String() + "#" + refURL.Fragment
+	if ok, checked := rc.resolved[key]; checked {
+		return ok
+	}
+	// A reference is assumed to resolve while it is checked, so that cyclic
+	// references are checked once.
+	rc.resolved[key] = true
+	node := rc.doc(&target)
+	if node != nil && refURL.Fragment != "" {
+		node = lookup(node, strings.Split(strings.TrimPrefix(refURL.Fragment, "/"), "/"))
+	}
+	ok := node != nil && walkRefs(node, nil, func(_ []string, ref string) bool {
+		return rc.resolves(&target, ref)
+	})
+	rc.resolved[key] = ok
+	return ok
+}
+
+// doc returns the root node of the document at a location, or nil if it
+// cannot be read.
+func (rc *refChecker) doc(location *url.URL) *yaml.Node {
+	if node, ok := rc.docs[location.String()]; ok {
+		return node
+	}
+	var node *yaml.Node
+	contents, err := rc.loader.ReadFromURIFunc(rc.loader, location)
+	if err == nil {
+		var root yaml.Node
+		if err := yaml.Unmarshal(contents, &root); err == nil && len(root.Content) > 0 {
+			node = root.Content[0]
+		}
+	}
+	rc.docs[location.String()] = node
+	return node
+}
+
+// walkRefs calls fn with each $ref found within node, in document order,
+// along with the JSON pointer reference tokens of the $ref relative to node.
+// Walking stops if fn returns false, in which case false is returned.
+func walkRefs(node *yaml.Node, tokens []string, fn func(tokens []string, ref string) bool) bool {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return walkRefs(node.Alias, tokens, fn)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			keyTokens := append(slices.Clip(tokens), key)
+			if key == "$ref" && value.Kind == yaml.ScalarNode {
+				if !fn(keyTokens, value.Value) {
+					return false
+				}
+			} else if !walkRefs(value, keyTokens, fn) {
+				return false
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			if !walkRefs(item, append(slices.Clip(tokens), strconv.Itoa(i)), fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+var jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// lookup returns the node addressed by escaped JSON pointer reference tokens
+// within node, or nil if there is none.
+func lookup(node *yaml.Node, tokens []string) *yaml.Node {
+	for _, token := range tokens {
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		token = jsonPointerUnescaper.Replace(token)
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if index, err := strconv.Atoi(token); err == nil && index >= 0 && index < len(node.Content) {
+				next = node.Content[index]
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+var yamlErrorLineRE = regexp.MustCompile(`yaml: line (\d+):`)
+
+// yamlDiagnostic returns a diagnostic for an error parsing a YAML file,
+// located at the line reported by the parser if any.
+func yamlDiagnostic(file string, err error) *DiagnosticError {
+	diag := &DiagnosticError{File: file, Err: err}
+	if m := yamlErrorLineRE.FindStringSubmatch(err.Error()); m != nil {
+		diag.Line, _ = strconv.Atoi(m[1])
+	}
+	return diag
+}
+
+// DiagnosticErrors is a collection of located errors, such as all the
+// problems found while loading or collating specs.
+type DiagnosticErrors []*DiagnosticError
+
+// Error implements error.
+func (ds DiagnosticErrors) Error() string {
+	msgs := make([]string, len(ds))
+	for i := range ds {
+		msgs[i] = ds[i].Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors found.
+func (ds DiagnosticErrors) Unwrap() []error {
+	errs := make([]error, len(ds))
+	for i := range ds {
+		errs[i] = ds[i]
+	}
+	return errs
+}
+
+// AsDiagnostics returns all the diagnostics in err, which may combine several
+// errors with multierr or errors.Join. Errors which are not located are
+// returned as diagnostics without a file. Returns nil if err is nil.
+func AsDiagnostics(err error) DiagnosticErrors {
+	if err == nil {
+		return nil
+	}
+	ds, found := collectDiagnostics(err)
+	if !found {
+		return DiagnosticErrors{{Err: err}}
+	}
+	return ds
+}
+
+// collectDiagnostics returns the diagnostics in the tree of errors wrapped by
+// err, and whether any were found. Branches of the tree without any
+// diagnostic are returned as unlocated diagnostics.
+func collectDiagnostics(err error) (DiagnosticErrors, bool) {
+	switch e := err.(type) {
+	case *DiagnosticError:
+		return DiagnosticErrors{e}, true
+	case interface{ Unwrap() []error }:
+		var ds DiagnosticErrors
+		found := false
+		for _, branch := range e.Unwrap() {
+			branchDiags, branchFound := collectDiagnostics(branch)
+			ds = append(ds, branchDiags...)
+			found = found || branchFound
+		}
+		return ds, found
+	case interface{ Unwrap() error }:
+		if ds, found := collectDiagnostics(e.Unwrap()); found {
+			return ds, true
+		}
+	}
+	return DiagnosticErrors{{Err: err}}, false
+}
+
+// WriteGitHubActions writes the diagnostics as GitHub Actions workflow
+// commands, which annotate the lines of source files where errors were found
+// in pull requests. Paths are made relative to the current working directory,
+// which is expected to be the root of the repository.
+func (ds DiagnosticErrors) WriteGitHubActions(w io.Writer) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	for _, d := range ds {
+		var props []string
+		if d.File != "" {
+			file := d.File
+			if rel, err := filepath.Rel(cwd, file); err == nil && filepath.IsAbs(file) {
+				file = rel
+			}
+			props = append(props, "file="+githubPropertyEscaper.Replace(filepath.ToSlash(file)))
+			if d.Line > 0 {
+				props = append(props, "line="+strconv.Itoa(d.Line))
+			}
+			if d.Column > 0 {
+				props = append(props, "col="+strconv.Itoa(d.Column))
+			}
+		}
+		if d.Pointer != "" {
+			props = append(props, "title="+githubPropertyEscaper.Replace(d.Pointer))
+		}
+		cmd := "::error"
+		if len(props) > 0 {
+			cmd += " " + strings.Join(props, ",")
+		}
+		_, err := fmt.Fprintf(w, "%s::%s\n", cmd, githubDataEscaper.Replace(d.Err.Error()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	githubDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
@@ -0,0 +1,158 @@
+package vervet_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+	"go.uber.org/multierr"
+
+	"github.com/snyk/vervet/v8"
+)
+
+func TestDocumentDiagnostic(t *testing.T) {
+	c := qt.New(t)
+	doc, err := vervet.NewDocumentFS(relativeRefsFS, "resources/things/2024-01-01/spec.yaml")
+	c.Assert(err, qt.IsNil)
+
+	diag := doc.Diagnostic(errors.New("bad thing"), "paths", "/things/{id}", "get", "parameters", "0")
+	c.Assert(diag.File, qt.Equals, "resources/things/2024-01-01/spec.yaml")
+	c.Assert(diag.Pointer, qt.Equals, "/paths/~1things~1{id}/get/parameters/0")
+	c.Assert(diag.Line, qt.Equals, 8)
+	c.Assert(diag.Column, qt.Equals, 11)
+	c.Assert(diag, qt.ErrorMatches, `resources/things/2024-01-01/spec.yaml:8:11: bad thing`)
+
+	// Missing elements are located at their nearest ancestor.
+	diag = doc.Diagnostic(errors.New("no post"), "paths", "/things/{id}", "post")
+	c.Assert(diag.Line, qt.Equals, 5)
+	c.Assert(diag.Column, qt.Equals, 3)
+
+	diag = doc.Diagnostic(errors.New("whole file"))
+	c.Assert(diag, qt.ErrorMatches, `resources/things/2024-01-01/spec.yaml: whole file`)
+}
+
+func TestDocumentYAMLDiagnostic(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{"things/2024-01-01/spec.yaml": {Data: []byte("openapi: 3.0.3\ninfo:\n  title: [\n")}}
+	_, err := vervet.NewDocumentFS(fsys, "things/2024-01-01/spec.yaml")
+	var diag *vervet.DiagnosticError
+	c.Assert(errors.As(err, &diag), qt.IsTrue)
+	c.Assert(diag.File, qt.Equals, "things/2024-01-01/spec.yaml")
+	c.Assert(diag.Line, qt.Not(qt.Equals), 0)
+}
+
+func TestAsDiagnostics(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(vervet.AsDiagnostics(nil), qt.HasLen, 0)
+
+	err := errors.New("plain")
+	diags := vervet.AsDiagnostics(err)
+	c.Assert(diags, qt.HasLen, 1)
+	c.Assert(diags[0].File, qt.Equals, "")
+	c.Assert(diags[0].Err, qt.Equals, err)
+
+	located1 := &vervet.DiagnosticError{File: "a.yaml", Line: 1, Err: errors.New("one")}
+	located2 := &vervet.DiagnosticError{File: "b.yaml", Pointer: "/paths", Err: errors.New("two")}
+	unlocated := errors.New("three")
+	err = fmt.Errorf("failed: %w", multierr.Combine(located1, fmt.Errorf("wrapped: %w", located2), unlocated))
+	diags = vervet.AsDiagnostics(err)
+	c.Assert(diags, qt.HasLen, 3)
+	c.Assert(diags[0], qt.Equals, located1)
+	c.Assert(diags[1], qt.Equals, located2)
+	c.Assert(diags[2].File, qt.Equals, "")
+	c.Assert(diags[2].Err, qt.Equals, unlocated)
+	c.Assert(located2, qt.ErrorMatches, `b.yaml#/paths: two`)
+}
+
+func TestWriteGitHubActions(t *testing.T) {
+	c := qt.New(t)
+	var buf bytes.Buffer
+	err := vervet.DiagnosticErrors{{
+		File:    "resources/things/2024-01-01/spec.yaml",
+		Line:    8,
+		Column:  11,
+		Pointer: "/paths/~1things/get",
+		Err:     errors.New("bad thing: 100%\nreally"),
+	}, {
+		Err: errors.New("unlocated"),
+	}}.WriteGitHubActions(&buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(buf.String(), qt.Equals, ""+
+		"::error file=resources/things/2024-01-01/spec.yaml,line=8,col=11,title=/paths/~1things/get"+
+		"::bad thing: 100%25%0Areally\n"+
+		"::error::unlocated\n")
+}
+
+func TestDocumentRefDiagnostic(t *testing.T) {
+	c := qt.New(t)
+	spec := `
+openapi: 3.0.3
+info: {title: things, version: 3.0.0}
+paths:
+  /things:
+    get:
+      responses:
+        '200':
+          description: A thing
+          content:
+            application/json:
+              schema:
+                $ref: '../schemas/things.yaml#/Thing'
+        '400':
+          description: Bad request
+          content:
+            application/json:
+              schema:
+                $ref: '%s'
+components:
+  schemas:
+    Error: {type: object}
+`[1:]
+	tests := []struct {
+		ref string
+	}{
+		{ref: "../schemas/missing.yaml#/Error"},
+		{ref: "../schemas/things.yaml#/Missing"},
+		{ref: "#/components/schemas/Missing"},
+		// A reference within the referenced element cannot be resolved.
+		{ref: "../schemas/errors.yaml#/Error"},
+	}
+	for _, test := range tests {
+		c.Run(test.ref, func(c *qt.C) {
+			fsys := fstest.MapFS{
+				"things/2024-01-01/spec.yaml": {Data: []byte(fmt.Sprintf(spec, test.ref))},
+				"things/schemas/things.yaml":  {Data: []byte("Thing: {type: object}\n")},
+				"things/schemas/errors.yaml":  {Data: []byte("Error: {$ref: 'missing.yaml#/Error'}\n")},
+			}
+			_, err := vervet.NewDocumentFS(fsys, "things/2024-01-01/spec.yaml")
+			var diag *vervet.DiagnosticError
+			c.Assert(errors.As(err, &diag), qt.IsTrue)
+			c.Assert(diag.File, qt.Equals, "things/2024-01-01/spec.yaml")
+			c.Assert(diag.Pointer, qt.Equals,
+				"/paths/~1things/get/responses/400/content/application~1json/schema/$ref")
+			c.Assert(diag.Line, qt.Equals, 18)
+			c.Assert(diag.Column, qt.Equals, 17)
+		})
+	}
+}
+
+func TestDiagnosticJSON(t *testing.T) {
+	c := qt.New(t)
+	diag := &vervet.DiagnosticError{File: "a.yaml", Line: 1, Pointer: "/paths", Err: errors.New("bad thing")}
+	buf, err := json.Marshal(diag)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(buf), qt.JSONEquals, map[string]any{
+		"file":    "a.yaml",
+		"line":    1,
+		"pointer": "/paths",
+		"error":   "bad thing",
+	})
+
+	var decoded vervet.DiagnosticError
+	c.Assert(json.Unmarshal(buf, &decoded), qt.IsNil)
+	c.Assert(&decoded, qt.ErrorMatches, "a.yaml:1: bad thing")
+}
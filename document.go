@@ -3,9 +3,10 @@ package vervet
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"io/fs"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -31,77 +32,103 @@ type Document struct {
 	*openapi3.T
 	path string
 	url  *url.URL
+
+	// fsys is the filesystem the document was loaded from, if it was not
+	// loaded from the local filesystem.
+	fsys fs.FS
 }
 
 // NewDocumentFile loads an OpenAPI spec file from the given file path,
-// returning a document object.
-func NewDocumentFile(specFile string) (_ *Document, returnErr error) {
-	// Restore current working directory upon returning
-	cwd, err := os.Getwd()
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		err := os.Chdir(cwd)
-		if err != nil {
-			log.Println("warning: failed to restore working directory: %w", err)
-			if returnErr == nil {
-				returnErr = err
-			}
-		}
-	}()
-
-	specFile, err = filepath.Abs(specFile)
+// returning a document object. Relative references are resolved against the
+// location of the spec file.
+func NewDocumentFile(specFile string) (*Document, error) {
+	specFile, err := filepath.Abs(specFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
-
-	// `cd` to the path containing the spec file, so that relative paths
-	// resolve.
-	specBase, specDir := filepath.Base(specFile), filepath.Dir(specFile)
-	err = os.Chdir(specDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to chdir %q: %w", specDir, err)
-	}
-
 	specURL, err := url.Parse(specFile)
 	if err != nil {
 		return nil, err
 	}
-
-	var t openapi3.T
-
 	contents, err := os.ReadFile(specFile)
 	if err != nil {
 		return nil, err
 	}
+	return newDocument(contents, specFile, specURL, nil)
+}
 
-	err = yaml.Unmarshal(contents, &t)
+// NewDocumentFS loads an OpenAPI spec file from the given path within fsys,
+// returning a document object. Relative references are resolved against the
+// location of the spec file within fsys; remote references are loaded from
+// their URL.
+//
+// Unlike NewDocumentFile, the location of the document is its path within
+// fsys.
+func NewDocumentFS(fsys fs.FS, specPath string) (*Document, error) {
+	specPath = path.Clean(filepath.ToSlash(specPath))
+	contents, err := fs.ReadFile(fsys, specPath)
 	if err != nil {
 		return nil, err
 	}
+	specURL := &url.URL{Path: "/" + specPath}
+	return newDocument(contents, specPath, specURL, fsys)
+}
+
+// ReadFromFS returns a function for openapi3.Loader.ReadFromURIFunc which
+// reads local references from fsys, where the root of fsys is the root path
+// "/". Remote references are read from their URL.
+func ReadFromFS(fsys fs.FS) openapi3.ReadFromURIFunc {
+	return func(l *openapi3.Loader, location *url.URL) ([]byte, error) {
+		if location.Scheme != "" && location.Scheme != "file" {
+			return openapi3.DefaultReadFromURI(l, location)
+		}
+		return fs.ReadFile(fsys, strings.TrimPrefix(path.Clean(location.Path), "/"))
+	}
+}
+
+// newDocument loads an OpenAPI document from its contents, resolving
+// references relative to specURL, within fsys if not nil.
+func newDocument(contents []byte, specPath string, specURL *url.URL, fsys fs.FS) (*Document, error) {
+	var t openapi3.T
+	err := yaml.Unmarshal(contents, &t)
+	if err != nil {
+		return nil, yamlDiagnostic(specPath, err)
+	}
 	err = newRefAliasResolver(&t).resolve()
 	if err != nil {
 		return nil, err
 	}
 
-	l := openapi3.NewLoader()
-	l.IsExternalRefsAllowed = true
-
-	err = l.ResolveRefsIn(&t, specURL)
+	doc := &Document{
+		T:    &t,
+		path: specPath,
+		url:  specURL,
+		fsys: fsys,
+	}
+	err = doc.ResolveRefs()
 	if err != nil {
-		return nil, fmt.Errorf("failed to load %q: %w", specBase, err)
+		// Locate the reference which could not be resolved, if it can be
+		// found.
+		return nil, doc.Diagnostic(fmt.Errorf("failed to load %q: %w", path.Base(specURL.Path), err),
+			doc.unresolvedRef(contents)...)
 	}
 
 	if t.Components == nil {
 		t.Components = &openapi3.Components{}
 	}
+	return doc, nil
+}
 
-	return &Document{
-		T:    &t,
-		path: specFile,
-		url:  specURL,
-	}, nil
+// loader returns an OpenAPI loader which resolves references from where the
+// document was loaded.
+func (d *Document) loader() *openapi3.Loader {
+	l := openapi3.NewLoader()
+	l.IsExternalRefsAllowed = true
+	l.ReadFromURIFunc = openapi3.DefaultReadFromURI
+	if d.fsys != nil {
+		l.ReadFromURIFunc = ReadFromFS(d.fsys)
+	}
+	return l
 }
 
 // NewResolvedDocument returns a Document that has already been loaded and
@@ -135,9 +162,7 @@ func (d *Document) Location() *url.URL {
 // ResolveRefs resolves all Ref types in the document, causing the Value field
 // of each Ref to be loaded and populated from its referenced location.
 func (d *Document) ResolveRefs() error {
-	l := openapi3.NewLoader()
-	l.IsExternalRefsAllowed = true
-	return l.ResolveRefsIn(d.T, d.url)
+	return d.loader().ResolveRefsIn(d.T, d.url)
 }
 
 // LoadReference loads a reference from refPath, relative to relPath, into
@@ -148,7 +173,10 @@ func (d *Document) LoadReference(relPath, refPath string, target interface{}) (_
 	if err != nil {
 		return "", err
 	}
-	if refUrl.Scheme == "" || refUrl.Scheme == "file" {
+	local := refUrl.Scheme == "" || refUrl.Scheme == "file"
+	if local && d.fsys != nil {
+		refUrl.Path = path.Join("/", relPath, refUrl.Path)
+	} else if local {
 		refPath, err = filepath.Abs(filepath.Join(relPath, refUrl.Path))
 		if err != nil {
 			return "", err
@@ -157,9 +185,8 @@ func (d *Document) LoadReference(relPath, refPath string, target interface{}) (_
 	}
 
 	// Parse and load the contents of the referenced document.
-	l := openapi3.NewLoader()
-	l.IsExternalRefsAllowed = true
-	contents, err := openapi3.DefaultReadFromURI(l, refUrl)
+	l := d.loader()
+	contents, err := l.ReadFromURIFunc(l, refUrl)
 	if err != nil {
 		return "", fmt.Errorf("failed to read %q: %w", refUrl, err)
 	}
@@ -192,14 +219,18 @@ func (d *Document) LoadReference(relPath, refPath string, target interface{}) (_
 		return "", err
 	}
 
+	if d.fsys != nil {
+		return strings.TrimPrefix(path.Dir(refUrl.Path), "/"), nil
+	}
 	return filepath.Abs(filepath.Dir(refUrl.Path))
 }
 
-// Version returns the version of the document.
+// Version returns the version of the document, from the name of the version
+// directory containing it, in whichever version scheme it is written.
 func (d *Document) Version() (Version, error) {
 	versionDir := filepath.Dir(d.path)
 	versionStr := filepath.Base(versionDir)
-	return ParseVersion(versionStr)
+	return ParseAnyVersion(versionStr)
 }
 
 // Lifecycle returns the lifecycle of the document.
@@ -2,7 +2,12 @@ package vervet_test
 
 import (
 	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"testing/fstest"
 
 	qt "github.com/frankban/quicktest"
 
@@ -29,3 +34,88 @@ func TestDocumentVersion(t *testing.T) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(version, qt.Equals, vervet.MustParseVersion("2021-06-01"))
 }
+
+var relativeRefsFS = fstest.MapFS{
+	"resources/things/2024-01-01/spec.yaml": {Data: []byte(`
+openapi: 3.0.3
+info: {title: things, version: 3.0.0}
+paths:
+  /things/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        '200':
+          description: A thing
+          content:
+            application/json:
+              schema:
+                $ref: '../../schemas/things.yaml#/Thing'
+`)},
+	"resources/schemas/things.yaml": {Data: []byte(`
+Thing:
+  type: object
+  properties:
+    id:
+      $ref: 'common.yaml#/Id'
+`)},
+	"resources/schemas/common.yaml": {Data: []byte(`
+Id:
+  type: string
+  format: uuid
+`)},
+}
+
+func assertRelativeRefsResolved(c *qt.C, doc *vervet.Document) {
+	schema := doc.Paths.Value("/things/{id}").Get.Responses.Status(200).Value.Content.Get("application/json").Schema
+	c.Assert(schema.Value, qt.Not(qt.IsNil))
+	c.Assert(schema.Value.Properties["id"].Value.Format, qt.Equals, "uuid")
+	c.Assert(doc.Validate(context.TODO()), qt.IsNil)
+}
+
+func TestNewDocumentFileRelativeRefs(t *testing.T) {
+	c := qt.New(t)
+	dir := c.TempDir()
+	for name, f := range relativeRefsFS {
+		c.Assert(os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755), qt.IsNil)
+		c.Assert(os.WriteFile(filepath.Join(dir, name), f.Data, 0644), qt.IsNil)
+	}
+	cwd, err := os.Getwd()
+	c.Assert(err, qt.IsNil)
+
+	// Documents may be loaded concurrently, without changing the working
+	// directory.
+	var wg sync.WaitGroup
+	docs := make([]*vervet.Document, 8)
+	errs := make([]error, len(docs))
+	for i := range docs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			docs[i], errs[i] = vervet.NewDocumentFile(filepath.Join(dir, "resources/things/2024-01-01/spec.yaml"))
+		}(i)
+	}
+	wg.Wait()
+	for i := range docs {
+		c.Assert(errs[i], qt.IsNil)
+		assertRelativeRefsResolved(c, docs[i])
+	}
+	after, err := os.Getwd()
+	c.Assert(err, qt.IsNil)
+	c.Assert(after, qt.Equals, cwd)
+}
+
+func TestNewDocumentFS(t *testing.T) {
+	c := qt.New(t)
+	doc, err := vervet.NewDocumentFS(relativeRefsFS, "resources/things/2024-01-01/spec.yaml")
+	c.Assert(err, qt.IsNil)
+	assertRelativeRefsResolved(c, doc)
+	c.Assert(doc.Location().Path, qt.Equals, "/resources/things/2024-01-01/spec.yaml")
+
+	version, err := doc.Version()
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, vervet.MustParseVersion("2024-01-01"))
+
+	_, err = vervet.NewDocumentFS(relativeRefsFS, "resources/missing/2024-01-01/spec.yaml")
+	c.Assert(err, qt.ErrorIs, fs.ErrNotExist)
+}
@@ -1,8 +1,10 @@
 package generate
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -23,8 +25,21 @@ type GeneratorParams struct {
 	DryRun         bool
 	FS             fs.FS
 	Functions      template.FuncMap
+
+	// ManifestFile is the generator manifest recording the files generated
+	// and their inputs. If set, only files whose inputs have changed are
+	// generated again, and files which are no longer generated are removed.
+	ManifestFile string
+
+	// Check compares generated files with those already written, failing
+	// with ErrOutOfDate if they differ, rather than writing them.
+	Check bool
 }
 
+// ErrOutOfDate is returned when checking finds generated files which are out
+// of date.
+var ErrOutOfDate = errors.New("generated files are out of date")
+
 // Generate executes code generators against OpenAPI specs.
 func Generate(params GeneratorParams) error {
 	f, err := os.Open(params.ConfigFile)
@@ -67,13 +82,20 @@ func Generate(params GeneratorParams) error {
 			generatorsHere[k] = filepath.Dir(filepath.Join(basePath, genFile))
 		}
 	}
-	// If a list of specific generators were specified, only instantiate those.
+	// Generators run in dependency order. If a list of specific generators
+	// were specified, only instantiate those.
+	order, err := proj.Generators.Order()
+	if err != nil {
+		return err
+	}
 	if len(selectedGenerators) > 0 {
-		for k := range proj.Generators {
-			if _, ok := selectedGenerators[k]; !ok {
-				delete(proj.Generators, k)
+		selectedOrder := []string{}
+		for _, k := range order {
+			if _, ok := selectedGenerators[k]; ok {
+				selectedOrder = append(selectedOrder, k)
 			}
 		}
+		order = selectedOrder
 	}
 
 	options := []generator.Option{generator.Force(true)}
@@ -89,8 +111,20 @@ func Generate(params GeneratorParams) error {
 	if len(params.Functions) > 0 {
 		options = append(options, generator.Functions(params.Functions))
 	}
-	generators := map[string]*generator.Generator{}
-	for k, genConf := range proj.Generators {
+	if params.Check {
+		options = append(options, generator.Check(true))
+	}
+	var manifest *generator.Manifest
+	if params.ManifestFile != "" {
+		manifest, err = generator.OpenManifest(params.ManifestFile)
+		if err != nil {
+			return err
+		}
+		options = append(options, generator.WithManifest(manifest))
+	}
+	var generators []*generator.Generator
+	for _, k := range order {
+		genConf := proj.Generators[k]
 		genHere, ok := generatorsHere[k]
 		if !ok {
 			genHere = params.ProjectDir
@@ -103,7 +137,7 @@ func Generate(params GeneratorParams) error {
 		if err != nil {
 			return err
 		}
-		generators[k] = gen
+		generators = append(generators, gen)
 	}
 
 	err = os.Chdir(params.ProjectDir)
@@ -116,13 +150,61 @@ func Generate(params GeneratorParams) error {
 		return err
 	}
 
-	var allGeneratedFiles []string
+	// Compiled API versions are only loaded if a generator needs them, as
+	// they may not have been built yet.
+	var apis generator.APIMap
+	for _, gen := range generators {
+		if gen.Scope() == config.GeneratorScopeAPI {
+			apis, err = generator.MapAPIs(proj)
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	var allGeneratedFiles, outOfDate []string
 	for _, gen := range generators {
-		generatedFiles, err := gen.Execute(resources)
+		var generatedFiles []string
+		if gen.Scope() == config.GeneratorScopeAPI {
+			generatedFiles, err = gen.ExecuteAPIs(apis)
+		} else {
+			generatedFiles, err = gen.Execute(resources)
+		}
 		if err != nil {
 			return err
 		}
 		allGeneratedFiles = append(allGeneratedFiles, generatedFiles...)
+		outOfDate = append(outOfDate, gen.OutOfDate()...)
+	}
+
+	if manifest != nil {
+		// Files generated by generators which were not run remain, unless
+		// the generator is no longer configured at all.
+		pruned := order
+		if len(selectedGenerators) == 0 {
+			pruned = manifest.Generators()
+		}
+		if params.Check || params.DryRun {
+			outOfDate = append(outOfDate, manifest.Stale(pruned)...)
+		} else {
+			removed, err := manifest.Prune(pruned)
+			if err != nil {
+				return err
+			}
+			for _, removedFile := range removed {
+				log.Printf("removed %q, which is no longer generated", removedFile)
+			}
+			if err := manifest.Save(); err != nil {
+				return err
+			}
+		}
+	}
+	if params.Check {
+		if len(outOfDate) > 0 {
+			return fmt.Errorf("%w: %s", ErrOutOfDate, strings.Join(outOfDate, ", "))
+		}
+		return nil
 	}
 
 	for _, generatedFile := range allGeneratedFiles {
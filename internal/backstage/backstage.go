@@ -275,14 +275,19 @@ func (c *CatalogInfo) vervetAPI(doc *vervet.Document, root string, pivotDate tim
 
 	apiNameBS := toBackstageName(apiName)
 	docTitleBS := toBackstageName(doc.Info.Title)
-	dateStr := version.DateString()
+	// Versions are named in the form of their scheme, so that versions
+	// released on the same date, or without a date, are distinct.
+	dateStr := version.DirName()
 	nameSuffix := fmt.Sprintf("_%s_%s", apiNameBS, dateStr)
 
 	title := doc.Info.Title + " " + dateStr
 	labels := map[string]string{
 		snykApiVersionDate: dateStr,
 	}
-	tags := []string{version.Date.Format("2006-01")}
+	var tags []string
+	if !version.Date.IsZero() {
+		tags = append(tags, version.Date.Format("2006-01"))
+	}
 	specLifecycle := "production"
 
 	// Specs generated after the pivot date have per operation stability, so
@@ -0,0 +1,144 @@
+package buildcache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/files"
+)
+
+// ResourceFile is a resource version spec file declared in an API.
+type ResourceFile struct {
+	// Path is the location of the spec file.
+	Path string
+
+	// Resource is the directory containing all the versions of the resource.
+	Resource string
+
+	// Version is the resource version declared by the spec file, including
+	// its stability.
+	Version vervet.Version
+
+	SpecInput
+}
+
+// ResourceFiles returns all the resource version spec files declared in an
+// API, in a stable order.
+func (in *Inputs) ResourceFiles(api *config.API) ([]ResourceFile, error) {
+	var resourceFiles []ResourceFile
+	policy, err := api.VersioningPolicy()
+	if err != nil {
+		return nil, err
+	}
+	for _, rcConfig := range api.Resources {
+		specFiles, err := files.LocalFSSource{}.Match(rcConfig)
+		if err != nil {
+			return nil, err
+		}
+		for _, specFile := range specFiles {
+			versionDir := filepath.Dir(specFile)
+			version, err := policy.ParseVersion(filepath.Base(versionDir))
+			if err != nil {
+				return nil, err
+			}
+			spec, err := in.Spec(specFile)
+			if err != nil {
+				return nil, err
+			}
+			if spec.Stability != "" {
+				version.Stability, err = vervet.ParseStability(spec.Stability)
+				if err != nil {
+					return nil, err
+				}
+			}
+			resourceFiles = append(resourceFiles, ResourceFile{
+				Path:      specFile,
+				Resource:  filepath.Dir(versionDir),
+				Version:   version,
+				SpecInput: spec,
+			})
+		}
+	}
+	sort.SliceStable(resourceFiles, func(i, j int) bool {
+		return resourceFiles[i].Path < resourceFiles[j].Path
+	})
+	return resourceFiles, nil
+}
+
+// Parts returns the parts of a resource file's digest: its location,
+// contents and the lifecycle of its version at the time of the build, which
+// is annotated in outputs according to the API's versioning policy.
+func (rf ResourceFile) Parts(policy *vervet.VersioningPolicy) []string {
+	return []string{
+		rf.Path,
+		string(rf.Digest),
+		rf.Version.String(),
+		policy.LifecycleAt(rf.Version, time.Time{}).String(),
+	}
+}
+
+// APIParts returns the parts of the digest of inputs common to all outputs
+// of an API: its configuration, overlays and the CODEOWNERS file used to
+// annotate operation owners.
+func (in *Inputs) APIParts(api *config.API) ([]string, error) {
+	apiJSON, err := json.Marshal(api)
+	if err != nil {
+		return nil, err
+	}
+	parts := []string{api.Name, string(apiJSON)}
+	for _, overlay := range api.Overlays {
+		if overlay.Include != "" {
+			digest, err := in.FileDigest(overlay.Include)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, string(digest))
+		} else if overlay.Inline != "" {
+			parts = append(parts, os.ExpandEnv(overlay.Inline))
+		}
+	}
+	codeownersPath, err := findCodeowners()
+	if err != nil {
+		return nil, err
+	}
+	if codeownersPath != "" {
+		digest, err := FileContentsDigest(codeownersPath)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, codeownersPath, string(digest))
+	}
+	return parts, nil
+}
+
+// findCodeowners returns the path of the CODEOWNERS file that applies to the
+// current working directory, searching the same locations as
+// github.com/hairyhenderson/go-codeowners. Returns "" if there is none.
+func findCodeowners() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, p := range []string{".", "docs", ".github", ".gitlab"} {
+			codeownersPath := filepath.Join(dir, p, "CODEOWNERS")
+			if _, err := os.Stat(codeownersPath); err == nil {
+				return codeownersPath, nil
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,208 @@
+package buildcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Cache records the input digests of built outputs, along with the digests
+// of the files written for them.
+//
+// A nil *Cache is valid and caches nothing.
+type Cache struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]Entry
+	stats   Stats
+}
+
+// Entry is a cached build output.
+type Entry struct {
+	// Inputs is the digest of all the inputs the output was built from.
+	Inputs Digest `json:"inputs"`
+
+	// Outputs maps the files written for the output to the digests of their
+	// contents.
+	Outputs map[string]Digest `json:"outputs"`
+}
+
+// Stats summarizes the use of the cache during a build.
+type Stats struct {
+	Hits   int
+	Misses int
+}
+
+// String implements fmt.Stringer.
+func (s Stats) String() string {
+	return fmt.Sprintf("build cache: %d hits, %d misses", s.Hits, s.Misses)
+}
+
+// DefaultDir returns the default directory in which build caches are stored.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "vervet"), nil
+}
+
+// Open returns the cache of the project in projectDir, stored in cacheDir.
+// An empty cache is returned if none has been saved yet.
+func Open(cacheDir, projectDir string) (*Cache, error) {
+	projectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	key := sha256.Sum256([]byte(projectDir))
+	c := &Cache{
+		path:    filepath.Join(cacheDir, hex.EncodeToString(key[:])+".json"),
+		entries: map[string]Entry{},
+	}
+	contents, err := os.ReadFile(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read build cache: %w", err)
+	}
+	if err := json.Unmarshal(contents, &c.entries); err != nil {
+		// A corrupt cache is discarded rather than failing the build.
+		c.entries = map[string]Entry{}
+	}
+	return c, nil
+}
+
+// Lookup returns whether the output named key was built from inputs, and
+// whether the files written for it are still present and unmodified.
+func (c *Cache) Lookup(key string, inputs Digest) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hit := c.lookup(key, inputs)
+	if hit {
+		c.stats.Hits++
+	} else {
+		c.stats.Misses++
+	}
+	return hit
+}
+
+func (c *Cache) lookup(key string, inputs Digest) bool {
+	entry, ok := c.entries[key]
+	if !ok || entry.Inputs != inputs {
+		return false
+	}
+	for outputPath, digest := range entry.Outputs {
+		actual, err := FileContentsDigest(outputPath)
+		if err != nil || actual != digest {
+			return false
+		}
+	}
+	return true
+}
+
+// Store records that the output named key was built from inputs, written to
+// the given output files.
+func (c *Cache) Store(key string, inputs Digest, outputs []string) error {
+	if c == nil {
+		return nil
+	}
+	entry := Entry{Inputs: inputs, Outputs: map[string]Digest{}}
+	for _, outputPath := range outputs {
+		outputPath, err := filepath.Abs(outputPath)
+		if err != nil {
+			return err
+		}
+		digest, err := FileContentsDigest(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to cache build output: %w", err)
+		}
+		entry.Outputs[outputPath] = digest
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	return nil
+}
+
+// Delete removes the output named key from the cache.
+func (c *Cache) Delete(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Keys returns the sorted keys of cached outputs which start with prefix.
+func (c *Cache) Keys(prefix string) []string {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var keys []string
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Stats returns the cache hits and misses so far.
+func (c *Cache) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Save writes the cache to disk.
+func (c *Cache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	contents, err := json.MarshalIndent(c.entries, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to save build cache: %w", err)
+	}
+	// Write to a temporary file and rename, so that an interrupted build
+	// does not leave a truncated cache behind.
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save build cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save build cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save build cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to save build cache: %w", err)
+	}
+	return nil
+}
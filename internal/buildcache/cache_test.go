@@ -0,0 +1,50 @@
+package buildcache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/snyk/vervet/v8/internal/buildcache"
+)
+
+func TestCache(t *testing.T) {
+	c := qt.New(t)
+	cacheDir, projectDir := c.TempDir(), c.TempDir()
+	outputPath := filepath.Join(projectDir, "out", "spec.json")
+	writeFile(c, outputPath, `{"openapi":"3.0.3"}`)
+	inputs := buildcache.NewDigest("input")
+
+	cache, err := buildcache.Open(cacheDir, projectDir)
+	c.Assert(err, qt.IsNil)
+	c.Assert(cache.Lookup("a", inputs), qt.IsFalse)
+	c.Assert(cache.Store("a", inputs, []string{outputPath}), qt.IsNil)
+	c.Assert(cache.Save(), qt.IsNil)
+
+	cache, err = buildcache.Open(cacheDir, projectDir)
+	c.Assert(err, qt.IsNil)
+	c.Assert(cache.Keys(""), qt.DeepEquals, []string{"a"})
+	c.Assert(cache.Lookup("a", inputs), qt.IsTrue)
+	c.Assert(cache.Lookup("a", buildcache.NewDigest("changed")), qt.IsFalse)
+
+	// Modified outputs are rebuilt.
+	writeFile(c, outputPath, `{}`)
+	c.Assert(cache.Lookup("a", inputs), qt.IsFalse)
+	c.Assert(os.Remove(outputPath), qt.IsNil)
+	c.Assert(cache.Lookup("a", inputs), qt.IsFalse)
+	c.Assert(cache.Stats(), qt.Equals, buildcache.Stats{Hits: 1, Misses: 3})
+
+	cache.Delete("a")
+	c.Assert(cache.Keys(""), qt.HasLen, 0)
+}
+
+func TestNilCache(t *testing.T) {
+	c := qt.New(t)
+	var cache *buildcache.Cache
+	c.Assert(cache.Lookup("a", buildcache.NewDigest("input")), qt.IsFalse)
+	c.Assert(cache.Store("a", buildcache.NewDigest("input"), nil), qt.IsNil)
+	c.Assert(cache.Save(), qt.IsNil)
+	c.Assert(cache.Stats(), qt.Equals, buildcache.Stats{})
+}
@@ -0,0 +1,41 @@
+// Package buildcache records digests of the inputs of compiled API versions,
+// so that builds may skip versions whose inputs have not changed since they
+// were last written.
+package buildcache
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+)
+
+// formatVersion is included in every digest, so that changes to how outputs
+// are derived from inputs invalidate previously cached builds.
+const formatVersion = "vervet-buildcache-1"
+
+// Digest is a sha256 calculation generated into a specific string format
+// prefixed by "sha256:" followed by the sha256 value generated.
+type Digest string
+
+// NewDigest returns the digest of the given parts. Parts are length-prefixed,
+// so that the boundaries between them are significant.
+func NewDigest(parts ...string) Digest {
+	h := sha256.New()
+	var n [8]byte
+	for _, part := range append([]string{formatVersion}, parts...) {
+		binary.BigEndian.PutUint64(n[:], uint64(len(part)))
+		h.Write(n[:])
+		h.Write([]byte(part))
+	}
+	return Digest("sha256:" + base64.StdEncoding.EncodeToString(h.Sum(nil)))
+}
+
+// FileContentsDigest returns the digest of a file's contents.
+func FileContentsDigest(path string) (Digest, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return NewDigest(string(contents)), nil
+}
@@ -0,0 +1,180 @@
+package buildcache
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+)
+
+// SpecInput describes a resource version spec file as a build input.
+type SpecInput struct {
+	// Digest covers the contents of the spec file and of all the local files
+	// it references, recursively.
+	Digest Digest
+
+	// Stability is the value of the x-snyk-api-stability extension declared
+	// in the spec, if any.
+	Stability string
+
+	// Paths are the paths declared in the spec.
+	Paths []string
+}
+
+// Inputs computes the digests of build input files. Each file is read once;
+// digests are memoized so that files referenced from many specs are not
+// re-read. Inputs is safe for concurrent use.
+type Inputs struct {
+	mu    sync.Mutex
+	files map[string]*fileInput
+}
+
+type fileInput struct {
+	contents []byte
+	refs     []string
+	spec     SpecInput
+	done     bool
+}
+
+// NewInputs returns a new Inputs instance.
+func NewInputs() *Inputs {
+	return &Inputs{files: map[string]*fileInput{}}
+}
+
+// FileDigest returns the digest of a file's contents together with those of
+// the local files it references with $ref, recursively. Remote references are
+// included by URL only.
+func (in *Inputs) FileDigest(path string) (Digest, error) {
+	spec, err := in.Spec(path)
+	if err != nil {
+		return "", err
+	}
+	return spec.Digest, nil
+}
+
+// Spec returns a description of the spec file at path as a build input.
+func (in *Inputs) Spec(path string) (SpecInput, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return SpecInput{}, err
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	f, _, err := in.digest(path, map[string]bool{})
+	if err != nil {
+		return SpecInput{}, err
+	}
+	return f.spec, nil
+}
+
+// digest computes the digest of the file at path. It also returns whether
+// the file is part of a reference cycle, in which case its digest depends on
+// where the cycle was entered and is not memoized.
+func (in *Inputs) digest(path string, visiting map[string]bool) (*fileInput, bool, error) {
+	if f, ok := in.files[path]; ok && f.done {
+		return f, false, nil
+	}
+	f, err := in.load(path)
+	if err != nil {
+		return nil, false, err
+	}
+	visiting[path] = true
+	defer delete(visiting, path)
+
+	cyclic := false
+	parts := []string{path, string(f.contents)}
+	for _, ref := range f.refs {
+		refPath, local := localRefPath(path, ref)
+		if !local {
+			parts = append(parts, ref)
+			continue
+		}
+		if visiting[refPath] {
+			// The referenced file is already covered by the digest being
+			// computed.
+			parts = append(parts, refPath)
+			cyclic = true
+			continue
+		}
+		refFile, refCyclic, err := in.digest(refPath, visiting)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read %q referenced from %q: %w", ref, path, err)
+		}
+		parts = append(parts, string(refFile.spec.Digest))
+		cyclic = cyclic || refCyclic
+	}
+	f.spec.Digest = NewDigest(parts...)
+	f.done = !cyclic
+	return f, cyclic, nil
+}
+
+func (in *Inputs) load(path string) (*fileInput, error) {
+	if f, ok := in.files[path]; ok {
+		return f, nil
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &fileInput{contents: contents}
+	var doc interface{}
+	if err := yaml.Unmarshal(contents, &doc); err == nil {
+		refs := map[string]struct{}{}
+		collectRefs(doc, refs)
+		for ref := range refs {
+			f.refs = append(f.refs, ref)
+		}
+		sort.Strings(f.refs)
+		if m, ok := doc.(map[string]interface{}); ok {
+			f.spec.Stability, _ = m["x-snyk-api-stability"].(string)
+			if paths, ok := m["paths"].(map[string]interface{}); ok {
+				for p := range paths {
+					f.spec.Paths = append(f.spec.Paths, p)
+				}
+				sort.Strings(f.spec.Paths)
+			}
+		}
+	}
+	in.files[path] = f
+	return f, nil
+}
+
+// collectRefs adds the value of every $ref found in a decoded YAML document
+// to refs.
+func collectRefs(v interface{}, refs map[string]struct{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if ref, ok := item.(string); ok && k == "$ref" {
+				refs[ref] = struct{}{}
+				continue
+			}
+			collectRefs(item, refs)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectRefs(item, refs)
+		}
+	}
+}
+
+// localRefPath returns the path of the local file referenced by ref from the
+// file at path, and whether the reference is to a local file at all.
+func localRefPath(path, ref string) (string, bool) {
+	if strings.HasPrefix(ref, "#") {
+		return "", false
+	}
+	u, err := url.Parse(ref)
+	if err != nil || (u.Scheme != "" && u.Scheme != "file") || u.Path == "" {
+		return "", false
+	}
+	if filepath.IsAbs(u.Path) {
+		return filepath.Clean(u.Path), true
+	}
+	return filepath.Join(filepath.Dir(path), u.Path), true
+}
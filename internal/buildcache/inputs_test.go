@@ -0,0 +1,64 @@
+package buildcache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/snyk/vervet/v8/internal/buildcache"
+)
+
+func TestSpecInput(t *testing.T) {
+	c := qt.New(t)
+	dir := c.TempDir()
+	specPath := filepath.Join(dir, "2024-01-01", "spec.yaml")
+	writeFile(c, specPath, `
+x-snyk-api-stability: beta
+paths:
+  /things:
+    get:
+      responses:
+        '200':
+          $ref: '../schemas/responses.yaml#/Things'
+        '400':
+          $ref: 'https://example.com/errors.yaml#/BadRequest'
+`)
+	writeFile(c, filepath.Join(dir, "schemas", "responses.yaml"), `
+Things:
+  $ref: 'things.yaml#/Things'
+Other:
+  $ref: 'responses.yaml#/Things'
+`)
+	writeFile(c, filepath.Join(dir, "schemas", "things.yaml"), "Things: {description: things}\n")
+
+	spec, err := buildcache.NewInputs().Spec(specPath)
+	c.Assert(err, qt.IsNil)
+	c.Assert(spec.Stability, qt.Equals, "beta")
+	c.Assert(spec.Paths, qt.DeepEquals, []string{"/things"})
+
+	c.Run("digest is stable", func(c *qt.C) {
+		again, err := buildcache.NewInputs().Spec(specPath)
+		c.Assert(err, qt.IsNil)
+		c.Assert(again.Digest, qt.Equals, spec.Digest)
+	})
+
+	c.Run("digest covers transitively referenced files", func(c *qt.C) {
+		writeFile(c, filepath.Join(dir, "schemas", "things.yaml"), "Things: {description: changed}\n")
+		changed, err := buildcache.NewInputs().Spec(specPath)
+		c.Assert(err, qt.IsNil)
+		c.Assert(changed.Digest, qt.Not(qt.Equals), spec.Digest)
+	})
+
+	c.Run("missing referenced file", func(c *qt.C) {
+		c.Assert(os.Remove(filepath.Join(dir, "schemas", "things.yaml")), qt.IsNil)
+		_, err := buildcache.NewInputs().Spec(specPath)
+		c.Assert(err, qt.ErrorMatches, `failed to read "../schemas/responses.yaml#/Things" referenced from .*`)
+	})
+}
+
+func writeFile(c *qt.C, path, contents string) {
+	c.Assert(os.MkdirAll(filepath.Dir(path), 0755), qt.IsNil)
+	c.Assert(os.WriteFile(path, []byte(contents), 0644), qt.IsNil)
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/internal/generator"
+)
+
+// GenerateClientCommand is the `vervet generate-client` subcommand.
+var GenerateClientCommand = cli.Command{
+	Name:      "generate-client",
+	Usage:     "Generate a Go client package for a compiled version of an API",
+	ArgsUsage: "[compiled api root]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c", "conf"},
+			Usage:   "Project configuration file",
+		},
+		&cli.StringFlag{
+			Name:  "api",
+			Usage: "API in the project configuration; required if the project declares more than one",
+		},
+		&cli.StringFlag{
+			Name:  "version",
+			Usage: "Version requested by the client, such as 2024-06-01~beta. Defaults to the latest version compiled",
+		},
+		&cli.StringFlag{
+			Name:  "package",
+			Usage: "Name of the generated Go package",
+			Value: "client",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "File to write the generated client to. Defaults to standard output",
+		},
+	},
+	Action: GenerateClient,
+}
+
+// GenerateClient generates a Go client package for a compiled version of an
+// API. The client always requests that version, so that it is pinned when
+// the client is compiled.
+func GenerateClient(ctx *cli.Context) error {
+	specDir := ctx.Args().Get(0)
+	if specDir == "" {
+		var err error
+		specDir, err = mockOutputDir(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	docs, err := vervet.LoadVersions(os.DirFS(specDir))
+	if err != nil {
+		return fmt.Errorf("failed to load compiled versions from %q: %w", specDir, err)
+	}
+	versions := make(vervet.VersionSlice, len(docs))
+	for i := range docs {
+		versionStr, err := vervet.ExtensionString(docs[i].Extensions, vervet.ExtSnykApiVersion)
+		if err != nil {
+			return err
+		}
+		versions[i], err = vervet.ParseAnyVersion(versionStr)
+		if err != nil {
+			return err
+		}
+	}
+	index := vervet.NewVersionIndex(versions)
+	requested := index.Versions()[len(index.Versions())-1]
+	if ctx.IsSet("version") {
+		requested, err = vervet.ParseAnyVersion(ctx.String("version"))
+		if err != nil {
+			return err
+		}
+	}
+	resolved, err := index.Resolve(requested)
+	if err != nil {
+		return fmt.Errorf("no compiled version matches %s: %w", requested, err)
+	}
+	resolved.Stability = requested.Stability
+	var src string
+	for i := range versions {
+		if versions[i].Compare(resolved) == 0 {
+			src, err = generator.GoClient(docs[i], ctx.String("package"))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if src == "" {
+		return fmt.Errorf("version %s not compiled in %q", resolved, specDir)
+	}
+
+	output := ctx.String("output")
+	if output == "" {
+		_, err = fmt.Fprint(ctx.App.Writer, src)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0777); err != nil {
+		return err
+	}
+	return os.WriteFile(output, []byte(src), 0666)
+}
@@ -0,0 +1,71 @@
+package cmd_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/snyk/vervet/v8/internal/cmd"
+)
+
+const clientTestSpec = `{
+  "openapi": "3.0.3",
+  "x-snyk-api-version": %q,
+  "info": {"title": "things", "version": "3.0.0"},
+  "paths": {
+    "/things": {
+      "get": {
+        "operationId": "listThings",
+        "responses": {"204": {"description": "No content"}}
+      }
+    }
+  }
+}`
+
+func TestGenerateClient(t *testing.T) {
+	c := qt.New(t)
+	specDir := c.TempDir()
+	for _, version := range []string{"2024-01-01~beta", "2024-06-01"} {
+		path := filepath.Join(specDir, version, "spec.json")
+		c.Assert(os.MkdirAll(filepath.Dir(path), 0755), qt.IsNil)
+		c.Assert(os.WriteFile(path, []byte(fmt.Sprintf(clientTestSpec, version)), 0644), qt.IsNil)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		version string
+		err     string
+	}{{
+		name:    "latest",
+		version: "2024-06-01",
+	}, {
+		name:    "resolved",
+		args:    []string{"--version", "2024-03-01~beta"},
+		version: "2024-01-01~beta",
+	}, {
+		name: "not compiled",
+		args: []string{"--version", "2023-01-01"},
+		err:  `no compiled version matches 2023-01-01: .*`,
+	}}
+	for _, test := range tests {
+		c.Run(test.name, func(c *qt.C) {
+			output := filepath.Join(c.TempDir(), "client", "client_gen.go")
+			args := append([]string{"vervet", "generate-client", "--package", "things", "-o", output}, test.args...)
+			err := cmd.Vervet.Run(append(args, specDir))
+			if test.err != "" {
+				c.Assert(err, qt.ErrorMatches, test.err)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			contents, err := os.ReadFile(output)
+			c.Assert(err, qt.IsNil)
+			c.Assert(string(contents), qt.Contains, "package things\n")
+			c.Assert(string(contents), qt.Contains, fmt.Sprintf("const Version = %q\n", test.version))
+			c.Assert(string(contents), qt.Contains, "func (c *Client) ListThings(ctx context.Context) (*Response, error) {")
+		})
+	}
+}
@@ -12,6 +12,9 @@ import (
 
 	"github.com/manifoldco/promptui"
 	"github.com/urfave/cli/v2"
+	"go.uber.org/multierr"
+
+	"github.com/snyk/vervet/v8"
 )
 
 // MANAGED BY scripts/genversion.bash DO NOT EDIT.
@@ -29,6 +32,8 @@ type VervetParams struct {
 type VervetApp struct {
 	App    *cli.App
 	Params VervetParams
+
+	errorFormat string
 }
 
 // VervetPrompt defines the interface for interactive prompts in vervet.
@@ -49,7 +54,32 @@ func contextWithApp(ctx context.Context, v *VervetApp) context.Context {
 // Run runs the cli.App with the Vervet config params.
 func (v *VervetApp) Run(args []string) error {
 	ctx := contextWithApp(context.Background(), v)
-	return v.App.RunContext(ctx, args)
+	err := v.App.RunContext(ctx, args)
+	if err != nil && v.errorFormat == errorFormatGitHub {
+		// Annotations are written in addition to returning the error, so
+		// that the command still fails.
+		if writeErr := vervet.AsDiagnostics(err).WriteGitHubActions(v.Params.Stderr); writeErr != nil {
+			return multierr.Append(err, writeErr)
+		}
+	}
+	return err
+}
+
+const (
+	errorFormatText   = "text"
+	errorFormatGitHub = "github"
+)
+
+// setErrorFormat sets how the app reports errors.
+func setErrorFormat(ctx *cli.Context) error {
+	format := ctx.String("error-format")
+	if format != errorFormatText && format != errorFormatGitHub {
+		return fmt.Errorf("unsupported error format %q", format)
+	}
+	if v, ok := ctx.Context.Value(vervetKey).(*VervetApp); ok {
+		v.errorFormat = format
+	}
+	return nil
 }
 
 // NewApp returns a new VervetApp with the provided params.
@@ -72,15 +102,25 @@ var CLIApp = cli.App{
 			Name:  "debug",
 			Usage: "Turn on debug logging",
 		},
+		&cli.StringFlag{
+			Name: "error-format",
+			Usage: fmt.Sprintf("Format of errors: %q, or %q to also annotate source files in GitHub Actions",
+				errorFormatText, errorFormatGitHub),
+			Value: errorFormatText,
+		},
 	},
+	Before: setErrorFormat,
 	Commands: []*cli.Command{
 		&BackstageCommand,
 		&BuildCommand,
 		&RetroBuildCommand,
 		&SimpleBuildCommand,
+		&ExplainCommand,
 		&FilterCommand,
 		&GenerateCommand,
+		&GenerateClientCommand,
 		&LocalizeCommand,
+		&MockCommand,
 		&ResourceCommand,
 		&ResolveCommand,
 		&SanitizeCommand,
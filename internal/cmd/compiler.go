@@ -2,11 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"slices"
 
 	"github.com/urfave/cli/v2"
+	"go.uber.org/multierr"
 
 	"github.com/snyk/vervet/v8"
 	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/buildcache"
 	"github.com/snyk/vervet/v8/internal/compiler"
 	"github.com/snyk/vervet/v8/internal/simplebuild"
 )
@@ -15,6 +19,10 @@ var defaultVersioningUrl = "https://api.snyk.io/rest/openapi"
 
 var pivotDateCLIFlagName = "pivot-version"
 var versioningUrlCLIFlagName = "versioning-url"
+var concurrencyCLIFlagName = "concurrency"
+var noCacheCLIFlagName = "no-cache"
+var cacheDirCLIFlagName = "cache-dir"
+var reportCLIFlagName = "report"
 
 var buildFlags = []cli.Flag{
 	&cli.StringFlag{
@@ -41,14 +49,34 @@ var buildFlags = []cli.Flag{
 		Usage:   fmt.Sprintf("URL to fetch versioning information. Default is %q", defaultVersioningUrl),
 		Value:   defaultVersioningUrl,
 	},
+	&cli.IntFlag{
+		Name:    concurrencyCLIFlagName,
+		Aliases: []string{"j"},
+		Usage:   "Maximum number of build tasks run concurrently across all APIs. Default is the number of CPUs",
+	},
+	&cli.BoolFlag{
+		Name:  noCacheCLIFlagName,
+		Usage: "Build all output versions, even those whose inputs have not changed since the last build",
+	},
+	&cli.StringFlag{
+		Name:  cacheDirCLIFlagName,
+		Usage: "Directory in which to store the build cache. Default is a vervet directory in the user cache directory",
+	},
 }
 
+// simpleBuildFlags are the flags of commands which build versions with the
+// simplified versioning rules.
+var simpleBuildFlags = append(slices.Clone(buildFlags), &cli.StringFlag{
+	Name:  reportCLIFlagName,
+	Usage: "Write a JSON report of the APIs, versions and operations built to this file",
+})
+
 // BuildCommand is the `vervet build` subcommand.
 var BuildCommand = cli.Command{
 	Name:      "build",
 	Usage:     "Build versioned resources into versioned OpenAPI specs",
 	ArgsUsage: "[input resources root] [output api root]",
-	Flags:     buildFlags,
+	Flags:     simpleBuildFlags,
 	Action:    CombinedBuild,
 }
 
@@ -65,7 +93,7 @@ var SimpleBuildCommand = cli.Command{
 	Name:      "simplebuild",
 	Usage:     "Build versioned resources into versioned OpenAPI specs",
 	ArgsUsage: "[input resources root]",
-	Flags:     buildFlags,
+	Flags:     simpleBuildFlags,
 	Action:    SimpleBuild,
 }
 
@@ -82,8 +110,20 @@ func SimpleBuild(ctx *cli.Context) error {
 
 	versioningURL := ctx.String(versioningUrlCLIFlagName)
 
-	err = simplebuild.Build(ctx.Context, project, pivotDate, versioningURL, false)
-	return err
+	cache, err := openBuildCache(ctx)
+	if err != nil {
+		return err
+	}
+	err = buildWithReport(ctx, func(report *simplebuild.BuildReport) error {
+		return simplebuild.Build(ctx.Context, project, pivotDate, versioningURL, false,
+			simplebuild.Concurrency(ctx.Int(concurrencyCLIFlagName)),
+			simplebuild.Cache(cache),
+			simplebuild.Report(report))
+	})
+	if err != nil {
+		return err
+	}
+	return saveBuildCache(cache)
 }
 
 // CombinedBuild compiles versioned resources into versioned API specs
@@ -100,7 +140,12 @@ func CombinedBuild(ctx *cli.Context) error {
 
 	versioningURL := ctx.String(versioningUrlCLIFlagName)
 
-	comp, err := compiler.New(ctx.Context, project)
+	concurrency := ctx.Int(concurrencyCLIFlagName)
+	cache, err := openBuildCache(ctx)
+	if err != nil {
+		return err
+	}
+	comp, err := compiler.New(ctx.Context, project, compiler.Concurrency(concurrency), compiler.Cache(cache))
 	if err != nil {
 		return err
 	}
@@ -109,7 +154,69 @@ func CombinedBuild(ctx *cli.Context) error {
 		return err
 	}
 
-	return simplebuild.Build(ctx.Context, project, pivotDate, versioningURL, true)
+	err = buildWithReport(ctx, func(report *simplebuild.BuildReport) error {
+		return simplebuild.Build(ctx.Context, project, pivotDate, versioningURL, true,
+			simplebuild.Concurrency(concurrency),
+			simplebuild.Cache(cache),
+			simplebuild.Report(report))
+	})
+	if err != nil {
+		return err
+	}
+	return saveBuildCache(cache)
+}
+
+// buildWithReport runs a build, writing a report of it to the file given
+// with --report, if any. The report is written even if the build fails.
+func buildWithReport(ctx *cli.Context, build func(*simplebuild.BuildReport) error) error {
+	reportPath := ctx.String(reportCLIFlagName)
+	if reportPath == "" {
+		return build(nil)
+	}
+	report := &simplebuild.BuildReport{}
+	buildErr := build(report)
+	if buildErr != nil {
+		report.Error = buildErr.Error()
+	}
+	if err := report.WriteFile(reportPath); err != nil {
+		return multierr.Append(buildErr, err)
+	}
+	return buildErr
+}
+
+// openBuildCache returns the build cache of the project being built, or nil
+// if caching is disabled.
+func openBuildCache(ctx *cli.Context) (*buildcache.Cache, error) {
+	if ctx.Bool(noCacheCLIFlagName) {
+		return nil, nil //nolint:nilnil // a nil cache caches nothing
+	}
+	cacheDir := ctx.String(cacheDirCLIFlagName)
+	if cacheDir == "" {
+		var err error
+		cacheDir, err = buildcache.DefaultDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate build cache, use --%s or --%s: %w",
+				cacheDirCLIFlagName, noCacheCLIFlagName, err)
+		}
+	}
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return buildcache.Open(cacheDir, projectDir)
+}
+
+// saveBuildCache saves the build cache after a successful build, and reports
+// how much of the build it saved.
+func saveBuildCache(cache *buildcache.Cache) error {
+	if cache == nil {
+		return nil
+	}
+	if err := cache.Save(); err != nil {
+		return err
+	}
+	fmt.Println(cache.Stats())
+	return nil
 }
 
 func parsePivotDate(ctx *cli.Context) (vervet.Version, error) {
@@ -127,11 +234,21 @@ func RetroBuild(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to parse pivot date %q: %w", pivotDate, err)
 	}
-	comp, err := compiler.New(ctx.Context, project)
+	cache, err := openBuildCache(ctx)
+	if err != nil {
+		return err
+	}
+	comp, err := compiler.New(ctx.Context, project,
+		compiler.Concurrency(ctx.Int(concurrencyCLIFlagName)),
+		compiler.Cache(cache))
+	if err != nil {
+		return err
+	}
+	err = comp.BuildAll(ctx.Context, pivotDate)
 	if err != nil {
 		return err
 	}
-	return comp.BuildAll(ctx.Context, pivotDate)
+	return saveBuildCache(cache)
 }
 
 func projectFromContext(ctx *cli.Context) (*config.Project, error) {
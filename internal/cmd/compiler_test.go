@@ -3,7 +3,9 @@ package cmd_test
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	qt "github.com/frankban/quicktest"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -52,7 +54,29 @@ func TestBuildConflict(t *testing.T) {
 	c := qt.New(t)
 	dstDir := c.TempDir()
 	err := cmd.Vervet.Run([]string{"vervet", "build", testdata.Path("conflict"), dstDir})
-	c.Assert(err, qt.ErrorMatches, `failed to load spec versions: conflict: .*`)
+	c.Assert(err, qt.ErrorMatches, `failed to load spec versions: .*: conflict: .*`)
+}
+
+func TestBuildConflictGitHubAnnotations(t *testing.T) {
+	c := qt.New(t)
+	dstDir := c.TempDir()
+	stderr, err := os.Create(filepath.Join(c.TempDir(), "stderr"))
+	c.Assert(err, qt.IsNil)
+	defer stderr.Close()
+	app := cmd.CLIApp
+	vervetApp := cmd.NewApp(&app, cmd.VervetParams{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: stderr,
+		Prompt: cmd.Prompt{},
+	})
+	err = vervetApp.Run([]string{"vervet", "--error-format", "github", "build", testdata.Path("conflict"), dstDir})
+	c.Assert(err, qt.ErrorMatches, `failed to load spec versions: .*: conflict: .*`)
+
+	annotations, err := os.ReadFile(stderr.Name())
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(annotations), qt.Matches,
+		`::error file=.*/conflict/_examples2/2021-06-15/spec.yaml,line=\d+,col=\d+,title=/paths/.*::conflict: .*\n`)
 }
 
 func TestBuildInclude(t *testing.T) {
@@ -101,3 +125,39 @@ func TestBuildInclude(t *testing.T) {
 		c.Assert(expected, qt.JSONEquals, doc)
 	}
 }
+
+func TestBuildCache(t *testing.T) {
+	c := qt.New(t)
+	dstDir, cacheDir := c.TempDir(), c.TempDir()
+	build := func(args ...string) {
+		err := cmd.Vervet.Run(append([]string{"vervet", "retrobuild", "--cache-dir", cacheDir},
+			append(args, testdata.Path("sunset-specs"), dstDir)...))
+		c.Assert(err, qt.IsNil)
+	}
+	specPath := dstDir + "/2023-02-01~experimental" + specFile
+	build()
+	expected, err := os.ReadFile(specPath)
+	c.Assert(err, qt.IsNil)
+
+	// Unchanged inputs and outputs are not rebuilt.
+	marker := time.Unix(0, 0)
+	c.Assert(os.Chtimes(specPath, marker, marker), qt.IsNil)
+	build()
+	info, err := os.Stat(specPath)
+	c.Assert(err, qt.IsNil)
+	c.Assert(info.ModTime().Equal(marker), qt.IsTrue)
+
+	// Modified outputs are rebuilt.
+	c.Assert(os.WriteFile(specPath, []byte("modified"), 0644), qt.IsNil)
+	build()
+	contents, err := os.ReadFile(specPath)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(contents), qt.Equals, string(expected))
+
+	// Everything is rebuilt without the cache.
+	c.Assert(os.Chtimes(specPath, marker, marker), qt.IsNil)
+	build("--no-cache")
+	info, err = os.Stat(specPath)
+	c.Assert(err, qt.IsNil)
+	c.Assert(info.ModTime().Equal(marker), qt.IsFalse)
+}
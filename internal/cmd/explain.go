@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli/v2"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/simplebuild"
+)
+
+// ExplainCommand is the `vervet explain` subcommand.
+var ExplainCommand = cli.Command{
+	Name:      "explain",
+	Usage:     "Explain why an operation appears in a compiled version",
+	ArgsUsage: "METHOD PATH",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c", "conf"},
+			Usage:   "Project configuration file",
+		},
+		&cli.StringFlag{
+			Name:  "api",
+			Usage: "API containing the operation. May be omitted if the project has only one API",
+		},
+		&cli.StringFlag{
+			Name:     "version",
+			Usage:    "Version requested, such as 2024-11-01 or 2023-06-01~beta",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    pivotDateCLIFlagName,
+			Aliases: []string{"P"},
+			Usage:   "Pivot version after which new strategy versioning is used. Defaults to the API's pivot version",
+		},
+	},
+	Action: Explain,
+}
+
+// Explain is a command that explains why an operation appears in a compiled
+// version of an API: which versions of the operation were candidates, which
+// was chosen and why, and when it may be sunset.
+func Explain(ctx *cli.Context) error {
+	if ctx.Args().Len() != 2 {
+		return fmt.Errorf("expected METHOD PATH arguments")
+	}
+	opKey := simplebuild.OpKey{
+		Method: strings.ToUpper(ctx.Args().Get(0)),
+		Path:   ctx.Args().Get(1),
+	}
+	requested, err := vervet.ParseVersion(ctx.String("version"))
+	if err != nil {
+		return err
+	}
+	projectDir, configFile, err := projectConfig(ctx)
+	if err != nil {
+		return err
+	}
+	proj, err := config.FromFile(configFile)
+	if err != nil {
+		return err
+	}
+	err = os.Chdir(projectDir)
+	if err != nil {
+		return err
+	}
+	apiName := ctx.String("api")
+	if apiName == "" {
+		if apiNames := proj.APINames(); len(apiNames) == 1 {
+			apiName = apiNames[0]
+		} else {
+			return fmt.Errorf("--api is required when the project has more than one API")
+		}
+	}
+	api, ok := proj.APIs[apiName]
+	if !ok {
+		return fmt.Errorf("API %q not found in %s", apiName, configFile)
+	}
+
+	policy, err := api.VersioningPolicy()
+	if err != nil {
+		return fmt.Errorf("%w (apis.%s.versioning)", err, apiName)
+	}
+	if ctx.IsSet(pivotDateCLIFlagName) {
+		policy.PivotDate, err = parsePivotDate(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to parse pivot date: %w", err)
+		}
+	}
+
+	ops, err := simplebuild.LoadPaths(ctx.Context, api)
+	if err != nil {
+		return err
+	}
+	explanation, err := simplebuild.Explain(ops, opKey, requested, policy)
+	if err != nil {
+		return fmt.Errorf("%w (apis.%s)", err, apiName)
+	}
+	writeExplanation(ctx.App.Writer, apiName, explanation)
+	return nil
+}
+
+func writeExplanation(w io.Writer, apiName string, e *simplebuild.Explanation) {
+	fmt.Fprintf(w, "%s %s in API %s, version %s\n\n", e.Operation.Method, e.Operation.Path, apiName, e.Requested)
+	if e.Simplified {
+		fmt.Fprintf(w, "Version %s is served by compiled version %s, built with simplified versioning "+
+			"on or after the pivot version %s.\n\n", e.Requested, e.Compiled.DateString(), e.Pivot.DateString())
+	} else {
+		fmt.Fprintf(w, "Version %s is before the pivot version %s, so each resource is resolved "+
+			"at the requested stability.\n\n", e.Requested, e.Pivot.DateString())
+	}
+
+	fmt.Fprintln(w, "Candidates:")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Version", "Resource", "Source", "Chosen"})
+	table.SetAutoWrapText(false)
+	for i := range e.Candidates {
+		c := &e.Candidates[i]
+		chosen := "yes"
+		if c != e.Chosen {
+			chosen = "no: " + c.Reason
+		}
+		table.Append([]string{c.Version.String(), c.ResourceName, c.Source, chosen})
+	}
+	table.Render()
+	fmt.Fprintln(w)
+
+	if e.Chosen == nil {
+		fmt.Fprintf(w, "The operation does not appear in this version: %s.\n", e.Rule)
+		return
+	}
+	fmt.Fprintf(w, "Chosen: %s, from resource %s\n", e.Chosen.Version, e.Chosen.ResourceName)
+	fmt.Fprintf(w, "Rule: %s\n", e.Rule)
+	fmt.Fprintf(w, "Lifecycle: %s\n", e.Lifecycle)
+	if e.DeprecatedBy == nil {
+		fmt.Fprintln(w, "Deprecation: not deprecated by any later version of equal or greater stability")
+		return
+	}
+	fmt.Fprintf(w, "Deprecation: deprecated by %s\n", e.DeprecatedBy)
+	if !e.Sunset.IsZero() {
+		fmt.Fprintf(w, "Sunset: eligible from %s, %d days after deprecation for %s versions\n",
+			e.Sunset.Format(time.DateOnly), int(e.Sunset.Sub(e.DeprecatedBy.Date).Hours()/24),
+			e.Chosen.Version.Stability)
+	}
+}
@@ -0,0 +1,77 @@
+package cmd_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/snyk/vervet/v8/internal/cmd"
+)
+
+const explainTestSpec = `openapi: 3.0.3
+x-snyk-api-stability: %s
+info: {title: things, version: 3.0.0}
+paths:
+  /orgs/{org_id}/things:
+    get:
+      parameters:
+        - {name: org_id, in: path, required: true, schema: {type: string}}
+      responses:
+        '200': {description: OK}
+`
+
+func TestExplain(t *testing.T) {
+	c := qt.New(t)
+	projectDir := c.TempDir()
+	origWD, err := os.Getwd()
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() {
+		c.Assert(os.Chdir(origWD), qt.IsNil)
+	})
+	files := map[string]string{
+		"CODEOWNERS": "",
+		".vervet.yaml": `
+apis:
+  rest:
+    resources:
+      - path: resources
+    output:
+      path: versions
+`,
+		"resources/things/2024-06-01/spec.yaml": fmt.Sprintf(explainTestSpec, "beta"),
+		"resources/things/2024-11-15/spec.yaml": fmt.Sprintf(explainTestSpec, "ga"),
+		"resources/things/2024-12-01/spec.yaml": fmt.Sprintf(explainTestSpec, "experimental"),
+	}
+	for name, contents := range files {
+		path := filepath.Join(projectDir, name)
+		c.Assert(os.MkdirAll(filepath.Dir(path), 0755), qt.IsNil)
+		c.Assert(os.WriteFile(path, []byte(contents), 0644), qt.IsNil)
+	}
+
+	stdout, err := os.Create(filepath.Join(c.TempDir(), "stdout"))
+	c.Assert(err, qt.IsNil)
+	defer stdout.Close()
+	app := cmd.CLIApp
+	vervetApp := cmd.NewApp(&app, cmd.VervetParams{
+		Stdin:  os.Stdin,
+		Stdout: stdout,
+		Stderr: os.Stderr,
+		Prompt: cmd.Prompt{},
+	})
+	err = vervetApp.Run([]string{
+		"vervet", "explain", "--config", filepath.Join(projectDir, ".vervet.yaml"),
+		"--api", "rest", "--version", "2025-01-01", "get", "/orgs/{org_id}/things",
+	})
+	c.Assert(err, qt.IsNil)
+
+	output, err := os.ReadFile(stdout.Name())
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(output), qt.Contains, "served by compiled version 2024-11-15")
+	c.Assert(string(output), qt.Contains, "superseded by 2024-11-15")
+	c.Assert(string(output), qt.Contains, "experimental versions are not compiled")
+	c.Assert(string(output), qt.Contains, "Chosen: 2024-11-15, from resource things")
+	c.Assert(string(output), qt.Contains, "Lifecycle: released")
+}
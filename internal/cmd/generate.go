@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/snyk/vervet/v8/generate"
+	"github.com/snyk/vervet/v8/internal/generator"
 )
 
 // GenerateCommand is the `vervet generate` subcommand.
@@ -29,6 +31,16 @@ var GenerateCommand = cli.Command{
 			Aliases: []string{"g", "gen", "generator"},
 			Usage:   "Generators definition file",
 		},
+		&cli.StringFlag{
+			Name: "manifest",
+			Usage: fmt.Sprintf("Manifest of generated files and their inputs, such as %s in the project directory. "+
+				"If set, only out of date files are generated, and files no longer generated are removed",
+				generator.DefaultManifestFile),
+		},
+		&cli.BoolFlag{
+			Name:  "check",
+			Usage: "Check that generated files are up to date, without writing them",
+		},
 	},
 	Action: Generate,
 }
@@ -51,6 +63,11 @@ func Generate(ctx *cli.Context) error {
 		return err
 	}
 
+	manifestFile, err := manifestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
 	params := generate.GeneratorParams{
 		ProjectDir:     projectDir,
 		ConfigFile:     configFile,
@@ -58,7 +75,19 @@ func Generate(ctx *cli.Context) error {
 		GeneratorsFile: genFile,
 		Debug:          ctx.Bool("debug"),
 		DryRun:         ctx.Bool("dry-run"),
+		ManifestFile:   manifestFile,
+		Check:          ctx.Bool("check"),
 	}
 
 	return generate.Generate(params)
 }
+
+// manifestFromContext returns the absolute path of the manifest given with
+// --manifest, or an empty path if none is given.
+func manifestFromContext(ctx *cli.Context) (string, error) {
+	manifestFile := ctx.String("manifest")
+	if manifestFile == "" {
+		return "", nil
+	}
+	return filepath.Abs(manifestFile)
+}
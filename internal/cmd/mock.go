@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/urfave/cli/v2"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/mock"
+)
+
+// MockCommand is the `vervet mock` subcommand.
+var MockCommand = cli.Command{
+	Name:      "mock",
+	Usage:     "Serve a mock API answering requests for any compiled version",
+	ArgsUsage: "[compiled api root]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c", "conf"},
+			Usage:   "Project configuration file",
+		},
+		&cli.StringFlag{
+			Name:  "api",
+			Usage: "API in the project configuration to serve; required if the project declares more than one",
+		},
+		&cli.StringFlag{
+			Name:    "listen",
+			Aliases: []string{"l"},
+			Usage:   "Address to listen on",
+			Value:   "localhost:8080",
+		},
+		&cli.BoolFlag{
+			Name:  "no-validate",
+			Usage: "Do not validate requests and responses against the requested version",
+		},
+		&cli.BoolFlag{
+			Name:  "strict",
+			Usage: "Respond with an error when a mock response fails validation",
+		},
+	},
+	Action: Mock,
+}
+
+// Mock serves a mock API from compiled OpenAPI spec versions.
+func Mock(ctx *cli.Context) error {
+	specDir := ctx.Args().Get(0)
+	policy := vervet.DefaultVersioningPolicy()
+	if specDir == "" || ctx.IsSet("api") {
+		api, err := mockAPI(ctx)
+		if err != nil {
+			return err
+		}
+		policy, err = api.VersioningPolicy()
+		if err != nil {
+			return fmt.Errorf("%w (apis.%s.versioning)", err, api.Name)
+		}
+		if specDir == "" {
+			specDir, err = apiOutputDir(api)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	docs, err := vervet.LoadVersions(os.DirFS(specDir))
+	if err != nil {
+		return fmt.Errorf("failed to load compiled versions from %q: %w", specDir, err)
+	}
+	listen := ctx.String("listen")
+	serverURL := mockServerURL(listen, docs)
+	h, err := mock.NewHandler(docs,
+		mock.ServerURL(serverURL),
+		mock.Validate(!ctx.Bool("no-validate")),
+		mock.Strict(ctx.Bool("strict")),
+		mock.Policy(policy),
+	)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Addr:              listen,
+		Handler:           h,
+		ReadHeaderTimeout: 15 * time.Second,
+	}
+	log.Printf("serving mock API from %s at %s", specDir, serverURL)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// mockOutputDir returns the compiled output directory of the API selected
+// from the project configuration.
+func mockOutputDir(ctx *cli.Context) (string, error) {
+	api, err := mockAPI(ctx)
+	if err != nil {
+		return "", err
+	}
+	return apiOutputDir(api)
+}
+
+// apiOutputDir returns the compiled output directory of an API.
+func apiOutputDir(api *config.API) (string, error) {
+	if api.Output == nil || len(api.Output.Paths) == 0 {
+		return "", fmt.Errorf("no output defined (apis.%s.output)", api.Name)
+	}
+	return api.Output.Paths[0], nil
+}
+
+// mockAPI returns the API selected from the project configuration.
+func mockAPI(ctx *cli.Context) (*config.API, error) {
+	_, configFile, err := projectConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	proj, err := config.FromFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	apiName := ctx.String("api")
+	if apiName == "" {
+		apiNames := proj.APINames()
+		if len(apiNames) != 1 {
+			return nil, fmt.Errorf("project declares multiple APIs, select one with --api")
+		}
+		apiName = apiNames[0]
+	}
+	api, ok := proj.APIs[apiName]
+	if !ok {
+		return nil, fmt.Errorf("api not found (apis.%s)", apiName)
+	}
+	return api, nil
+}
+
+// mockServerURL returns the URL the mock API is served at, preserving the
+// base path of the servers declared in the compiled specs so that operation
+// paths resolve as they would in production.
+func mockServerURL(listen string, docs []*openapi3.T) string {
+	serverURL := &url.URL{Scheme: "http", Host: listen}
+	for _, doc := range docs {
+		if len(doc.Servers) == 0 {
+			continue
+		}
+		if u, err := url.Parse(doc.Servers[0].URL); err == nil {
+			serverURL.Path = u.Path
+			break
+		}
+	}
+	return serverURL.String()
+}
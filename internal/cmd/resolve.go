@@ -30,7 +30,7 @@ func Resolve(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	version, err := vervet.ParseVersion(ctx.String("at"))
+	version, err := vervet.ParseAnyVersion(ctx.String("at"))
 	if err != nil {
 		return err
 	}
@@ -1,11 +1,18 @@
 package cmd
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
 	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli/v2"
 
@@ -34,80 +41,298 @@ var ResourceCommand = cli.Command{
 		Name:      "info",
 		Usage:     "Information about versioned resources in a vervet project",
 		ArgsUsage: "[api [resource]]",
-		Action:    ResourceShow,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name: "format",
+				Usage: fmt.Sprintf("Output format: %q, %q, %q or %q", resourceFormatTable, resourceFormatJSON,
+					resourceFormatYAML, resourceFormatCSV),
+				Value: resourceFormatTable,
+			},
+			&cli.StringFlag{
+				Name:  "at",
+				Usage: "Date of the lifecycle reported, as YYYY-mm-dd. Defaults to today",
+			},
+		},
+		Action: ResourceShow,
+	}, {
+		Name:      "new",
+		Usage:     "Create a new resource, with a starter spec for its first version",
+		ArgsUsage: "<api> <resource>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "resources",
+				Usage: "Path of the API's resources to create the resource in. Defaults to the first configured",
+			},
+			&cli.StringFlag{
+				Name:  "stability",
+				Usage: "Stability of the first version",
+				Value: vervet.StabilityExperimental.String(),
+			},
+			&cli.BoolFlag{
+				Name:  "no-generate",
+				Usage: "Do not run the project's resource generators",
+			},
+			&cli.StringFlag{
+				Name: "manifest",
+				Usage: "Manifest of generated files and their inputs. " +
+					"If set, only out of date files are generated, and files no longer generated are removed",
+			},
+		},
+		Action: ResourceNew,
+	}, {
+		Name:      "bump",
+		Usage:     "Create a new version of a resource from its latest version",
+		ArgsUsage: "<resource>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "api",
+				Usage: "API of the resource, if more than one API has a resource of the same name",
+			},
+			&cli.StringFlag{
+				Name:  "stability",
+				Usage: "Stability of the new version. Defaults to the stability of the latest version",
+			},
+			&cli.BoolFlag{
+				Name:  "no-generate",
+				Usage: "Do not run the project's resource generators",
+			},
+			&cli.StringFlag{
+				Name: "manifest",
+				Usage: "Manifest of generated files and their inputs. " +
+					"If set, only out of date files are generated, and files no longer generated are removed",
+			},
+		},
+		Action: ResourceBump,
 	}},
 }
 
+const (
+	resourceFormatTable = "table"
+	resourceFormatJSON  = "json"
+	resourceFormatYAML  = "yaml"
+	resourceFormatCSV   = "csv"
+)
+
+// resourceInfo describes an operation of a resource version.
+type resourceInfo struct {
+	API            string   `json:"api"`
+	Resource       string   `json:"resource"`
+	Version        string   `json:"version"`
+	Stability      string   `json:"stability"`
+	Lifecycle      string   `json:"lifecycle"`
+	DeprecatedBy   string   `json:"deprecatedBy,omitempty"`
+	SunsetEligible string   `json:"sunsetEligible,omitempty"`
+	Path           string   `json:"path"`
+	Method         string   `json:"method"`
+	Operation      string   `json:"operation"`
+	Owners         []string `json:"owners,omitempty"`
+
+	version vervet.Version
+}
+
+var resourceInfoHeader = []string{
+	"API", "Resource", "Version", "Stability", "Lifecycle", "Deprecated By", "Sunset Eligible",
+	"Path", "Method", "Operation", "Owners",
+}
+
+func (info *resourceInfo) row() []string {
+	return []string{
+		info.API, info.Resource, info.Version, info.Stability, info.Lifecycle, info.DeprecatedBy,
+		info.SunsetEligible, info.Path, info.Method, info.Operation, strings.Join(info.Owners, " "),
+	}
+}
+
 // ResourceShow is a command that lists all the versions of matching resources.
 // It takes optional arguments to filter the output: api resource.
 func ResourceShow(ctx *cli.Context) error {
-	projectDir, configFile, err := projectConfig(ctx)
-	if err != nil {
-		return err
-	}
-	f, err := os.Open(configFile)
-	if err != nil {
-		return err
+	format := ctx.String("format")
+	switch format {
+	case resourceFormatTable, resourceFormatJSON, resourceFormatYAML, resourceFormatCSV:
+	default:
+		return fmt.Errorf("unsupported format %q", format)
 	}
-	defer f.Close()
-	proj, err := config.Load(f)
-	if err != nil {
-		return err
+	var at time.Time
+	if atArg := ctx.String("at"); atArg != "" {
+		var err error
+		at, err = time.ParseInLocation("2006-01-02", atArg, time.UTC)
+		if err != nil {
+			return fmt.Errorf("invalid date %q: %w", atArg, err)
+		}
 	}
-	err = os.Chdir(projectDir)
+	_, _, proj, err := loadProject(ctx)
 	if err != nil {
 		return err
 	}
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"API", "Resource", "Version", "Path", "Method", "Operation"})
+	infos := []resourceInfo{}
 	for _, apiName := range proj.APINames() {
 		if apiArg := ctx.Args().Get(0); apiArg != "" && apiArg != apiName {
 			continue
 		}
 		api := proj.APIs[apiName]
+		policy, err := api.VersioningPolicy()
+		if err != nil {
+			return err
+		}
 		for _, rcConfig := range api.Resources {
-			specFiles, err := compiler.ResourceSpecFiles(rcConfig)
+			rcInfos, err := resourceSetInfos(apiName, rcConfig, policy, ctx.Args().Get(1), at)
 			if err != nil {
 				return err
 			}
-			resources, err := vervet.LoadResourceVersionsFileset(specFiles)
+			infos = append(infos, rcInfos...)
+		}
+	}
+	return writeResourceInfos(os.Stdout, format, infos)
+}
+
+// resourceSetInfos returns the info of each operation of each version of the
+// resources in a resource set, or only those of the named resource if one is
+// given, ordered by version.
+func resourceSetInfos(apiName string, rcConfig *config.ResourceSet, policy *vervet.VersioningPolicy,
+	rcName string, at time.Time,
+) ([]resourceInfo, error) {
+	specFiles, err := compiler.ResourceSpecFiles(rcConfig)
+	if err != nil {
+		return nil, err
+	}
+	resourceSpecFiles := map[string][]string{}
+	for i := range specFiles {
+		resourceDir := filepath.Dir(filepath.Dir(specFiles[i]))
+		if rcName != "" && rcName != filepath.Base(resourceDir) {
+			continue
+		}
+		resourceSpecFiles[resourceDir] = append(resourceSpecFiles[resourceDir], specFiles[i])
+	}
+	resourceDirs := make([]string, 0, len(resourceSpecFiles))
+	for resourceDir := range resourceSpecFiles {
+		resourceDirs = append(resourceDirs, resourceDir)
+	}
+	sort.Strings(resourceDirs)
+	var infos []resourceInfo
+	for _, resourceDir := range resourceDirs {
+		// Versions are loaded for each resource, so that they are
+		// deprecated only by later versions of the same resource.
+		resources, err := vervet.LoadResourceVersionsFileset(resourceSpecFiles[resourceDir],
+			vervet.WithVersioningPolicy(policy))
+		if err != nil {
+			return nil, err
+		}
+		index := vervet.NewVersionIndex(resources.Versions())
+		for _, version := range resources.Versions() {
+			rc, err := resources.At(version.String())
 			if err != nil {
-				return err
+				return nil, err
 			}
-			for _, version := range resources.Versions() {
-				rc, err := resources.At(version.String())
-				if err != nil {
-					return err
-				}
-				if rcArg := ctx.Args().Get(1); rcArg != "" && rcArg != rc.Name {
-					continue
+			info := resourceInfo{
+				API:       apiName,
+				Resource:  rc.Name,
+				Version:   version.String(),
+				Stability: version.Stability.String(),
+				Lifecycle: policy.LifecycleAt(version, at).String(),
+				version:   version,
+			}
+			if deprecatedBy, ok := index.Deprecates(version); ok {
+				info.DeprecatedBy = deprecatedBy.String()
+				if sunset, ok := policy.Sunset(version, deprecatedBy); ok {
+					info.SunsetEligible = sunset.Format("2006-01-02")
 				}
+			}
+			infos = append(infos, operationInfos(info, rc)...)
+		}
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].version.Compare(infos[j].version) < 0
+	})
+	return infos, nil
+}
 
-				pathNames := rc.Paths.InMatchingOrder()
-				sort.Strings(pathNames)
-				for _, pathName := range pathNames {
-					pathSpec := rc.Paths.Value(pathName)
-					if pathSpec.Get != nil {
-						table.Append([]string{apiName, rc.Name, version.String(), pathName, "GET", pathSpec.Get.OperationID})
-					}
-					if pathSpec.Post != nil {
-						table.Append([]string{apiName, rc.Name, version.String(), pathName, "POST", pathSpec.Post.OperationID})
-					}
-					if pathSpec.Put != nil {
-						table.Append([]string{apiName, rc.Name, version.String(), pathName, "PUT", pathSpec.Put.OperationID})
-					}
-					if pathSpec.Patch != nil {
-						table.Append([]string{apiName, rc.Name, version.String(), pathName, "PATCH", pathSpec.Patch.OperationID})
-					}
-					if pathSpec.Delete != nil {
-						table.Append([]string{apiName, rc.Name, version.String(), pathName, "DELETE", pathSpec.Delete.OperationID})
-					}
-				}
+// operationInfos returns the info of each operation of a resource version.
+func operationInfos(info resourceInfo, rc *vervet.ResourceVersion) []resourceInfo {
+	var infos []resourceInfo
+	pathNames := rc.Paths.InMatchingOrder()
+	sort.Strings(pathNames)
+	for _, pathName := range pathNames {
+		pathSpec := rc.Paths.Value(pathName)
+		for _, op := range []struct {
+			method    string
+			operation *openapi3.Operation
+		}{
+			{"GET", pathSpec.Get},
+			{"POST", pathSpec.Post},
+			{"PUT", pathSpec.Put},
+			{"PATCH", pathSpec.Patch},
+			{"DELETE", pathSpec.Delete},
+		} {
+			if op.operation == nil {
+				continue
 			}
+			opInfo := info
+			opInfo.Path = pathName
+			opInfo.Method = op.method
+			opInfo.Operation = op.operation.OperationID
+			opInfo.Owners = operationOwners(op.operation)
+			infos = append(infos, opInfo)
 		}
 	}
-	table.Render()
-	return nil
+	return infos
+}
+
+// operationOwners returns the owners an operation is annotated with.
+func operationOwners(op *openapi3.Operation) []string {
+	switch owners := op.Extensions[vervet.ExtSnykApiOwner].(type) {
+	case []string:
+		return owners
+	case []any:
+		result := make([]string, 0, len(owners))
+		for i := range owners {
+			if owner, ok := owners[i].(string); ok {
+				result = append(result, owner)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// writeResourceInfos writes resource info to w in the given format.
+func writeResourceInfos(w io.Writer, format string, infos []resourceInfo) error {
+	switch format {
+	case resourceFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(infos)
+	case resourceFormatYAML:
+		buf, err := yaml.Marshal(infos)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(buf)
+		return err
+	case resourceFormatCSV:
+		cw := csv.NewWriter(w)
+		header := make([]string, len(resourceInfoHeader))
+		for i := range resourceInfoHeader {
+			header[i] = strings.ToLower(strings.ReplaceAll(resourceInfoHeader[i], " ", "_"))
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		for i := range infos {
+			if err := cw.Write(infos[i].row()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		table := tablewriter.NewWriter(w)
+		table.SetHeader(resourceInfoHeader)
+		for i := range infos {
+			table.Append(infos[i].row())
+		}
+		table.Render()
+		return nil
+	}
 }
 
 // ResourceFiles is a command that lists all versioned OpenAPI spec files of
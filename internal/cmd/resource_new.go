@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/urfave/cli/v2"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/generate"
+	"github.com/snyk/vervet/v8/internal/files"
+)
+
+var resourceNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// stabilityRE matches the stability annotation of a resource version spec.
+var stabilityRE = regexp.MustCompile(`(?m)^(` + regexp.QuoteMeta(vervet.ExtSnykApiStability) + `:\s*)\S+`)
+
+var starterSpecTemplate = template.Must(template.New("spec.yaml").Parse(`openapi: 3.0.3
+x-snyk-api-stability: {{ .Stability }}
+info:
+  title: {{ .Name }}
+  version: 3.0.0
+tags:
+  - name: {{ .Name }}
+paths:
+  /{{ .Name }}:
+    get:
+      operationId: list{{ .OperationName }}
+      summary: List {{ .Name }}
+      tags:
+        - {{ .Name }}
+      responses:
+        '200':
+          description: A list of {{ .Name }}
+`))
+
+// ResourceNew is a command that creates a new resource, with a starter spec
+// for its first version, and runs the project's resource generators.
+func ResourceNew(ctx *cli.Context) error {
+	apiName, rcName := ctx.Args().Get(0), ctx.Args().Get(1)
+	if apiName == "" || rcName == "" {
+		return fmt.Errorf("an API and resource name are required")
+	}
+	if !resourceNameRE.MatchString(rcName) {
+		return fmt.Errorf("invalid resource name %q", rcName)
+	}
+	stability, err := vervet.ParseStability(ctx.String("stability"))
+	if err != nil {
+		return err
+	}
+	manifestFile, err := manifestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	projectDir, configFile, proj, err := loadProject(ctx)
+	if err != nil {
+		return err
+	}
+	api, ok := proj.APIs[apiName]
+	if !ok {
+		return fmt.Errorf("API %q not found in %q", apiName, configFile)
+	}
+	rcConfig, err := resourceSet(api, ctx.String("resources"))
+	if err != nil {
+		return err
+	}
+	resourceDir := filepath.Join(rcConfig.Path, rcName)
+	if _, err := os.Stat(resourceDir); err == nil {
+		return fmt.Errorf("resource %q already exists in %q", rcName, resourceDir)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	policy, err := api.VersioningPolicy()
+	if err != nil {
+		return err
+	}
+	version, err := nextVersion(policy.Scheme, nil, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	specFile := filepath.Join(resourceDir, version.DirName(), "spec.yaml")
+	if err := os.MkdirAll(filepath.Dir(specFile), 0777); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(specFile, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	err = starterSpecTemplate.Execute(f, map[string]string{
+		"Name":          rcName,
+		"Stability":     stability.String(),
+		"OperationName": operationName(rcName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %q: %w", specFile, err)
+	}
+	fmt.Println(specFile)
+	if ctx.Bool("no-generate") {
+		return nil
+	}
+	return generateResources(projectDir, configFile, proj, manifestFile)
+}
+
+// ResourceBump is a command that creates a new version of a resource, by
+// copying its latest version, and runs the project's resource generators.
+func ResourceBump(ctx *cli.Context) error {
+	rcName := ctx.Args().Get(0)
+	if rcName == "" {
+		return fmt.Errorf("a resource name is required")
+	}
+	manifestFile, err := manifestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	projectDir, configFile, proj, err := loadProject(ctx)
+	if err != nil {
+		return err
+	}
+	var found []string
+	var foundAPI *config.API
+	for _, apiName := range proj.APINames() {
+		if apiArg := ctx.String("api"); apiArg != "" && apiArg != apiName {
+			continue
+		}
+		for _, rcConfig := range proj.APIs[apiName].Resources {
+			dir := filepath.Join(rcConfig.Path, rcName)
+			if st, err := os.Stat(dir); err == nil && st.IsDir() {
+				found = append(found, dir)
+				foundAPI = proj.APIs[apiName]
+			}
+		}
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("resource %q not found", rcName)
+	} else if len(found) > 1 {
+		return fmt.Errorf("resource %q found in more than one place (%s); select one with --api",
+			rcName, strings.Join(found, ", "))
+	}
+	resourceDir := found[0]
+
+	policy, err := foundAPI.VersioningPolicy()
+	if err != nil {
+		return err
+	}
+	resources, err := vervet.LoadResourceVersions(resourceDir, vervet.WithVersioningPolicy(policy))
+	if err != nil {
+		return err
+	}
+	versions := resources.Versions()
+	if len(versions) == 0 {
+		return fmt.Errorf("resource %q has no versions", rcName)
+	}
+	latest := versions[len(versions)-1]
+	stability := latest.Stability
+	if s := ctx.String("stability"); s != "" {
+		if stability, err = vervet.ParseStability(s); err != nil {
+			return err
+		}
+	}
+	version, err := nextVersion(policy.Scheme, &latest, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("cannot bump resource %q: %w", rcName, err)
+	}
+
+	srcDir := filepath.Join(resourceDir, latest.DirName())
+	dstDir := filepath.Join(resourceDir, version.DirName())
+	if err := files.CopyDir(dstDir, srcDir, false); err != nil {
+		return fmt.Errorf("failed to copy %q to %q: %w", srcDir, dstDir, err)
+	}
+	specFiles, err := doublestar.FilepathGlob(filepath.Join(dstDir, "spec.{yaml,yml}"))
+	if err != nil {
+		return err
+	}
+	if len(specFiles) != 1 {
+		return fmt.Errorf("expected one spec file in %q", dstDir)
+	}
+	contents, err := os.ReadFile(specFiles[0])
+	if err != nil {
+		return err
+	}
+	if !stabilityRE.Match(contents) {
+		return fmt.Errorf("%s not found in %q", vervet.ExtSnykApiStability, specFiles[0])
+	}
+	contents = stabilityRE.ReplaceAll(contents, []byte("${1}"+stability.String()))
+	if err := os.WriteFile(specFiles[0], contents, 0666); err != nil {
+		return err
+	}
+	fmt.Println(specFiles[0])
+	if ctx.Bool("no-generate") {
+		return nil
+	}
+	return generateResources(projectDir, configFile, proj, manifestFile)
+}
+
+// loadProject loads the project configuration and changes to the project
+// directory, so that resource paths may be resolved.
+func loadProject(ctx *cli.Context) (string, string, *config.Project, error) {
+	projectDir, configFile, err := projectConfig(ctx)
+	if err != nil {
+		return "", "", nil, err
+	}
+	f, err := os.Open(configFile)
+	if err != nil {
+		return "", "", nil, err
+	}
+	defer f.Close()
+	proj, err := config.Load(f)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if err := os.Chdir(projectDir); err != nil {
+		return "", "", nil, err
+	}
+	return projectDir, configFile, proj, nil
+}
+
+// resourceSet returns the resource set of an API with the given path, or its
+// first resource set if no path is given.
+func resourceSet(api *config.API, path string) (*config.ResourceSet, error) {
+	if len(api.Resources) == 0 {
+		return nil, fmt.Errorf("API %q has no resources", api.Name)
+	}
+	if path == "" {
+		return api.Resources[0], nil
+	}
+	for _, rcConfig := range api.Resources {
+		if filepath.Clean(rcConfig.Path) == filepath.Clean(path) {
+			return rcConfig, nil
+		}
+	}
+	return nil, fmt.Errorf("API %q has no resources in %q", api.Name, path)
+}
+
+// nextVersion returns the version to release after latest, or the first
+// version of a resource if latest is nil, in the given version scheme. Date
+// versions are released on today's date.
+func nextVersion(scheme vervet.VersionScheme, latest *vervet.Version, now time.Time) (vervet.Version, error) {
+	today, err := time.Parse("2006-01-02", now.Format("2006-01-02"))
+	if err != nil {
+		return vervet.Version{}, err
+	}
+	switch scheme {
+	case vervet.SemverScheme:
+		if latest == nil {
+			return vervet.Version{Semantic: vervet.SemanticVersion{Major: 1}}, nil
+		}
+		return vervet.Version{Semantic: vervet.SemanticVersion{Major: latest.Semantic.Major,
+			Minor: latest.Semantic.Minor + 1}}, nil
+	case vervet.DateSequenceScheme:
+		if latest != nil && latest.Date.Equal(today) {
+			return vervet.Version{Date: today, Sequence: latest.Sequence + 1}, nil
+		}
+	}
+	if latest != nil && !latest.Date.Before(today) {
+		return vervet.Version{}, fmt.Errorf("a version was already released on %s", latest.DateString())
+	}
+	return vervet.Version{Date: today}, nil
+}
+
+// operationName returns a resource name in PascalCase, for naming its
+// operations.
+func operationName(rcName string) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(rcName, func(r rune) bool { return r == '-' || r == '_' }) {
+		sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return sb.String()
+}
+
+// generateResources runs the project's resource and version generators,
+// tracking generated files in the manifest given, if any.
+func generateResources(projectDir, configFile string, proj *config.Project, manifestFile string) error {
+	var generators []string
+	for name, genConf := range proj.Generators {
+		if genConf.Scope != config.GeneratorScopeAPI {
+			generators = append(generators, name)
+		}
+	}
+	if len(generators) == 0 {
+		return nil
+	}
+	return generate.Generate(generate.GeneratorParams{
+		ProjectDir:   projectDir,
+		ConfigFile:   configFile,
+		Generators:   generators,
+		ManifestFile: manifestFile,
+	})
+}
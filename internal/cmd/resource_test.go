@@ -1,9 +1,11 @@
 package cmd_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	qt "github.com/frankban/quicktest"
 
@@ -64,20 +66,21 @@ func TestResourceInfo(t *testing.T) {
 	})
 	out, err := os.ReadFile(tmpFile)
 	c.Assert(err, qt.IsNil)
+	//nolint:lll // acked
 	c.Assert(string(out), qt.Equals, `
-+----------+-------------+-------------------------+--------------------------------------+--------+-------------------+
-|   API    |  RESOURCE   |         VERSION         |                 PATH                 | METHOD |     OPERATION     |
-+----------+-------------+-------------------------+--------------------------------------+--------+-------------------+
-| testdata | hello-world | 2021-06-01~experimental | /examples/hello-world/{id}           | GET    | helloWorldGetOne  |
-| testdata | projects    | 2021-06-04~experimental | /orgs/{orgId}/projects               | GET    | getOrgsProjects   |
-| testdata | hello-world | 2021-06-07~experimental | /examples/hello-world/{id}           | GET    | helloWorldGetOne  |
-| testdata | hello-world | 2021-06-13~beta         | /examples/hello-world                | POST   | helloWorldCreate  |
-| testdata | hello-world | 2021-06-13~beta         | /examples/hello-world/{id}           | GET    | helloWorldGetOne  |
-| testdata | projects    | 2021-08-20~experimental | /orgs/{org_id}/projects/{project_id} | DELETE | deleteOrgsProject |
-| testdata | users       | 2023-06-01~experimental | /users                               | GET    | getUsers          |
-| testdata | users       | 2023-06-02~experimental | /users                               | GET    | getUsers          |
-| testdata | projects    | 2023-06-03~experimental | /orgs/{org_id}/projects/{project_id} | DELETE | deleteOrgsProject |
-+----------+-------------+-------------------------+--------------------------------------+--------+-------------------+
++----------+-------------+-------------------------+--------------+-----------+-------------------------+-----------------+--------------------------------------+--------+-------------------+-----------+
+|   API    |  RESOURCE   |         VERSION         |  STABILITY   | LIFECYCLE |      DEPRECATED BY      | SUNSET ELIGIBLE |                 PATH                 | METHOD |     OPERATION     |  OWNERS   |
++----------+-------------+-------------------------+--------------+-----------+-------------------------+-----------------+--------------------------------------+--------+-------------------+-----------+
+| testdata | hello-world | 2021-06-01~experimental | experimental | sunset    | 2021-06-07~experimental | 2021-06-08      | /examples/hello-world/{id}           | GET    | helloWorldGetOne  | @snyk/api |
+| testdata | projects    | 2021-06-04~experimental | experimental | sunset    | 2021-08-20~experimental | 2021-08-21      | /orgs/{orgId}/projects               | GET    | getOrgsProjects   | @snyk/api |
+| testdata | hello-world | 2021-06-07~experimental | experimental | sunset    | 2021-06-13~beta         | 2021-06-14      | /examples/hello-world/{id}           | GET    | helloWorldGetOne  | @snyk/api |
+| testdata | hello-world | 2021-06-13~beta         | beta         | released  |                         |                 | /examples/hello-world                | POST   | helloWorldCreate  | @snyk/api |
+| testdata | hello-world | 2021-06-13~beta         | beta         | released  |                         |                 | /examples/hello-world/{id}           | GET    | helloWorldGetOne  | @snyk/api |
+| testdata | projects    | 2021-08-20~experimental | experimental | sunset    | 2023-06-03~experimental | 2023-06-04      | /orgs/{org_id}/projects/{project_id} | DELETE | deleteOrgsProject | @snyk/api |
+| testdata | users       | 2023-06-01~experimental | experimental | sunset    | 2023-06-02~experimental | 2023-06-03      | /users                               | GET    | getUsers          | @snyk/api |
+| testdata | users       | 2023-06-02~experimental | experimental | sunset    |                         |                 | /users                               | GET    | getUsers          | @snyk/api |
+| testdata | projects    | 2023-06-03~experimental | experimental | sunset    |                         |                 | /orgs/{org_id}/projects/{project_id} | DELETE | deleteOrgsProject | @snyk/api |
++----------+-------------+-------------------------+--------------+-----------+-------------------------+-----------------+--------------------------------------+--------+-------------------+-----------+
 `[1:])
 }
 
@@ -96,13 +99,243 @@ func TestResourceInfoResource(t *testing.T) {
 	})
 	out, err := os.ReadFile(tmpFile)
 	c.Assert(err, qt.IsNil)
+	//nolint:lll // acked
 	c.Assert(string(out), qt.Equals, `
-+----------+----------+-------------------------+--------------------------------------+--------+-------------------+
-|   API    | RESOURCE |         VERSION         |                 PATH                 | METHOD |     OPERATION     |
-+----------+----------+-------------------------+--------------------------------------+--------+-------------------+
-| testdata | projects | 2021-06-04~experimental | /orgs/{orgId}/projects               | GET    | getOrgsProjects   |
-| testdata | projects | 2021-08-20~experimental | /orgs/{org_id}/projects/{project_id} | DELETE | deleteOrgsProject |
-| testdata | projects | 2023-06-03~experimental | /orgs/{org_id}/projects/{project_id} | DELETE | deleteOrgsProject |
-+----------+----------+-------------------------+--------------------------------------+--------+-------------------+
++----------+----------+-------------------------+--------------+-----------+-------------------------+-----------------+--------------------------------------+--------+-------------------+-----------+
+|   API    | RESOURCE |         VERSION         |  STABILITY   | LIFECYCLE |      DEPRECATED BY      | SUNSET ELIGIBLE |                 PATH                 | METHOD |     OPERATION     |  OWNERS   |
++----------+----------+-------------------------+--------------+-----------+-------------------------+-----------------+--------------------------------------+--------+-------------------+-----------+
+| testdata | projects | 2021-06-04~experimental | experimental | sunset    | 2021-08-20~experimental | 2021-08-21      | /orgs/{orgId}/projects               | GET    | getOrgsProjects   | @snyk/api |
+| testdata | projects | 2021-08-20~experimental | experimental | sunset    | 2023-06-03~experimental | 2023-06-04      | /orgs/{org_id}/projects/{project_id} | DELETE | deleteOrgsProject | @snyk/api |
+| testdata | projects | 2023-06-03~experimental | experimental | sunset    |                         |                 | /orgs/{org_id}/projects/{project_id} | DELETE | deleteOrgsProject | @snyk/api |
++----------+----------+-------------------------+--------------+-----------+-------------------------+-----------------+--------------------------------------+--------+-------------------+-----------+
 `[1:])
 }
+
+func TestResourceNewBump(t *testing.T) {
+	c := qt.New(t)
+	dir := c.TempDir()
+	for path, contents := range map[string]string{
+		"CODEOWNERS":  "* @snyk/things\n",
+		"README.tmpl": "{{ .Resource }} {{ .Version }}\n",
+		".vervet.yaml": `
+versionScheme: date-sequence
+apis:
+  things:
+    resources:
+      - path: resources
+generators:
+  readme:
+    scope: version
+    template: README.tmpl
+    filename: "{{ .Path }}/README"
+`,
+		"resources/things/2021-06-01/spec.yaml": `
+openapi: 3.0.3
+x-snyk-api-stability: experimental
+info:
+  title: things
+  version: 3.0.0
+paths:
+  /things:
+    get:
+      operationId: listThings
+      responses:
+        '204':
+          description: Things
+`[1:],
+		"resources/things/2021-06-01/notes.md": "Notes\n",
+	} {
+		c.Assert(os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0777), qt.IsNil)
+		c.Assert(os.WriteFile(filepath.Join(dir, path), []byte(contents), 0666), qt.IsNil)
+	}
+	cd(c, dir)
+	output, err := os.Create(filepath.Join(c.TempDir(), "out"))
+	c.Assert(err, qt.IsNil)
+	defer output.Close()
+	c.Patch(&os.Stdout, output)
+	today := time.Now().UTC().Format("2006-01-02")
+
+	// A new resource has a starter spec, and is generated.
+	err = cmd.Vervet.Run([]string{"vervet", "resource", "new", "things", "user-groups"})
+	c.Assert(err, qt.IsNil)
+	spec, err := os.ReadFile(filepath.Join("resources", "user-groups", today, "spec.yaml"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(spec), qt.Contains, "x-snyk-api-stability: experimental\n")
+	c.Assert(string(spec), qt.Contains, "operationId: listUserGroups\n")
+	readme, err := os.ReadFile(filepath.Join("resources", "user-groups", today, "README"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(readme), qt.Equals, "user-groups "+today+"~experimental\n")
+	// Generated files are only tracked in a manifest when one is given.
+	_, err = os.Stat(".vervet-generated.json")
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+	err = cmd.Vervet.Run([]string{"vervet", "resource", "new", "things", "user-groups"})
+	c.Assert(err, qt.ErrorMatches, `resource "user-groups" already exists in "resources/user-groups"`)
+	err = cmd.Vervet.Run([]string{"vervet", "resource", "new", "nope", "user-groups"})
+	c.Assert(err, qt.ErrorMatches, `API "nope" not found in .*`)
+
+	// Bumping a resource copies its latest version to today's date, with
+	// the stability given.
+	err = cmd.Vervet.Run([]string{"vervet", "resource", "bump", "--stability", "beta", "things"})
+	c.Assert(err, qt.IsNil)
+	spec, err = os.ReadFile(filepath.Join("resources", "things", today, "spec.yaml"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(spec), qt.Contains, "x-snyk-api-stability: beta\n")
+	c.Assert(string(spec), qt.Contains, "operationId: listThings\n")
+	notes, err := os.ReadFile(filepath.Join("resources", "things", today, "notes.md"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(notes), qt.Equals, "Notes\n")
+	readme, err = os.ReadFile(filepath.Join("resources", "things", today, "README"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(readme), qt.Equals, "things "+today+"~beta\n")
+
+	// Further versions on the same date are sequenced, and keep the
+	// stability of the latest version by default.
+	err = cmd.Vervet.Run([]string{"vervet", "resource", "bump", "--manifest", "generated.json", "things"})
+	c.Assert(err, qt.IsNil)
+	manifest, err := os.ReadFile("generated.json")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(manifest), qt.Contains, "resources/things/"+today+".1/README")
+	spec, err = os.ReadFile(filepath.Join("resources", "things", today+".1", "spec.yaml"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(spec), qt.Contains, "x-snyk-api-stability: beta\n")
+	readme, err = os.ReadFile(filepath.Join("resources", "things", today+".1", "README"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(readme), qt.Equals, "things "+today+".1~beta\n")
+	// Other versions on the same date are generated in their own directory.
+	readme, err = os.ReadFile(filepath.Join("resources", "things", today, "README"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(readme), qt.Equals, "things "+today+"~beta\n")
+
+	err = cmd.Vervet.Run([]string{"vervet", "resource", "bump", "nope"})
+	c.Assert(err, qt.ErrorMatches, `resource "nope" not found`)
+}
+
+func TestResourceBumpDateScheme(t *testing.T) {
+	c := qt.New(t)
+	dir := c.TempDir()
+	for path, contents := range map[string]string{
+		"CODEOWNERS":  "* @snyk/things\n",
+		"README.tmpl": "{{ .Resource }} {{ .Version }}\n",
+		".vervet.yaml": `
+apis:
+  things:
+    resources:
+      - path: resources
+generators:
+  readme:
+    scope: version
+    template: README.tmpl
+    filename: "{{ .Path }}/README"
+`,
+		"resources/things/2021-06-01/spec.yaml": `
+openapi: 3.0.3
+x-snyk-api-stability: experimental
+info:
+  title: things
+  version: 3.0.0
+paths:
+  /things:
+    get:
+      operationId: listThings
+      responses:
+        '204':
+          description: Things
+`[1:],
+	} {
+		c.Assert(os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0777), qt.IsNil)
+		c.Assert(os.WriteFile(filepath.Join(dir, path), []byte(contents), 0666), qt.IsNil)
+	}
+	cd(c, dir)
+	output, err := os.Create(filepath.Join(c.TempDir(), "out"))
+	c.Assert(err, qt.IsNil)
+	defer output.Close()
+	c.Patch(&os.Stdout, output)
+	today := time.Now().UTC().Format("2006-01-02")
+
+	err = cmd.Vervet.Run([]string{"vervet", "resource", "bump", "--stability", "beta", "things"})
+	c.Assert(err, qt.IsNil)
+	readme, err := os.ReadFile(filepath.Join("resources", "things", today, "README"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(readme), qt.Equals, "things "+today+"~beta\n")
+
+	// Dates cannot be sequenced in the date scheme.
+	err = cmd.Vervet.Run([]string{"vervet", "resource", "bump", "things"})
+	c.Assert(err, qt.ErrorMatches, `cannot bump resource "things": a version was already released on `+today)
+}
+
+func TestResourceInfoFormat(t *testing.T) {
+	c := qt.New(t)
+	dir := c.TempDir()
+	spec := `
+openapi: 3.0.3
+x-snyk-api-stability: %s
+info:
+  title: things
+  version: 3.0.0
+paths:
+  /things:
+    get:
+      operationId: listThings
+      responses:
+        '204':
+          description: Things
+`[1:]
+	for path, contents := range map[string]string{
+		"CODEOWNERS": "* @org/things\n",
+		".vervet.yaml": `
+apis:
+  api:
+    resources:
+      - path: resources
+`,
+		"resources/things/2021-06-01/spec.yaml": fmt.Sprintf(spec, "experimental"),
+		"resources/things/2021-06-10/spec.yaml": fmt.Sprintf(spec, "beta"),
+	} {
+		c.Assert(os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0777), qt.IsNil)
+		c.Assert(os.WriteFile(filepath.Join(dir, path), []byte(contents), 0666), qt.IsNil)
+	}
+	cd(c, dir)
+
+	run := func(c *qt.C, args ...string) string {
+		outFile := filepath.Join(c.TempDir(), "out")
+		output, err := os.Create(outFile)
+		c.Assert(err, qt.IsNil)
+		defer output.Close()
+		c.Patch(&os.Stdout, output)
+		err = cmd.Vervet.Run(append([]string{"vervet", "resource", "info", "--at", "2021-06-15"}, args...))
+		c.Assert(err, qt.IsNil)
+		out, err := os.ReadFile(outFile)
+		c.Assert(err, qt.IsNil)
+		return string(out)
+	}
+
+	c.Assert(run(c, "--format", "csv"), qt.Equals, `
+api,resource,version,stability,lifecycle,deprecated_by,sunset_eligible,path,method,operation,owners
+api,things,2021-06-01~experimental,experimental,deprecated,2021-06-10~beta,2021-06-11,/things,GET,listThings,@org/things
+api,things,2021-06-10~beta,beta,released,,,/things,GET,listThings,@org/things
+`[1:])
+	c.Assert(run(c, "--format", "json", "api", "things"), qt.JSONEquals, []map[string]any{{
+		"api":            "api",
+		"resource":       "things",
+		"version":        "2021-06-01~experimental",
+		"stability":      "experimental",
+		"lifecycle":      "deprecated",
+		"deprecatedBy":   "2021-06-10~beta",
+		"sunsetEligible": "2021-06-11",
+		"path":           "/things",
+		"method":         "GET",
+		"operation":      "listThings",
+		"owners":         []string{"@org/things"},
+	}, {
+		"api":       "api",
+		"resource":  "things",
+		"version":   "2021-06-10~beta",
+		"stability": "beta",
+		"lifecycle": "released",
+		"path":      "/things",
+		"method":    "GET",
+		"operation": "listThings",
+		"owners":    []string{"@org/things"},
+	}})
+	c.Assert(run(c, "--format", "yaml", "nope"), qt.Equals, "[]\n")
+}
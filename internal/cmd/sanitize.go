@@ -91,7 +91,7 @@ func sanitizeAction(c *cli.Context) error {
 		}
 
 		versionName := filepath.Base(dir)
-		v, err := vervet.ParseVersion(versionName)
+		v, err := vervet.ParseAnyVersion(versionName)
 		if err != nil {
 			fmt.Fprintf(c.App.Writer, "Skipping folder %q: not a valid version: %v\n", dir, err)
 			continue
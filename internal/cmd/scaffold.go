@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/urfave/cli/v2"
 
@@ -23,6 +25,14 @@ var Scaffold = cli.Command{
 				Aliases: []string{"f", "overwrite"},
 				Usage:   "Overwrite existing files",
 			},
+			&cli.StringSliceFlag{
+				Name:  "var",
+				Usage: "Set a scaffold variable, as name=value",
+			},
+			&cli.BoolFlag{
+				Name:  "defaults",
+				Usage: "Use default values for scaffold variables not set with --var, rather than prompting",
+			},
 		},
 		Action: ScaffoldInit,
 	}},
@@ -50,14 +60,21 @@ func ScaffoldInit(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	vervetApp, err := appFromContext(ctx.Context)
+	if err != nil {
+		return err
+	}
+	prompt := vervetApp.Params.Prompt
+	values, err := scaffoldValues(ctx, sc, prompt)
+	if err != nil {
+		return err
+	}
+	if err := sc.SetValues(values); err != nil {
+		return err
+	}
 	err = sc.Organize()
 	if err == scaffold.ErrAlreadyInitialized {
 		// If the project files already exist, prompt the user to see if they want to overwrite them.
-		vervetApp, err := appFromContext(ctx.Context)
-		if err != nil {
-			return err
-		}
-		prompt := vervetApp.Params.Prompt
 		overwrite, err := prompt.Confirm("Scaffold already initialized; do you want to overwrite")
 		if err != nil {
 			return err
@@ -80,3 +97,68 @@ func ScaffoldInit(ctx *cli.Context) error {
 	}
 	return nil
 }
+
+// otherValue is the choice offered to enter a value other than a scaffold
+// variable's default.
+const otherValue = "(enter another value)"
+
+// scaffoldValues gathers the values of a scaffold's variables, from --var
+// flags, or by prompting for them. Variables not set with a flag are left to
+// their default values if --defaults is given.
+func scaffoldValues(ctx *cli.Context, sc *scaffold.Scaffold, prompt VervetPrompt) (map[string]string, error) {
+	values := map[string]string{}
+	for _, arg := range ctx.StringSlice("var") {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid variable %q: must be name=value", arg)
+		}
+		values[name] = value
+	}
+	for _, v := range sc.Variables() {
+		if _, ok := values[v.Name]; ok || ctx.Bool("defaults") {
+			continue
+		}
+		var value string
+		var err error
+		switch v.Type {
+		case scaffold.VariableBool:
+			if v.HasDefault() {
+				value, err = prompt.Select(v.Label(), defaultFirst(&v, []string{"true", "false"}))
+			} else {
+				var confirmed bool
+				confirmed, err = prompt.Confirm(v.Label())
+				value = strconv.FormatBool(confirmed)
+			}
+		case scaffold.VariableChoice:
+			value, err = prompt.Select(v.Label(), defaultFirst(&v, v.Choices))
+		default:
+			if v.HasDefault() {
+				value, err = prompt.Select(v.Label(), []string{v.DefaultString(), otherValue})
+			}
+			if err == nil && (!v.HasDefault() || value == otherValue) {
+				value, err = prompt.Entry(v.Label())
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[v.Name] = value
+	}
+	return values, nil
+}
+
+// defaultFirst returns the choices of a variable with its default first, so
+// that the default is selected unless another choice is made.
+func defaultFirst(v *scaffold.Variable, choices []string) []string {
+	if !v.HasDefault() {
+		return choices
+	}
+	def := v.DefaultString()
+	result := []string{def}
+	for _, choice := range choices {
+		if choice != def {
+			result = append(result, choice)
+		}
+	}
+	return result
+}
@@ -2,6 +2,7 @@ package cmd_test
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -29,6 +30,9 @@ type testPrompt struct {
 	ReturnConfirm bool
 	ReturnSelect  string
 	ReturnEntry   string
+
+	// Selects are the items of each selection prompted for.
+	Selects [][]string
 }
 
 func (tp *testPrompt) Confirm(label string) (bool, error) {
@@ -39,7 +43,13 @@ func (tp *testPrompt) Entry(label string) (string, error) {
 	return tp.ReturnEntry, nil
 }
 
+// Select returns ReturnSelect if set, or otherwise the first item, which is
+// selected by default.
 func (tp *testPrompt) Select(label string, items []string) (string, error) {
+	tp.Selects = append(tp.Selects, items)
+	if tp.ReturnSelect == "" {
+		return items[0], nil
+	}
 	return tp.ReturnSelect, nil
 }
 
@@ -112,3 +122,83 @@ func TestScaffold(t *testing.T) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(fileMarked, qt.IsFalse)
 }
+
+func TestScaffoldVariables(t *testing.T) {
+	c := qt.New(t)
+	prompt := &testPrompt{}
+	testScaffoldCmd := appWithSubcommand(&cmd.Scaffold, prompt)
+
+	// Variables may be set with flags, using defaults for the rest.
+	cd(c, c.TempDir())
+	err := testScaffoldCmd.Run([]string{"vervet", "scaffold", "init", "--defaults",
+		"--var", "owner=@snyk/things", testdata.Path("test-scaffold-v2")})
+	c.Assert(err, qt.IsNil)
+	contents, err := os.ReadFile(vervetConfigFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(contents), qt.Contains, "versionScheme: date\n")
+	c.Assert(string(contents), qt.Contains, "path: 'rest/resources'")
+
+	// A required variable without a value is an error.
+	cd(c, c.TempDir())
+	err = testScaffoldCmd.Run([]string{"vervet", "scaffold", "init", "--defaults", testdata.Path("test-scaffold-v2")})
+	c.Assert(err, qt.ErrorMatches, `a value is required for variable "owner"`)
+
+	// Variables not set with flags are prompted for.
+	cd(c, c.TempDir())
+	prompt.ReturnEntry = "@snyk/widgets"
+	prompt.ReturnSelect = "false"
+	err = testScaffoldCmd.Run([]string{"vervet", "scaffold", "init",
+		"--var", "api=widgets", "--var", "versionScheme=semver", testdata.Path("test-scaffold-v2")})
+	c.Assert(err, qt.IsNil)
+	contents, err = os.ReadFile(vervetConfigFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(contents), qt.Equals, `
+versionScheme: semver
+apis:
+  widgets:
+    resources:
+      - path: 'widgets/resources'
+    output:
+      path: 'widgets/versions'
+`[1:])
+	contents, err = os.ReadFile("CODEOWNERS")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(contents), qt.Equals, "* @snyk/widgets\n")
+	c.Assert(prompt.Selects, qt.DeepEquals, [][]string{{"true", "false"}})
+}
+
+func TestScaffoldVariablesPromptDefaults(t *testing.T) {
+	c := qt.New(t)
+	scaffoldDir := c.TempDir()
+	for path, contents := range map[string]string{
+		"manifest.yaml": `
+version: "2"
+variables:
+  - name: scheme
+    type: choice
+    choices: [date, date-sequence, semver]
+    default: semver
+  - name: readme
+    type: bool
+    default: false
+render:
+  - out.txt
+organize:
+  out.txt: out.txt
+`,
+		"out.txt": "{{ .scheme }} {{ .readme }}\n",
+	} {
+		c.Assert(os.WriteFile(filepath.Join(scaffoldDir, path), []byte(contents), 0666), qt.IsNil)
+	}
+	prompt := &testPrompt{}
+	testScaffoldCmd := appWithSubcommand(&cmd.Scaffold, prompt)
+
+	// Defaults are listed first, so that they are selected by default.
+	cd(c, c.TempDir())
+	err := testScaffoldCmd.Run([]string{"vervet", "scaffold", "init", scaffoldDir})
+	c.Assert(err, qt.IsNil)
+	c.Assert(prompt.Selects, qt.DeepEquals, [][]string{{"semver", "date", "date-sequence"}, {"false", "true"}})
+	contents, err := os.ReadFile("out.txt")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(contents), qt.Equals, "semver false\n")
+}
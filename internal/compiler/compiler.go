@@ -4,30 +4,72 @@ import (
 	"context"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/ghodss/yaml"
-	"go.uber.org/multierr"
 
 	"github.com/snyk/vervet/v8"
 	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/buildcache"
 	"github.com/snyk/vervet/v8/internal/files"
+	"github.com/snyk/vervet/v8/internal/parallel"
 )
 
 // A Compiler checks and builds versioned API resource inputs into aggregated
 // OpenAPI versioned outputs, as determined by an API project configuration.
 type Compiler struct {
-	apis map[string]*api
+	apis        map[string]*api
+	concurrency int
+	pool        *parallel.Pool
+	cache       *buildcache.Cache
+	inputs      *buildcache.Inputs
+	fsys        fs.FS
 }
 
 // CompilerOption applies a configuration option to a Compiler.
 type CompilerOption func(*Compiler) error
 
+// Concurrency sets the maximum number of tasks run at once across all the APIs
+// being built: loading a resource set, or serializing and writing an output
+// version. Values less than 1 use the number of available CPUs.
+func Concurrency(n int) CompilerOption {
+	return func(c *Compiler) error {
+		c.concurrency = n
+		return nil
+	}
+}
+
+// Cache sets the build cache used to skip compiling APIs whose inputs have
+// not changed since they were last compiled. Default is no caching.
+func Cache(cache *buildcache.Cache) CompilerOption {
+	return func(c *Compiler) error {
+		c.cache = cache
+		return nil
+	}
+}
+
+// FS sets the filesystem from which resource specs and overlays are loaded.
+// Paths in the project configuration are relative to the root of fsys.
+// Default is the local filesystem, relative to the working directory.
+//
+// The build cache tracks inputs on the local filesystem, so FS cannot be used
+// with Cache.
+func FS(fsys fs.FS) CompilerOption {
+	return func(c *Compiler) error {
+		c.fsys = fsys
+		return nil
+	}
+}
+
 type api struct {
+	config          *config.API
+	policy          *vervet.VersioningPolicy
 	resources       []*resourceSet
 	overlayIncludes []*vervet.Document
 	overlayInlines  []*openapi3.T
@@ -46,7 +88,8 @@ type output struct {
 // New returns a new Compiler for a given project configuration.
 func New(ctx context.Context, proj *config.Project, options ...CompilerOption) (*Compiler, error) {
 	compiler := &Compiler{
-		apis: map[string]*api{},
+		apis:   map[string]*api{},
+		inputs: buildcache.NewInputs(),
 	}
 
 	for i := range options {
@@ -55,10 +98,19 @@ func New(ctx context.Context, proj *config.Project, options ...CompilerOption) (
 			return nil, err
 		}
 	}
+	if compiler.fsys != nil && compiler.cache != nil {
+		return nil, fmt.Errorf("cannot cache builds loaded from a filesystem")
+	}
+	compiler.pool = parallel.NewPool(compiler.concurrency)
 
 	// set up APIs
 	for apiName, apiConfig := range proj.APIs {
-		a := api{}
+		a := api{config: apiConfig}
+		policy, err := apiConfig.VersioningPolicy()
+		if err != nil {
+			return nil, fmt.Errorf("%w (apis.%s.versioning)", err, apiName)
+		}
+		a.policy = policy
 
 		// Build resources
 		for rcIndex, rcConfig := range apiConfig.Resources {
@@ -66,7 +118,11 @@ func New(ctx context.Context, proj *config.Project, options ...CompilerOption) (
 			r := &resourceSet{
 				path: rcConfig.Path,
 			}
-			r.sourceFiles, err = ResourceSpecFiles(rcConfig)
+			if compiler.fsys != nil {
+				r.sourceFiles, err = files.MatchFS(compiler.fsys, rcConfig)
+			} else {
+				r.sourceFiles, err = ResourceSpecFiles(rcConfig)
+			}
 			if err != nil {
 				return nil, fmt.Errorf("%w: (apis.%s.resources[%d].path)", err, apiName, rcIndex)
 			}
@@ -76,7 +132,7 @@ func New(ctx context.Context, proj *config.Project, options ...CompilerOption) (
 		// Build overlays
 		for overlayIndex, overlayConfig := range apiConfig.Overlays {
 			if overlayConfig.Include != "" {
-				doc, err := vervet.NewDocumentFile(overlayConfig.Include)
+				doc, err := compiler.loadDocument(overlayConfig.Include)
 				if err != nil {
 					return nil, fmt.Errorf("failed to load overlay %q: %w (apis.%s.overlays[%d])",
 						overlayConfig.Include, err, apiName, overlayIndex)
@@ -114,6 +170,14 @@ func New(ctx context.Context, proj *config.Project, options ...CompilerOption) (
 	return compiler, nil
 }
 
+// loadDocument loads a document from the compiler's filesystem.
+func (c *Compiler) loadDocument(path string) (*vervet.Document, error) {
+	if c.fsys != nil {
+		return vervet.NewDocumentFS(c.fsys, path)
+	}
+	return vervet.NewDocumentFile(path)
+}
+
 // ResourceSpecFiles returns all matching spec files for a config.Resource.
 func ResourceSpecFiles(rcConfig *config.ResourceSet) ([]string, error) {
 	return files.LocalFSSource{}.Match(rcConfig)
@@ -121,7 +185,7 @@ func ResourceSpecFiles(rcConfig *config.ResourceSet) ([]string, error) {
 
 // Build builds an aggregate versioned OpenAPI spec for a specific API by name
 // in the project.
-func (c *Compiler) Build(apiName string, stopVersion vervet.Version) error {
+func (c *Compiler) Build(ctx context.Context, apiName string, stopVersion vervet.Version) error {
 	api, ok := c.apis[apiName]
 	if !ok {
 		return fmt.Errorf("api not found (apis.%s)", apiName)
@@ -129,30 +193,70 @@ func (c *Compiler) Build(apiName string, stopVersion vervet.Version) error {
 	if api.output == nil || len(api.output.paths) == 0 {
 		return nil
 	}
+	stopVersion, err := api.config.PivotVersion(stopVersion)
+	if err != nil {
+		return fmt.Errorf("%w (apis.%s.versioning.pivotVersion)", err, apiName)
+	}
+	var digest buildcache.Digest
+	if c.cache != nil {
+		var err error
+		digest, err = c.inputDigest(api, stopVersion)
+		if err != nil {
+			log.Printf("not caching the build of API %s: %v", apiName, err)
+		} else if c.cache.Lookup(cacheKey(apiName), digest) &&
+			fileExists(filepath.Join(api.output.paths[0], "embed.go")) {
+			log.Printf("API %s is unchanged since it was last compiled", apiName)
+			return nil
+		}
+	}
 	for _, path := range api.output.paths {
 		err := os.RemoveAll(path)
 		if err != nil {
 			return fmt.Errorf("failed to clear output directory: %w", err)
 		}
 	}
-	err := os.MkdirAll(api.output.paths[0], 0777)
-	if err != nil {
+	if err := os.MkdirAll(api.output.paths[0], 0777); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 	log.Printf("compiling API %s to output versions", apiName)
-	var versionSpecFiles []string
-	for rcIndex, rc := range api.resources {
-		specVersions, err := vervet.LoadSpecVersionsFileset(rc.sourceFiles)
+
+	// Resource sets are loaded independently of each other, so these are
+	// loaded concurrently.
+	loadResourceSet := func(_ context.Context, rcIndex int) (*vervet.SpecVersions, error) {
+		sourceFiles, policy := api.resources[rcIndex].sourceFiles, vervet.WithVersioningPolicy(api.policy)
+		var specVersions *vervet.SpecVersions
+		var err error
+		if c.fsys != nil {
+			specVersions, err = vervet.LoadSpecVersionsFilesetFS(c.fsys, sourceFiles, policy)
+		} else {
+			specVersions, err = vervet.LoadSpecVersionsFileset(sourceFiles, policy)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to load spec versions: %+v (apis.%s.resources[%d])",
+			return nil, fmt.Errorf("failed to load spec versions: %w (apis.%s.resources[%d])",
 				err, apiName, rcIndex)
 		}
+		return specVersions, nil
+	}
+	rcIndexes := make([]int, len(api.resources))
+	for i := range rcIndexes {
+		rcIndexes[i] = i
+	}
+	loaded, err := parallel.MapPool(ctx, c.pool, rcIndexes, loadResourceSet)
+	if err != nil {
+		return err
+	}
+
+	// Versions of a resource set share the operations and components they
+	// have in common, so overlays are merged into each in turn.
+	var outputs []versionOutput
+	for rcIndex, specVersions := range loaded {
+		rcIndex := rcIndex
 		buildErr := func(err error) error {
 			return fmt.Errorf("%w (apis.%s.resources[%d])", err, apiName, rcIndex)
 		}
 		versions := specVersions.Versions()
 		for _, version := range versions {
-			if version.LifecycleAt(time.Now()) == vervet.LifecycleUnreleased {
+			if api.policy.LifecycleAt(version, time.Now()) == vervet.LifecycleUnreleased {
 				return buildErr(fmt.Errorf(
 					"API spec with version %s is in the future. This is not supported as it may cause breakage",
 					version,
@@ -169,16 +273,6 @@ func (c *Compiler) Build(apiName string, stopVersion vervet.Version) error {
 				return buildErr(err)
 			}
 
-			// Create the directories, but only if a spec file exists for it.
-			versionDir := api.output.paths[0] + "/" + version.String()
-
-			if spec != nil {
-				err = os.MkdirAll(versionDir, 0755)
-				if err != nil {
-					return buildErr(err)
-				}
-			}
-
 			// Merge all overlays
 			for _, doc := range api.overlayIncludes {
 				err = vervet.Merge(spec, doc.T, true)
@@ -193,41 +287,34 @@ func (c *Compiler) Build(apiName string, stopVersion vervet.Version) error {
 				}
 			}
 
-			// Write the compiled spec to JSON and YAML
-			jsonBuf, err := vervet.ToSpecJSON(spec)
-			if err != nil {
-				return buildErr(err)
-			}
-			jsonSpecPath := versionDir + "/spec.json"
-			jsonEmbedPath, err := filepath.Rel(api.output.paths[0], jsonSpecPath)
-			if err != nil {
-				return buildErr(err)
-			}
-			versionSpecFiles = append(versionSpecFiles, jsonEmbedPath)
-			err = os.WriteFile(jsonSpecPath, jsonBuf, 0644)
-			if err != nil {
-				return buildErr(err)
-			}
-			log.Println(jsonSpecPath)
-			yamlBuf, err := yaml.JSONToYAML(jsonBuf)
-			if err != nil {
-				return buildErr(err)
-			}
-			yamlBuf, err = vervet.WithGeneratedComment(yamlBuf)
-			if err != nil {
-				return buildErr(err)
-			}
-			yamlSpecPath := versionDir + "/spec.yaml"
-			yamlEmbedPath, err := filepath.Rel(api.output.paths[0], yamlSpecPath)
-			if err != nil {
-				return buildErr(err)
-			}
-			versionSpecFiles = append(versionSpecFiles, yamlEmbedPath)
-			err = os.WriteFile(yamlSpecPath, yamlBuf, 0644)
-			if err != nil {
-				return buildErr(err)
-			}
-			log.Println(yamlSpecPath)
+			outputs = append(outputs, versionOutput{
+				version:  version,
+				spec:     spec,
+				buildErr: buildErr,
+			})
+		}
+	}
+
+	// Serializing and writing each version is independent of the others, so
+	// these are done concurrently. Results are collected in version order so
+	// that the output is deterministic.
+	writeOutput := func(_ context.Context, out versionOutput) ([]string, error) {
+		specFiles, err := writeVersion(api.output.paths[0], out.version, out.spec)
+		if err != nil {
+			return nil, out.buildErr(err)
+		}
+		return specFiles, nil
+	}
+	written, err := parallel.MapPool(ctx, c.pool, outputs, writeOutput)
+	if err != nil {
+		return err
+	}
+	var versionSpecFiles, outputFiles []string
+	for _, specFiles := range written {
+		for _, specFile := range specFiles {
+			versionSpecFiles = append(versionSpecFiles, specFile)
+			outputFiles = append(outputFiles, filepath.Join(api.output.paths[0], specFile))
+			log.Println(filepath.Join(api.output.paths[0], specFile))
 		}
 	}
 	err = c.writeEmbedGo(filepath.Base(api.output.paths[0]), api, versionSpecFiles)
@@ -241,9 +328,92 @@ func (c *Compiler) Build(apiName string, stopVersion vervet.Version) error {
 			return fmt.Errorf("failed to copy %q to %q: %w", src, dst, err)
 		}
 	}
+	if digest != "" {
+		// The embed.go file is not cached, as it may be rewritten when other
+		// versions are appended to the output.
+		return c.cache.Store(cacheKey(apiName), digest, outputFiles)
+	}
 	return nil
 }
 
+func cacheKey(apiName string) string {
+	return "compiler/" + apiName
+}
+
+// inputDigest returns the digest of all the inputs an API is compiled from.
+func (c *Compiler) inputDigest(a *api, stopVersion vervet.Version) (buildcache.Digest, error) {
+	parts, err := c.inputs.APIParts(a.config)
+	if err != nil {
+		return "", err
+	}
+	resourceFiles, err := c.inputs.ResourceFiles(a.config)
+	if err != nil {
+		return "", err
+	}
+	parts = append(parts, "compiler", stopVersion.String())
+	for _, rf := range resourceFiles {
+		parts = append(parts, rf.Parts(a.policy)...)
+	}
+	return buildcache.NewDigest(parts...), nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// versionOutput is a compiled spec version to be written to the output
+// directory.
+type versionOutput struct {
+	version  vervet.Version
+	spec     *openapi3.T
+	buildErr func(error) error
+}
+
+// writeVersion writes a compiled spec version in JSON and YAML formats to its
+// version directory under outputDir. The written files are returned relative
+// to outputDir.
+func writeVersion(outputDir string, version vervet.Version, spec *openapi3.T) ([]string, error) {
+	versionDir := outputDir + "/" + version.String()
+	err := os.MkdirAll(versionDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write the compiled spec to JSON and YAML
+	jsonBuf, err := vervet.ToSpecJSON(spec)
+	if err != nil {
+		return nil, err
+	}
+	jsonSpecPath := versionDir + "/spec.json"
+	jsonEmbedPath, err := filepath.Rel(outputDir, jsonSpecPath)
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(jsonSpecPath, jsonBuf, 0644)
+	if err != nil {
+		return nil, err
+	}
+	yamlBuf, err := yaml.JSONToYAML(jsonBuf)
+	if err != nil {
+		return nil, err
+	}
+	yamlBuf, err = vervet.WithGeneratedComment(yamlBuf)
+	if err != nil {
+		return nil, err
+	}
+	yamlSpecPath := versionDir + "/spec.yaml"
+	yamlEmbedPath, err := filepath.Rel(outputDir, yamlSpecPath)
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(yamlSpecPath, yamlBuf, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return []string{jsonEmbedPath, yamlEmbedPath}, nil
+}
+
 func (c *Compiler) writeEmbedGo(pkgName string, a *api, versionSpecFiles []string) error {
 	embedPath := filepath.Join(a.output.paths[0], "embed.go")
 	f, err := os.Create(embedPath)
@@ -288,14 +458,17 @@ var Versions embed.FS
 `[1:]))
 
 // BuildAll builds all APIs in the project, before the stop version.
+//
+// APIs are built concurrently. Building an API only coordinates its tasks,
+// which run in the compiler's pool, so it does not take a slot in the pool
+// itself. Errors are combined in API name order.
 func (c *Compiler) BuildAll(ctx context.Context, stopVersion vervet.Version) error {
-	var errs error
+	apiNames := make([]string, 0, len(c.apis))
 	for apiName := range c.apis {
-		err := c.Build(apiName, stopVersion) //nolint:contextcheck
-		// TODO: fix contextcheck in separate PR
-		if err != nil {
-			errs = multierr.Append(errs, err)
-		}
+		apiNames = append(apiNames, apiName)
 	}
-	return errs
+	sort.Strings(apiNames)
+	return parallel.ForEach(ctx, len(apiNames), apiNames, func(ctx context.Context, apiName string) error {
+		return c.Build(ctx, apiName, stopVersion)
+	})
 }
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 	"text/template"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 
 	"github.com/snyk/vervet/v8"
 	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/buildcache"
 	"github.com/snyk/vervet/v8/testdata"
 )
 
@@ -248,3 +250,152 @@ func assertOutputsEqual(c *qt.C, refDir, testDir string) {
 	})
 	c.Assert(err, qt.IsNil)
 }
+
+func TestCompilerConcurrencyDeterministic(t *testing.T) {
+	c := qt.New(t)
+	setup(c)
+	ctx := context.Background()
+	// Two APIs are built from the same resources into separate outputs, so
+	// that they are built concurrently with each other.
+	build := func(concurrency int) string {
+		outputPath := c.TempDir()
+		proj, err := config.Load(bytes.NewBufferString(`
+apis:
+  sunset-api:
+    resources:
+      - path: 'testdata/sunset-specs'
+    output:
+      path: ` + filepath.Join(outputPath, "releases") + `
+  other-api:
+    resources:
+      - path: 'testdata/sunset-specs'
+    output:
+      path: ` + filepath.Join(outputPath, "other") + `
+`[1:]))
+		c.Assert(err, qt.IsNil)
+		compiler, err := New(ctx, proj, Concurrency(concurrency))
+		c.Assert(err, qt.IsNil)
+		err = compiler.BuildAll(ctx, vervet.MustParseVersion("2024-06-01"))
+		c.Assert(err, qt.IsNil)
+		return outputPath
+	}
+	serialPath, concurrentPath := build(1), build(8)
+
+	embedSerial, err := os.ReadFile(filepath.Join(serialPath, "releases", "embed.go"))
+	c.Assert(err, qt.IsNil)
+	embedConcurrent, err := os.ReadFile(filepath.Join(concurrentPath, "releases", "embed.go"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(embedConcurrent), qt.Equals, string(embedSerial))
+	c.Assert(string(embedConcurrent), qt.Contains, "//go:embed 2023-01-01~experimental/spec.json")
+
+	err = fs.WalkDir(os.DirFS(serialPath), ".", func(path string, d fs.DirEntry, err error) error {
+		c.Assert(err, qt.IsNil)
+		if d.IsDir() {
+			return nil
+		}
+		serialFile, err := os.ReadFile(filepath.Join(serialPath, path))
+		c.Assert(err, qt.IsNil)
+		concurrentFile, err := os.ReadFile(filepath.Join(concurrentPath, path))
+		c.Assert(err, qt.IsNil)
+		c.Assert(string(concurrentFile), qt.Equals, string(serialFile), qt.Commentf("%s", path))
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+}
+
+func TestCompilerVersionScheme(t *testing.T) {
+	c := qt.New(t)
+	setup(c)
+	ctx := context.Background()
+	rcDir := c.TempDir()
+	for _, version := range []string{"2023-01-01", "2023-01-01.2"} {
+		specDir := filepath.Join(rcDir, "things", version)
+		c.Assert(os.MkdirAll(specDir, 0777), qt.IsNil)
+		err := os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte(`
+openapi: 3.0.3
+x-snyk-api-stability: ga
+info: {title: things, version: 3.0.0}
+paths:
+  /things:
+    get:
+      description: `+version+`
+      responses:
+        '204': {description: No content}
+`), 0644)
+		c.Assert(err, qt.IsNil)
+	}
+	outputPath := filepath.Join(c.TempDir(), "releases")
+	proj, err := config.Load(bytes.NewBufferString(`
+versionScheme: date-sequence
+apis:
+  things:
+    resources:
+      - path: ` + rcDir + `
+    output:
+      path: ` + outputPath + `
+`[1:]))
+	c.Assert(err, qt.IsNil)
+	compiler, err := New(ctx, proj)
+	c.Assert(err, qt.IsNil)
+	err = compiler.BuildAll(ctx, vervet.MustParseVersion("2024-06-01"))
+	c.Assert(err, qt.IsNil)
+
+	spec, err := os.ReadFile(filepath.Join(outputPath, "2023-01-01.2", "spec.yaml"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(spec), qt.Contains, "description: 2023-01-01.2")
+	c.Assert(string(spec), qt.Contains, "x-snyk-api-version: 2023-01-01.2")
+	spec, err = os.ReadFile(filepath.Join(outputPath, "2023-01-01", "spec.yaml"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(spec), qt.Contains, "x-snyk-deprecated-by: 2023-01-01.2")
+}
+
+func TestCompilerFS(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	fsys := fstest.MapFS{
+		"include.yaml": {Data: []byte("servers: [{url: 'https://example.com/api'}]\n")},
+	}
+	for _, version := range []string{"2023-01-01", "2023-02-01", "2023-03-01"} {
+		fsys["resources/things/"+version+"/spec.yaml"] = &fstest.MapFile{Data: []byte(`
+openapi: 3.0.3
+x-snyk-api-stability: ga
+info: {title: things, version: 3.0.0}
+paths:
+  /things:
+    get:
+      description: ` + version + `
+      responses:
+        '204': {description: No content}
+`)}
+	}
+	outputPath := filepath.Join(c.TempDir(), "releases")
+	proj, err := config.Load(bytes.NewBufferString(`
+apis:
+  things:
+    resources:
+      - path: resources
+        excludes:
+          - 'resources/things/2023-03-01/**'
+    overlays:
+      - include: include.yaml
+    output:
+      path: ` + outputPath + `
+`[1:]))
+	c.Assert(err, qt.IsNil)
+
+	_, err = New(ctx, proj, FS(fsys), Cache(&buildcache.Cache{}))
+	c.Assert(err, qt.ErrorMatches, "cannot cache builds loaded from a filesystem")
+
+	compiler, err := New(ctx, proj, FS(fsys))
+	c.Assert(err, qt.IsNil)
+	err = compiler.BuildAll(ctx, vervet.MustParseVersion("2024-06-01"))
+	c.Assert(err, qt.IsNil)
+
+	spec, err := os.ReadFile(filepath.Join(outputPath, "2023-02-01", "spec.yaml"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(spec), qt.Contains, `description: "2023-02-01"`)
+	c.Assert(string(spec), qt.Contains, "https://example.com/api")
+	// Excluded specs are matched within the filesystem.
+	_, err = os.Stat(filepath.Join(outputPath, "2023-03-01"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+}
@@ -3,6 +3,7 @@ package files
 import (
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 
 	"github.com/bmatcuk/doublestar/v4"
@@ -74,11 +75,34 @@ func (LocalFSSource) Name() string { return "local file" }
 
 // Match implements FileSource.
 func (LocalFSSource) Match(rcConfig *config.ResourceSet) ([]string, error) {
+	return matchSpecs(os.DirFS(rcConfig.Path), rcConfig, func(specPath string) string {
+		return filepath.Join(rcConfig.Path, specPath)
+	})
+}
+
+// MatchFS returns the paths within fsys of the spec files in a resource set,
+// the path of which is relative to the root of fsys. Excludes are matched
+// against paths within fsys.
+func MatchFS(fsys fs.FS, rcConfig *config.ResourceSet) ([]string, error) {
+	rcRoot := path.Clean(filepath.ToSlash(rcConfig.Path))
+	rcFS, err := fs.Sub(fsys, rcRoot)
+	if err != nil {
+		return nil, err
+	}
+	return matchSpecs(rcFS, rcConfig, func(specPath string) string {
+		return path.Join(rcRoot, specPath)
+	})
+}
+
+// matchSpecs returns the spec files found in the root of a resource set,
+// excluding those configured. Paths found are made relative to the resource
+// set with rcPath.
+func matchSpecs(rcFS fs.FS, rcConfig *config.ResourceSet, rcPath func(string) string) ([]string, error) {
 	var result []string
-	err := doublestar.GlobWalk(os.DirFS(rcConfig.Path),
-		vervet.SpecGlobPattern,
+	err := doublestar.GlobWalk(rcFS,
+		vervet.SchemeSpecGlobPattern(rcConfig.VersionScheme),
 		func(path string, d fs.DirEntry) error {
-			rcPath := filepath.Join(rcConfig.Path, path)
+			rcPath := rcPath(path)
 			for i := range rcConfig.Excludes {
 				if ok, err := doublestar.Match(rcConfig.Excludes[i], rcPath); ok {
 					return nil
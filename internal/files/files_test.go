@@ -0,0 +1,31 @@
+package files_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/files"
+)
+
+func TestMatchFS(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{
+		"resources/things/2024-01-01/spec.yaml":          {},
+		"resources/things/2024-02-01/spec.yaml":          {},
+		"resources/_examples/hello/2024-01-01/spec.yaml": {},
+		"resources/schemas/things.yaml":                  {},
+		"other/things/2024-01-01/spec.yaml":              {},
+	}
+	matches, err := files.MatchFS(fsys, &config.ResourceSet{
+		Path:     "resources",
+		Excludes: []string{"resources/_examples/**"},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(matches, qt.DeepEquals, []string{
+		"resources/things/2024-01-01/spec.yaml",
+		"resources/things/2024-02-01/spec.yaml",
+	})
+}
@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/config"
+)
+
+// APIMap defines a mapping from API name to its compiled output versions,
+// in ascending version order.
+type APIMap map[string][]*APIVersion
+
+// APIVersion is a compiled output version of an API.
+type APIVersion struct {
+	// Version is the compiled version.
+	Version vervet.Version
+	// Path is the directory containing the compiled version.
+	Path string
+	// Document is the compiled OpenAPI document of the version.
+	Document *openapi3.T
+}
+
+// MapAPIs returns a mapping of the compiled output versions of all APIs
+// within a Vervet project. APIs without an output are not included.
+func MapAPIs(proj *config.Project) (APIMap, error) {
+	apis := APIMap{}
+	for apiName, apiConfig := range proj.APIs {
+		if apiConfig.Output == nil || len(apiConfig.Output.Paths) == 0 {
+			continue
+		}
+		policy, err := apiConfig.VersioningPolicy()
+		if err != nil {
+			return nil, err
+		}
+		outputPath := apiConfig.Output.Paths[0]
+		docs, err := vervet.LoadVersions(os.DirFS(outputPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load compiled versions of API %q from %q: %w", apiName, outputPath, err)
+		}
+		for _, doc := range docs {
+			versionStr, err := vervet.ExtensionString(doc.Extensions, vervet.ExtSnykApiVersion)
+			if err != nil {
+				return nil, err
+			}
+			version, err := policy.ParseVersion(versionStr)
+			if err != nil {
+				return nil, err
+			}
+			apis[apiName] = append(apis[apiName], &APIVersion{
+				Version:  version,
+				Path:     filepath.Join(outputPath, version.String()),
+				Document: doc,
+			})
+		}
+		sort.Slice(apis[apiName], func(i, j int) bool {
+			return apis[apiName][i].Version.Compare(apis[apiName][j].Version) < 0
+		})
+	}
+	return apis, nil
+}
+
+// APIOperation is an operation in a compiled version of an API, with its
+// lifecycle annotations.
+type APIOperation struct {
+	*openapi3.Operation
+	// Path is the path of the operation.
+	Path string
+	// Method is the HTTP method of the operation, in lowercase.
+	Method string
+	// Resource is the name of the resource providing the operation.
+	Resource string
+	// Version is the resource version providing the operation.
+	Version string
+	// Lifecycle is the lifecycle of the resource version providing the
+	// operation at the time the API was compiled.
+	Lifecycle vervet.Lifecycle
+	// DeprecatedBy is the resource version which deprecates the operation, if
+	// any.
+	DeprecatedBy string
+	// SunsetEligible is the date after which the operation may be removed,
+	// if it is deprecated.
+	SunsetEligible string
+}
+
+// operations returns the operations of a compiled document, in path and
+// method order.
+func operations(doc *openapi3.T) []*APIOperation {
+	var ops []*APIOperation
+	for _, path := range sortedKeys(doc.Paths.Map()) {
+		pathItem := doc.Paths.Value(path)
+		pathOps := MapPathOperations(pathItem)
+		for _, method := range sortedKeys(pathOps) {
+			op := pathOps[method]
+			resource := extensionString(op.Extensions, vervet.ExtSnykApiResource)
+			if resource == "" {
+				// Resources are annotated on the path by the legacy compiler.
+				resource = extensionString(pathItem.Extensions, vervet.ExtSnykApiResource)
+			}
+			ops = append(ops, &APIOperation{
+				Operation:      op,
+				Path:           path,
+				Method:         method,
+				Resource:       resource,
+				Version:        extensionString(op.Extensions, vervet.ExtSnykApiVersion),
+				Lifecycle:      lifecycle(op.Extensions, doc.Extensions),
+				DeprecatedBy:   extensionString(op.Extensions, vervet.ExtSnykDeprecatedBy),
+				SunsetEligible: extensionString(op.Extensions, vervet.ExtSnykSunsetEligible),
+			})
+		}
+	}
+	return ops
+}
+
+// extensionString returns the string value of an extension, or an empty
+// string if it is not set.
+func extensionString(extensions map[string]any, key string) string {
+	s, err := vervet.ExtensionString(extensions, key)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// lifecycle returns the first lifecycle annotated in the given extensions.
+// Like vervet.Document.Lifecycle, versions which are not annotated are
+// assumed to be released.
+func lifecycle(extensions ...map[string]any) vervet.Lifecycle {
+	for i := range extensions {
+		if l, err := vervet.ParseLifecycle(extensionString(extensions[i], vervet.ExtSnykApiLifecycle)); err == nil {
+			return l
+		}
+	}
+	return vervet.LifecycleReleased
+}
@@ -64,6 +64,7 @@ var (
 				*s.AdditionalProperties.Has
 		},
 		"basename": filepath.Base,
+		"goServer": GoServer,
 	}
 )
 
@@ -95,35 +96,25 @@ func (g *Generator) loadFunctions(filename string) error {
 	if err != nil {
 		return err
 	}
+	defer functionFile.Close()
 	src, err := io.ReadAll(functionFile)
 	if err != nil {
 		return err
 	}
-	vm := goja.New()
-	_, err = vm.RunScript(filename, string(src))
+	g.sources = append(g.sources, string(src))
+	rt, err := newJSRuntime(g.fs, filename, g.functionTimeout, g.functionMemoryLimit)
 	if err != nil {
 		return err
 	}
-	module := vm.GlobalObject()
+	functions, err := rt.load(filename, string(src))
 	if err != nil {
 		return err
 	}
-	err = module.Set("console", jsConsole)
-	if err != nil {
-		return err
-	}
-	for _, key := range module.Keys() {
-		fn, ok := goja.AssertFunction(module.Get(key))
-		if !ok {
-			// not a callable function
-			continue
-		}
+	g.sources = append(g.sources, rt.moduleSources()...)
+	for key, fn := range functions {
+		fn := fn
 		g.functions[key] = func(args ...interface{}) (interface{}, error) {
-			jsArgs := make([]goja.Value, len(args))
-			for i := range args {
-				jsArgs[i] = vm.ToValue(args[i])
-			}
-			return fn(goja.Undefined(), jsArgs...)
+			return rt.call(fn, args...)
 		}
 	}
 	return nil
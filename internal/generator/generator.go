@@ -2,19 +2,23 @@ package generator
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/ghodss/yaml"
 
 	"github.com/snyk/vervet/v8"
 	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/buildcache"
 )
 
 // Generator generates files for new resources from data models and templates.
@@ -26,11 +30,23 @@ type Generator struct {
 	functions template.FuncMap
 	scope     config.GeneratorScope
 
-	debug  bool
-	dryRun bool
-	force  bool
-	here   string
-	fs     fs.FS
+	// dependsOn and sources are the generators this one depends on, and the
+	// sources of its templates and functions. Both are inputs of the files it
+	// generates.
+	dependsOn []string
+	sources   []string
+
+	debug     bool
+	dryRun    bool
+	force     bool
+	check     bool
+	here      string
+	fs        fs.FS
+	manifest  *Manifest
+	outOfDate []string
+
+	functionTimeout     time.Duration
+	functionMemoryLimit uint64
 }
 
 // NewMap instanstiates a map of Generators from configuration.
@@ -52,6 +68,10 @@ func New(conf *config.Generator, options ...Option) (*Generator, error) {
 		name:      conf.Name,
 		scope:     conf.Scope,
 		functions: template.FuncMap{},
+		dependsOn: conf.DependsOn,
+
+		functionTimeout:     DefaultFunctionTimeout,
+		functionMemoryLimit: DefaultFunctionMemoryLimit,
 	}
 	for i := range options {
 		options[i](g)
@@ -89,24 +109,18 @@ func New(conf *config.Generator, options ...Option) (*Generator, error) {
 		}
 	}
 
-	// Resolve the template filename... with a template.  Only .Here and .Cwd
-	// are supported, not full scope. Just enough to locate files relative to
-	// the config.
-	templateFilename, err := g.resolveFilename(conf.Template)
-	if err != nil {
-		return nil, fmt.Errorf("%w: (generators.%s.template)", err, conf.Name)
-	}
-
-	// Parse & wire up other templates: contents, filename or files. These do
-	// support full scope.
-	templateFile, err := g.fs.Open(templateFilename)
+	contentsTemplate, err := g.loadContents(conf)
 	if err != nil {
 		return nil, err
 	}
-	contentsTemplate, err := io.ReadAll(templateFile)
+	confJSON, err := json.Marshal(conf)
 	if err != nil {
-		return nil, fmt.Errorf("%w: (generators.%s.contents)", err, conf.Name)
+		return nil, err
 	}
+	g.sources = append(g.sources, string(confJSON))
+
+	// Parse & wire up other templates: contents, filename or files. These do
+	// support full scope.
 	g.contents, err = withIncludeFunc(template.New("contents").
 		Funcs(g.functions).
 		Funcs(builtinFuncs)).
@@ -129,9 +143,57 @@ func New(conf *config.Generator, options ...Option) (*Generator, error) {
 			return nil, fmt.Errorf("%w: (generators.%s.files)", err, conf.Name)
 		}
 	}
+	g.sources = append(g.sources, templateSources(g.contents)...)
 	return g, nil
 }
 
+// templateSources returns the name and definition of each template
+// associated with t, in name order. These are the templates which may be
+// executed with include, so they are all inputs of the files generated.
+func templateSources(t *template.Template) []string {
+	templates := t.Templates()
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name() < templates[j].Name()
+	})
+	var sources []string
+	for _, tmpl := range templates {
+		if tmpl.Tree != nil && tmpl.Tree.Root != nil {
+			sources = append(sources, tmpl.Name(), tmpl.Tree.Root.String())
+		}
+	}
+	return sources
+}
+
+// loadContents returns the contents template of a generator, which is built
+// into vervet for builtin generators.
+func (g *Generator) loadContents(conf *config.Generator) ([]byte, error) {
+	if conf.Builtin != "" {
+		contents, ok := builtinGenerators[conf.Builtin]
+		if !ok {
+			return nil, fmt.Errorf("unknown builtin generator %q (generators.%s.builtin)", conf.Builtin, conf.Name)
+		}
+		return []byte(contents), nil
+	}
+
+	// Resolve the template filename... with a template.  Only .Here and .Cwd
+	// are supported, not full scope. Just enough to locate files relative to
+	// the config.
+	templateFilename, err := g.resolveFilename(conf.Template)
+	if err != nil {
+		return nil, fmt.Errorf("%w: (generators.%s.template)", err, conf.Name)
+	}
+	templateFile, err := g.fs.Open(templateFilename)
+	if err != nil {
+		return nil, err
+	}
+	defer templateFile.Close()
+	contentsTemplate, err := io.ReadAll(templateFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: (generators.%s.contents)", err, conf.Name)
+	}
+	return contentsTemplate, nil
+}
+
 func (g *Generator) resolveFilename(filenameTemplate string) (string, error) {
 	t, err := template.New("").Funcs(g.functions).Parse(filenameTemplate)
 	if err != nil {
@@ -198,6 +260,40 @@ func Filesystem(fileSystem fs.FS) Option {
 	}
 }
 
+// WithManifest configures the Generator to record the files it generates in
+// a manifest, and to skip generating files which the manifest shows are
+// already up to date.
+func WithManifest(m *Manifest) Option {
+	return func(g *Generator) {
+		g.manifest = m
+	}
+}
+
+// Check configures the Generator to compare generated files with those
+// already written rather than writing them. Files which differ are listed by
+// OutOfDate.
+func Check(check bool) Option {
+	return func(g *Generator) {
+		g.check = check
+	}
+}
+
+// FunctionTimeout sets how long the generator's JavaScript functions may run,
+// when loaded or each time they are called.
+func FunctionTimeout(timeout time.Duration) Option {
+	return func(g *Generator) {
+		g.functionTimeout = timeout
+	}
+}
+
+// FunctionMemoryLimit sets how many bytes the generator's JavaScript
+// functions may allocate, when loaded or each time they are called.
+func FunctionMemoryLimit(limit uint64) Option {
+	return func(g *Generator) {
+		g.functionMemoryLimit = limit
+	}
+}
+
 func Functions(funcs template.FuncMap) Option {
 	return func(g *Generator) {
 		for k := range funcs {
@@ -219,12 +315,12 @@ func (g *Generator) Execute(resources ResourceMap) ([]string, error) {
 				}
 				scope := &VersionScope{
 					API:             rcKey.API,
-					Path:            filepath.Join(rcKey.Path, version.DateString()),
+					Path:            filepath.Join(rcKey.Path, version.DirName()),
 					ResourceVersion: rc,
 					Here:            g.here,
 					Env:             getEnvScope(),
 				}
-				generatedFiles, err := g.execute(scope)
+				generatedFiles, err := g.execute(scope.Path, scope)
 				if err != nil {
 					return nil, err
 				}
@@ -240,18 +336,53 @@ func (g *Generator) Execute(resources ResourceMap) ([]string, error) {
 				Here:             g.here,
 				Env:              getEnvScope(),
 			}
-			generatedFiles, err := g.execute(scope)
+			generatedFiles, err := g.execute(scope.Path, scope)
 			if err != nil {
 				return nil, err
 			}
 			allFiles = append(allFiles, generatedFiles...)
 		}
+	case config.GeneratorScopeAPI:
+		return nil, fmt.Errorf("generator scope %q requires compiled API versions (generators.%s.scope)",
+			g.Scope(), g.name)
 	default:
 		return nil, fmt.Errorf("unsupported generator scope %q", g.Scope())
 	}
 	return allFiles, nil
 }
 
+// ExecuteAPIs runs an api scoped generator on each of the given compiled API
+// versions.
+func (g *Generator) ExecuteAPIs(apis APIMap) ([]string, error) {
+	if g.Scope() != config.GeneratorScopeAPI {
+		return nil, fmt.Errorf("unsupported generator scope %q for compiled API versions (generators.%s.scope)",
+			g.Scope(), g.name)
+	}
+	var allFiles []string
+	for _, apiName := range sortedKeys(apis) {
+		apiVersions := apis[apiName]
+		versions := make(vervet.VersionSlice, len(apiVersions))
+		for i := range apiVersions {
+			versions[i] = apiVersions[i].Version
+		}
+		for _, apiVersion := range apiVersions {
+			scope := &APIScope{
+				APIVersion: apiVersion,
+				API:        apiName,
+				Versions:   versions,
+				Here:       g.here,
+				Env:        getEnvScope(),
+			}
+			generatedFiles, err := g.execute(scope.Path, scope)
+			if err != nil {
+				return nil, err
+			}
+			allFiles = append(allFiles, generatedFiles...)
+		}
+	}
+	return allFiles, nil
+}
+
 func getEnvScope() map[string]string {
 	environPrefix := "VERVET_TEMPLATE_"
 	envScope := make(map[string]string)
@@ -310,21 +441,83 @@ func (s *VersionScope) Version() *vervet.Version {
 	return &s.ResourceVersion.Version
 }
 
+// APIScope identifies a compiled version of an API that the generator is
+// building for.
+type APIScope struct {
+	*APIVersion
+	// API is the name of the API.
+	API string
+	// Versions contains all the compiled versions of the API.
+	Versions vervet.VersionSlice
+	// Here is the directory containing the generator template.
+	Here string
+	// Env is a map of template values read from the os environment.
+	Env map[string]string
+}
+
+// Lifecycle returns the lifecycle of the compiled version in scope.
+func (s *APIScope) Lifecycle() vervet.Lifecycle {
+	return lifecycle(s.Document.Extensions)
+}
+
+// Operations returns the operations of the compiled version in scope, with
+// their lifecycle annotations.
+func (s *APIScope) Operations() []*APIOperation {
+	return operations(s.Document)
+}
+
 // Scope returns the configured scope type of the generator.
 func (g *Generator) Scope() config.GeneratorScope {
 	return g.scope
 }
 
+// OutOfDate returns the files which differ from those the Generator would
+// generate, when checking.
+func (g *Generator) OutOfDate() []string {
+	return g.outOfDate
+}
+
+// generatedFile is a file rendered by the Generator.
+type generatedFile struct {
+	name     string
+	contents []byte
+}
+
 // execute the Generator. If generated artifacts already exist, a warning
-// is logged but the file is not overwritten, unless force is true.
-func (g *Generator) execute(scope interface{}) ([]string, error) {
+// is logged but the file is not overwritten, unless force is true. Files
+// which the manifest shows are up to date are not generated again.
+func (g *Generator) execute(scopeKey string, scope interface{}) ([]string, error) {
+	var inputs buildcache.Digest
+	if g.manifest != nil {
+		var err error
+		inputs, err = g.inputsDigest(scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read inputs: %w (generators.%s)", err, g.name)
+		}
+		if files, ok := g.manifest.lookup(g.name, scopeKey, inputs); ok {
+			if g.debug {
+				log.Printf("generators.%s: %q is up to date", g.name, scopeKey)
+			}
+			return files, nil
+		}
+	}
+	var files []generatedFile
+	var err error
 	if g.files != nil {
-		return g.runFiles(scope)
+		files, err = g.runFiles(scope)
+	} else {
+		files, err = g.runFile(scope)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return g.runFile(scope)
+	if g.manifest != nil {
+		g.manifest.store(g.name, scopeKey, inputs, files)
+	}
+	return g.write(files)
 }
 
-func (g *Generator) runFile(scope interface{}) ([]string, error) {
+func (g *Generator) runFile(scope interface{}) ([]generatedFile, error) {
 	var filenameBuf bytes.Buffer
 	err := g.filename.ExecuteTemplate(&filenameBuf, "filename", scope)
 	if err != nil {
@@ -334,34 +527,15 @@ func (g *Generator) runFile(scope interface{}) ([]string, error) {
 	if g.debug {
 		log.Printf("interpolated generators.%s.filename => %q", g.name, filename)
 	}
-	if _, err := os.Stat(filename); err == nil && !g.force {
-		log.Printf("not overwriting existing file %q", filename)
-		return nil, nil
-	}
-	parentDir := filepath.Dir(filename)
-	err = os.MkdirAll(parentDir, 0777)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create %q: %w: (generators.%s.filename)", parentDir, err, g.name)
-	}
-	var out io.Writer
-	if g.dryRun {
-		out = io.Discard
-	} else {
-		f, err := os.Create(filename)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create %q: %w: (generators.%s.filename)", filename, err, g.name)
-		}
-		defer f.Close()
-		out = f
-	}
-	err = g.contents.ExecuteTemplate(out, "contents", scope)
+	var contents bytes.Buffer
+	err = g.contents.ExecuteTemplate(&contents, "contents", scope)
 	if err != nil {
 		return nil, fmt.Errorf("template failed: %w (generators.%s.filename)", err, g.name)
 	}
-	return []string{filename}, nil
+	return []generatedFile{{name: filename, contents: contents.Bytes()}}, nil
 }
 
-func (g *Generator) runFiles(scope interface{}) ([]string, error) {
+func (g *Generator) runFiles(scope interface{}) ([]generatedFile, error) {
 	var filesBuf bytes.Buffer
 	err := g.files.ExecuteTemplate(&filesBuf, "files", scope)
 	if err != nil {
@@ -376,25 +550,91 @@ func (g *Generator) runFiles(scope interface{}) ([]string, error) {
 		// TODO: dump output for debugging?
 		return nil, fmt.Errorf("failed to load output as yaml: %w: (generators.%s.files)", err, g.name)
 	}
-	generatedFiles := []string{}
-	for filename, contents := range files {
-		generatedFiles = append(generatedFiles, filename)
-		dir := filepath.Dir(filename)
-		err := os.MkdirAll(dir, 0777)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create directory %q: %w (generators.%s.files)", dir, err, g.name)
+	generatedFiles := make([]generatedFile, 0, len(files))
+	for _, filename := range sortedKeys(files) {
+		generatedFiles = append(generatedFiles, generatedFile{name: filename, contents: []byte(files[filename])})
+	}
+	return generatedFiles, nil
+}
+
+// write writes generated files, unless they are unchanged, and returns their
+// names. When checking, files are compared rather than written.
+func (g *Generator) write(files []generatedFile) ([]string, error) {
+	var written []string
+	for _, f := range files {
+		existing, err := os.ReadFile(f.name)
+		exists := err == nil
+		if g.check {
+			if !exists || !bytes.Equal(existing, f.contents) {
+				g.outOfDate = append(g.outOfDate, f.name)
+			}
+			written = append(written, f.name)
+			continue
 		}
-		if _, err := os.Stat(filename); err == nil && !g.force {
-			log.Printf("not overwriting existing file %q", filename)
+		if exists && !g.force {
+			log.Printf("not overwriting existing file %q", f.name)
 			continue
 		}
-		if g.dryRun {
+		written = append(written, f.name)
+		if g.dryRun || (exists && bytes.Equal(existing, f.contents)) {
 			continue
 		}
-		err = os.WriteFile(filename, []byte(contents), 0777)
+		dir := filepath.Dir(f.name)
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			return nil, fmt.Errorf("failed to create directory %q: %w (generators.%s)", dir, err, g.name)
+		}
+		if err := os.WriteFile(f.name, f.contents, 0666); err != nil {
+			return nil, fmt.Errorf("failed to write file %q: %w (generators.%s)", f.name, err, g.name)
+		}
+	}
+	return written, nil
+}
+
+// inputsDigest returns the digest of the inputs of the files generated in a
+// scope: the generator's configuration and templates, the scope, the specs
+// it is generated from and the files generated by the generators this one
+// depends on.
+func (g *Generator) inputsDigest(scope interface{}) (buildcache.Digest, error) {
+	parts := append([]string{}, g.sources...)
+	parts = append(parts, g.manifest.outputsDigest(g.dependsOn))
+	var docs []*vervet.Document
+	var meta any
+	switch s := scope.(type) {
+	case *VersionScope:
+		docs = append(docs, s.ResourceVersion.Document)
+		meta = []any{s.API, s.Path, s.Here, s.Env, s.ResourceVersion.Name, s.ResourceVersion.Version}
+	case *ResourceScope:
+		for _, version := range s.Versions() {
+			rc, err := s.At(version.String())
+			if err != nil {
+				return "", err
+			}
+			docs = append(docs, rc.Document)
+		}
+		meta = []any{s.API, s.Path, s.Here, s.Env, s.Name(), s.Versions()}
+	case *APIScope:
+		// Compiled documents are self-contained.
+		docs = append(docs, &vervet.Document{T: s.Document})
+		meta = []any{s.API, s.Path, s.Here, s.Env, s.Version, s.Versions}
+	}
+	metaJSON, err := json.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+	parts = append(parts, string(metaJSON))
+	for _, doc := range docs {
+		// Local spec files are digested along with the files they reference.
+		if loc := doc.Location(); loc != nil && (loc.Scheme == "" || loc.Scheme == "file") {
+			if digest, err := g.manifest.inputs.FileDigest(loc.Path); err == nil {
+				parts = append(parts, string(digest))
+				continue
+			}
+		}
+		docJSON, err := json.Marshal(doc)
 		if err != nil {
-			return nil, fmt.Errorf("failed to write file %q: %w (generators.%s.files)", filename, err, g.name)
+			return "", err
 		}
+		parts = append(parts, string(docJSON))
 	}
-	return generatedFiles, nil
+	return buildcache.NewDigest(parts...), nil
 }
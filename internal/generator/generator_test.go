@@ -88,6 +88,77 @@ version-readme:
 	}
 }
 
+func TestVersionScopeSchemes(t *testing.T) {
+	c := qt.New(t)
+	tests := []struct {
+		scheme   string
+		versions []string
+	}{{
+		scheme:   "date-sequence",
+		versions: []string{"2024-01-01", "2024-01-01.2"},
+	}, {
+		scheme:   "semver",
+		versions: []string{"1.0.0", "1.1.0"},
+	}}
+	for _, test := range tests {
+		c.Run(test.scheme, func(c *qt.C) {
+			dir := c.TempDir()
+			cwd, err := os.Getwd()
+			c.Assert(err, qt.IsNil)
+			c.Assert(os.Chdir(dir), qt.IsNil)
+			c.Cleanup(func() {
+				c.Assert(os.Chdir(cwd), qt.IsNil)
+			})
+			c.Assert(os.WriteFile("CODEOWNERS", nil, 0666), qt.IsNil)
+			c.Assert(os.WriteFile("README.tmpl", []byte("{{ .Resource }} {{ .Version }}"), 0666), qt.IsNil)
+			for _, version := range test.versions {
+				versionDir := filepath.Join("resources", "things", version)
+				c.Assert(os.MkdirAll(versionDir, 0777), qt.IsNil)
+				err := os.WriteFile(filepath.Join(versionDir, "spec.yaml"), []byte(`
+openapi: 3.0.3
+x-snyk-api-stability: beta
+info: {title: things, version: 3.0.0}
+paths:
+  /things:
+    get:
+      responses:
+        '204': {description: No things}
+`), 0666)
+				c.Assert(err, qt.IsNil)
+			}
+			proj, err := config.Load(bytes.NewBufferString(`
+versionScheme: ` + test.scheme + `
+apis:
+  things:
+    resources:
+      - path: resources
+`))
+			c.Assert(err, qt.IsNil)
+			generatorsConf, err := config.LoadGenerators(bytes.NewBufferString(`
+version-readme:
+  scope: version
+  filename: "{{ .Path }}/README"
+  template: README.tmpl
+`))
+			c.Assert(err, qt.IsNil)
+			genReadme, err := New(generatorsConf["version-readme"])
+			c.Assert(err, qt.IsNil)
+			resources, err := MapResources(proj)
+			c.Assert(err, qt.IsNil)
+
+			// Each version is generated in its own version directory.
+			files, err := genReadme.Execute(resources)
+			c.Assert(err, qt.IsNil)
+			c.Assert(files, qt.HasLen, len(test.versions))
+			for _, version := range test.versions {
+				readme, err := os.ReadFile(filepath.Join("resources", "things", version, "README"))
+				c.Assert(err, qt.IsNil)
+				c.Assert(string(readme), qt.Equals, "things "+version+"~beta")
+			}
+		})
+	}
+}
+
 func TestResourceScope(t *testing.T) {
 	c := qt.New(t)
 	for _, prefix := range []string{"", "{{.Cwd}}/"} {
@@ -192,6 +263,58 @@ export const getOrgsProjects = versions([
 	}
 }
 
+func TestAPIScope(t *testing.T) {
+	c := qt.New(t)
+	setup(c)
+
+	configBuf, err := os.ReadFile(".vervet.yaml")
+	c.Assert(err, qt.IsNil)
+	proj, err := config.Load(bytes.NewBuffer(configBuf))
+	c.Assert(err, qt.IsNil)
+
+	out := c.TempDir()
+	err = os.WriteFile(filepath.Join(out, "changelog.tmpl"), []byte(`
+{{ .API }} {{ .Version }} is {{ .Lifecycle }}, one of {{ len .Versions }} versions of {{ .Document.Info.Title }}
+{{ range .Operations -}}
+{{ .Method }} {{ .Path }} {{ .Resource }} {{ .Version }} {{ .Lifecycle }}
+{{- with .DeprecatedBy }} -> {{ . }}{{ end }}
+{{- with .SunsetEligible }} until {{ . }}{{ end }}
+{{ end }}`[1:]), 0666)
+	c.Assert(err, qt.IsNil)
+
+	generatorsConf, err := config.LoadGenerators(bytes.NewBufferString(`
+api-changelog:
+  scope: api
+  filename: "{{ .Here }}/{{ .API }}/{{ .Version }}/CHANGELOG"
+  template: "{{ .Here }}/changelog.tmpl"
+`))
+	c.Assert(err, qt.IsNil)
+	gen, err := New(generatorsConf["api-changelog"], Here(out))
+	c.Assert(err, qt.IsNil)
+
+	_, err = gen.Execute(ResourceMap{})
+	c.Assert(err, qt.ErrorMatches,
+		`generator scope "api" requires compiled API versions \(generators.api-changelog.scope\)`)
+
+	apis, err := MapAPIs(proj)
+	c.Assert(err, qt.IsNil)
+	files, err := gen.ExecuteAPIs(apis)
+	c.Assert(err, qt.IsNil)
+	c.Assert(files, qt.HasLen, 10)
+	c.Assert(files[0], qt.Equals, out+"/testdata/2021-06-01~experimental/CHANGELOG")
+	c.Assert(files[9], qt.Equals, out+"/testdata/2024-10-15/CHANGELOG")
+
+	changelog, err := os.ReadFile(out + "/testdata/2021-06-04~experimental/CHANGELOG")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(changelog), qt.Equals, `
+testdata 2021-06-04~experimental is sunset, one of 10 versions of Registry
+get /examples/hello-world/{id} hello-world 2021-06-01~experimental sunset -> 2021-06-07~experimental until 2021-06-08
+get /openapi   sunset
+get /openapi/{version}   sunset
+get /orgs/{orgId}/projects projects 2021-06-04~experimental sunset
+`[1:])
+}
+
 func TestFunctions(t *testing.T) {
 	c := qt.New(t)
 	setup(c)
@@ -0,0 +1,387 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/snyk/vervet/v8"
+)
+
+// goClientRuntime declares the client, response and error types of a
+// generated Go client, and sends its requests.
+const goClientRuntime = `
+// Client is a client of the API at Version.
+type Client struct {
+	// BaseURL is the URL of the API server, to which operation paths are
+	// appended.
+	BaseURL string
+
+	// HTTPClient sends requests. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client of the API served at the given base URL.
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: baseURL}
+}
+
+// Response describes a response from the API.
+type Response struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Header contains the response headers.
+	Header http.Header
+
+	// VersionServed is the version of the API which served the request, from
+	// the snyk-version-served response header.
+	VersionServed ServedVersion
+}
+
+// ServedVersion is a version of the API which served a request.
+type ServedVersion struct {
+	// Release is the release version, such as 2021-11-01, 2021-11-01.2 or
+	// 1.2.0.
+	Release string
+
+	// Stability is the stability of the version, such as beta or ga.
+	Stability string
+}
+
+// String returns the version as written in the snyk-version-served response
+// header.
+func (v ServedVersion) String() string {
+	if v.Stability == "ga" {
+		return v.Release
+	}
+	return v.Release + "~" + v.Stability
+}
+
+// servedVersionPattern matches a date, date-sequence or semantic version,
+// with an optional stability.
+var servedVersionPattern = regexp.MustCompile(
+	"^(\\d{4}-\\d{2}-\\d{2}(?:\\.[1-9]\\d*)?|\\d+\\.\\d+\\.\\d+)(?:~(wip|experimental|beta|ga))?$")
+
+// parseServedVersion parses the version in a snyk-version-served response
+// header, returning false if it is not a version. Versions without a
+// stability are GA.
+func parseServedVersion(s string) (ServedVersion, bool) {
+	m := servedVersionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return ServedVersion{}, false
+	}
+	if m[2] == "" {
+		m[2] = "ga"
+	}
+	return ServedVersion{Release: m[1], Stability: m[2]}, true
+}
+
+// Error is returned for responses with a non-2xx status.
+type Error struct {
+	// Method and Path identify the operation requested.
+	Method, Path string
+
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// VersionServed is the version of the API which served the request.
+	VersionServed ServedVersion
+
+	// Body is the response body.
+	Body []byte
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// do sends a request at Version, decoding a successful JSON response into
+// result, if not nil.
+func (c *Client) do(
+	ctx context.Context, method, path string, query url.Values, header http.Header,
+	contentType string, body, result any,
+) (*Response, error) {
+	query.Set("version", Version)
+	var reqBody io.Reader
+	if body != nil {
+		contents, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(contents)
+	}
+	reqURL := strings.TrimSuffix(c.BaseURL, "/") + path + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	httpResp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	contents, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp := &Response{StatusCode: httpResp.StatusCode, Header: httpResp.Header}
+	if served, ok := parseServedVersion(httpResp.Header.Get("snyk-version-served")); ok {
+		resp.VersionServed = served
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, &Error{
+			Method: method, Path: path, StatusCode: resp.StatusCode, VersionServed: resp.VersionServed, Body: contents,
+		}
+	}
+	if result != nil && len(bytes.TrimSpace(contents)) > 0 {
+		if err := json.Unmarshal(contents, result); err != nil {
+			return resp, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return resp, nil
+}
+`
+
+// GoClient returns a Go client package for a compiled version of an API,
+// with a method for each operation which requests the version of the
+// document. Requests and responses are typed with a type for each component
+// schema and inline JSON request and response body of the document.
+func GoClient(doc *openapi3.T, packageName string) (string, error) {
+	version, err := vervet.ExtensionString(doc.Extensions, vervet.ExtSnykApiVersion)
+	if err != nil {
+		return "", fmt.Errorf("not a compiled version: %w", err)
+	}
+	g := newGoGen("Client", "NewClient", "Response", "ServedVersion", "Error", "Version")
+	// The client only depends on the standard library.
+	delete(g.imports, "github.com/snyk/vervet/v8")
+	for _, path := range []string{
+		"bytes", "context", "encoding/json", "fmt", "io", "net/http", "net/url", "regexp", "strings",
+	} {
+		g.imports[path] = true
+	}
+	g.declareComponents(doc)
+	var methods bytes.Buffer
+	for _, op := range g.operations(doc) {
+		g.writeClientMethod(&methods, doc, op)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by vervet. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "// Package %s is a client of version %s of the API.\n", packageName, version)
+	fmt.Fprintf(&buf, "package %s\n\n", packageName)
+	g.writeImports(&buf)
+	fmt.Fprintf(&buf, "// Version is the version of the API requested by the client.\n")
+	fmt.Fprintf(&buf, "const Version = %q\n", version)
+	buf.WriteString(goClientRuntime)
+	buf.WriteString("\n")
+	for _, name := range g.order {
+		buf.WriteString(g.types[name])
+	}
+	buf.Write(methods.Bytes())
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return string(src), nil
+}
+
+// goParam is a parameter of a generated client method.
+type goParam struct {
+	field, typ string
+	*openapi3.Parameter
+}
+
+// clientParams returns the parameters of an operation, including those of
+// its path, other than the version requested, which is set by the client.
+func (g *goGen) clientParams(doc *openapi3.T, op goOperation) []goParam {
+	pathItem := doc.Paths.Value(op.path)
+	var params []goParam
+	fields := map[string]bool{}
+	seen := map[string]bool{}
+	operation := pathItem.GetOperation(op.method)
+	for _, ref := range append(append(openapi3.Parameters{}, operation.Parameters...), pathItem.Parameters...) {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		p := ref.Value
+		key := p.In + ":" + p.Name
+		if seen[key] || (p.In == openapi3.ParameterInQuery && p.Name == "version") || p.In == "cookie" {
+			continue
+		}
+		seen[key] = true
+		field := goName(p.Name)
+		if field == "" {
+			field = "Param"
+		}
+		for fields[field] {
+			field += "_"
+		}
+		fields[field] = true
+		typ := "string"
+		if p.Schema != nil && p.Schema.Value != nil && p.Schema.Value.Type.Is("object") {
+			typ = "map[string]string"
+		} else if p.Schema != nil {
+			typ = g.typeExpr(p.Schema, op.name+field, fmt.Sprintf("the %q parameter of %s", p.Name, op.name))
+		}
+		if !p.Required && !isNillable(typ) {
+			typ = "*" + typ
+		}
+		params = append(params, goParam{field: field, typ: typ, Parameter: p})
+	}
+	return params
+}
+
+// writeClientMethod writes the parameters type and client method of an
+// operation.
+func (g *goGen) writeClientMethod(buf *bytes.Buffer, doc *openapi3.T, op goOperation) {
+	operation := doc.Paths.Value(op.path).GetOperation(op.method)
+	params := g.clientParams(doc, op)
+	if len(params) > 0 {
+		fmt.Fprintf(buf, "// %sParams are the parameters of %s.\n", op.name, op.name)
+		fmt.Fprintf(buf, "type %sParams struct {\n", op.name)
+		for _, p := range params {
+			if p.Description != "" {
+				fmt.Fprintf(buf, "\t// %s\n", strings.ReplaceAll(strings.TrimSpace(p.Description), "\n", "\n\t// "))
+			}
+			fmt.Fprintf(buf, "\t%s %s\n", p.field, p.typ)
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	if len(params) > 0 {
+		args = append(args, "params "+op.name+"Params")
+	}
+	reqMediaType, reqType := g.bodyType(requestContent(operation), op.name+"Request", requestDoc(op.name))
+	if reqType != "" {
+		args = append(args, "body "+pointerTo(reqType))
+	}
+	_, respType := g.bodyType(successContent(operation), op.name+"Response", responseDoc(op.name))
+	results := "(*Response, error)"
+	if respType != "" {
+		results = "(" + pointerTo(respType) + ", *Response, error)"
+	}
+	fmt.Fprintf(buf, "// %s sends %s %s.\n", op.name, op.method, op.path)
+	fmt.Fprintf(buf, "func (c *Client) %s(%s) %s {\n", op.name, strings.Join(args, ", "), results)
+	fmt.Fprintf(buf, "\tpath := %s\n", pathExpr(op.path, params))
+	buf.WriteString("\tquery := url.Values{}\n\theader := http.Header{}\n")
+	for _, p := range params {
+		writeParam(buf, p)
+	}
+	body := "nil"
+	if reqType != "" {
+		// Avoid sending a typed nil body as JSON null.
+		buf.WriteString("\tvar reqBody any\n\tif body != nil {\n\t\treqBody = body\n\t}\n")
+		body = "reqBody"
+	}
+	result := "nil"
+	if respType != "" {
+		fmt.Fprintf(buf, "\tvar result %s\n", strings.TrimPrefix(pointerTo(respType), "*"))
+		result = "&result"
+	}
+	fmt.Fprintf(buf, "\tresp, err := c.do(ctx, %q, path, query, header, %q, %s, %s)\n",
+		op.method, reqMediaType, body, result)
+	if respType == "" {
+		buf.WriteString("\treturn resp, err\n}\n\n")
+		return
+	}
+	buf.WriteString("\tif err != nil {\n\t\treturn nil, resp, err\n\t}\n")
+	if strings.HasPrefix(pointerTo(respType), "*") {
+		buf.WriteString("\treturn &result, resp, nil\n}\n\n")
+	} else {
+		buf.WriteString("\treturn result, resp, nil\n}\n\n")
+	}
+}
+
+// bodyType returns the media type and Go type of a JSON request or response
+// body, or empty strings if there is none. Inline bodies are documented as
+// doc.
+func (g *goGen) bodyType(content openapi3.Content, name, doc string) (string, string) {
+	for _, mediaType := range sortedKeys(content) {
+		if isJSONMediaType(mediaType) && content[mediaType].Schema != nil {
+			return mediaType, g.typeExpr(content[mediaType].Schema, name, doc)
+		}
+	}
+	return "", ""
+}
+
+// pointerTo returns the type of a pointer to the given type, unless it may
+// already be nil.
+func pointerTo(typ string) string {
+	if isNillable(typ) {
+		return typ
+	}
+	return "*" + typ
+}
+
+// pathExpr returns a Go expression of an operation path, with its path
+// parameters escaped.
+func pathExpr(path string, params []goParam) string {
+	fields := map[string]string{}
+	for _, p := range params {
+		if p.In == openapi3.ParameterInPath {
+			fields[p.Name] = p.field
+		}
+	}
+	var parts []string
+	for path != "" {
+		start := strings.Index(path, "{")
+		end := strings.Index(path, "}")
+		if start < 0 || end < start {
+			parts = append(parts, fmt.Sprintf("%q", path))
+			break
+		}
+		if start > 0 {
+			parts = append(parts, fmt.Sprintf("%q", path[:start]))
+		}
+		if field, ok := fields[path[start+1:end]]; ok {
+			parts = append(parts, fmt.Sprintf("url.PathEscape(fmt.Sprint(params.%s))", field))
+		} else {
+			parts = append(parts, fmt.Sprintf("%q", path[start:end+1]))
+		}
+		path = path[end+1:]
+	}
+	if len(parts) == 0 {
+		return `""`
+	}
+	return strings.Join(parts, " + ")
+}
+
+// writeParam writes the statements which set a query or header parameter.
+func writeParam(buf *bytes.Buffer, p goParam) {
+	var target string
+	switch p.In {
+	case openapi3.ParameterInQuery:
+		target = "query"
+	case openapi3.ParameterInHeader:
+		target = "header"
+	default:
+		return
+	}
+	value := "params." + p.field
+	switch {
+	case p.typ == "map[string]string":
+		fmt.Fprintf(buf, "\tfor k, v := range %s {\n\t\t%s.Set(%q+\"[\"+k+\"]\", v)\n\t}\n", value, target, p.Name)
+	case strings.HasPrefix(p.typ, "[]"):
+		fmt.Fprintf(buf, "\tfor _, v := range %s {\n\t\t%s.Add(%q, fmt.Sprint(v))\n\t}\n", value, target, p.Name)
+	case strings.HasPrefix(p.typ, "*"):
+		fmt.Fprintf(buf, "\tif %s != nil {\n\t\t%s.Set(%q, fmt.Sprint(*%s))\n\t}\n", value, target, p.Name, value)
+	default:
+		fmt.Fprintf(buf, "\t%s.Set(%q, fmt.Sprint(%s))\n", target, p.Name, value)
+	}
+}
@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const goClientSpec = `
+openapi: 3.0.3
+x-snyk-api-version: 2021-11-01~beta
+info:
+  title: things
+  version: 3.0.0
+paths:
+  /orgs/{org_id}/things:
+    parameters:
+      - name: org_id
+        in: path
+        required: true
+        schema:
+          type: string
+      - name: version
+        in: query
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: listThings
+      parameters:
+        - name: limit
+          in: query
+          description: Number of things to return.
+          schema:
+            type: integer
+        - name: filter
+          in: query
+          style: deepObject
+          schema:
+            type: object
+      responses:
+        '200':
+          description: Things
+          content:
+            application/vnd.api+json:
+              schema:
+                type: array
+                items: { $ref: '#/components/schemas/Thing' }
+    post:
+      operationId: createThing
+      requestBody:
+        content:
+          application/json:
+            schema: { $ref: '#/components/schemas/Thing' }
+      responses:
+        '204':
+          description: Created
+components:
+  schemas:
+    Thing:
+      type: object
+      properties:
+        name:
+          type: string
+      required: [name]
+`
+
+func TestGoClient(t *testing.T) {
+	c := qt.New(t)
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(goClientSpec))
+	c.Assert(err, qt.IsNil)
+	src, err := GoClient(doc, "things")
+	c.Assert(err, qt.IsNil)
+	for _, snippet := range []string{
+		"package things\n",
+		`const Version = "2021-11-01~beta"`,
+		"type Thing struct {\n\tName string `json:\"name\"`\n}",
+		"type ListThingsParams struct {\n" +
+			"\t// Number of things to return.\n" +
+			"\tLimit  *int64\n" +
+			"\tFilter map[string]string\n" +
+			"\tOrgID  string\n" +
+			"}",
+		"func (c *Client) ListThings(ctx context.Context, params ListThingsParams) ([]Thing, *Response, error) {",
+		`path := "/orgs/" + url.PathEscape(fmt.Sprint(params.OrgID)) + "/things"`,
+		`query.Set("limit", fmt.Sprint(*params.Limit))`,
+		`query.Set("filter"+"["+k+"]", v)`,
+		"func (c *Client) CreateThing(ctx context.Context, params CreateThingParams, body *Thing) (*Response, error) {",
+		`resp, err := c.do(ctx, "POST", path, query, header, "application/json", reqBody, nil)`,
+	} {
+		c.Assert(src, qt.Contains, snippet)
+	}
+	c.Assert(src, qt.Not(qt.Contains), "Version string")
+	c.Assert(src, qt.Not(qt.Contains), "github.com/snyk/vervet")
+}
+
+func TestGoClientNotCompiled(t *testing.T) {
+	c := qt.New(t)
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.3
+info:
+  title: things
+  version: 3.0.0
+paths: {}
+`))
+	c.Assert(err, qt.IsNil)
+	_, err = GoClient(doc, "things")
+	c.Assert(err, qt.ErrorMatches, `not a compiled version: .*`)
+}
+
+// goClientRoundTripTest is a test of the client generated from goClientSpec,
+// which sends requests to a test server.
+const goClientRoundTripTest = `package things
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("version"); got != "2021-11-01~beta" {
+			t.Errorf("version requested %q", got)
+		}
+		w.Header().Set("snyk-version-served", "2021-11-01.2~beta")
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /orgs/org%2F1/things":
+			if got := r.URL.Query().Get("limit"); got != "2" {
+				t.Errorf("limit %q", got)
+			}
+			if got := r.URL.Query().Get("filter[name]"); got != "thing" {
+				t.Errorf("filter %q", got)
+			}
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			_, _ = w.Write([]byte("[{\"name\":\"one\"},{\"name\":\"two\"}]"))
+		case "POST /orgs/org1/things":
+			contents, _ := io.ReadAll(r.Body)
+			var thing Thing
+			if err := json.Unmarshal(contents, &thing); err != nil || thing.Name != "new" {
+				t.Errorf("request body %q", contents)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+	c := NewClient(s.URL)
+	ctx := context.Background()
+
+	limit := int64(2)
+	things, resp, err := c.ListThings(ctx, ListThingsParams{
+		OrgID: "org/1", Limit: &limit, Filter: map[string]string{"name": "thing"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(things) != 2 || things[0].Name != "one" || things[1].Name != "two" {
+		t.Errorf("things %+v", things)
+	}
+	if resp.VersionServed.String() != "2021-11-01.2~beta" || resp.VersionServed.Stability != "beta" {
+		t.Errorf("version served %+v", resp.VersionServed)
+	}
+
+	resp, err = c.CreateThing(ctx, CreateThingParams{OrgID: "org1"}, &Thing{Name: "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status %d", resp.StatusCode)
+	}
+
+	_, err = c.CreateThing(ctx, CreateThingParams{OrgID: "org2"}, &Thing{Name: "new"})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("error %v", err)
+	}
+}
+`
+
+func TestGoClientRoundTrip(t *testing.T) {
+	c := qt.New(t)
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		c.Skip("go tool not found")
+	}
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(goClientSpec))
+	c.Assert(err, qt.IsNil)
+	src, err := GoClient(doc, "things")
+	c.Assert(err, qt.IsNil)
+	dir := c.TempDir()
+	for name, contents := range map[string]string{
+		"go.mod":         "module example.com/things\n\ngo 1.21\n",
+		"client.go":      src,
+		"client_test.go": goClientRoundTripTest,
+	} {
+		c.Assert(os.WriteFile(filepath.Join(dir, name), []byte(contents), 0666), qt.IsNil)
+	}
+
+	// The generated client only depends on the standard library, so it is
+	// built without downloading any modules.
+	cmd := exec.Command(goTool, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off", "GOPROXY=off", "GOTOOLCHAIN=local")
+	output, err := cmd.CombinedOutput()
+	c.Assert(err, qt.IsNil, qt.Commentf("%s", output))
+}
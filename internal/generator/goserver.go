@@ -0,0 +1,488 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/config"
+)
+
+// builtinGenerators are the contents templates of the generators built into
+// vervet, by name.
+var builtinGenerators = map[string]string{
+	config.GeneratorBuiltinGoServer: `{{ goServer . }}`,
+}
+
+// goInitialisms are words written in upper case in Go identifiers.
+var goInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "JSON": true,
+	"SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// GoServer returns Go server code for a resource version: a type for each of
+// its component schemas and the inline JSON request and response bodies of
+// its operations, a Handler interface with a method for each operation, and
+// the versionware.VersionHandler of each operation.
+func GoServer(scope *VersionScope) (string, error) {
+	g := newGoGen("Handler", "Version", "VersionHandlers")
+	doc := scope.ResourceVersion.Document
+	g.declareComponents(doc.T)
+	ops := g.operations(doc.T)
+	if len(ops) > 0 {
+		g.imports["net/http"] = true
+		g.imports["github.com/snyk/vervet/v8/versionware"] = true
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by vervet. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", goPackageName(scope.ResourceVersion.Version))
+	g.writeImports(&buf)
+	fmt.Fprintf(&buf, "// Version is the resource release version of handlers in this package.\n")
+	fmt.Fprintf(&buf, "var Version = %s\n\n", goVersionExpr(scope.ResourceVersion.Version))
+	for _, name := range g.order {
+		buf.WriteString(g.types[name])
+	}
+	if len(ops) > 0 {
+		writeHandlers(&buf, scope.ResourceVersion.Name, ops)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return string(src), nil
+}
+
+// goGen accumulates the type declarations and imports of generated Go code.
+type goGen struct {
+	types    map[string]string
+	order    []string
+	imports  map[string]bool
+	reserved map[string]bool
+	inline   map[*openapi3.Schema]string
+}
+
+func newGoGen(reserved ...string) *goGen {
+	g := &goGen{
+		types:    map[string]string{},
+		imports:  map[string]bool{"github.com/snyk/vervet/v8": true},
+		reserved: map[string]bool{},
+		inline:   map[*openapi3.Schema]string{},
+	}
+	for _, name := range reserved {
+		g.reserved[name] = true
+	}
+	return g
+}
+
+// declareComponents declares a type for each component schema of a
+// document.
+func (g *goGen) declareComponents(doc *openapi3.T) {
+	if doc.Components == nil {
+		return
+	}
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		g.declare(g.typeName(goName(name)), doc.Components.Schemas[name].Value, componentDoc)
+	}
+}
+
+// componentDoc documents the types declared for component and referenced
+// schemas.
+const componentDoc = "generated from the OpenAPI schema of the same name"
+
+// typeName returns the name of the type declared for a schema, which is
+// suffixed if it is reserved for other declarations in the generated code.
+func (g *goGen) typeName(name string) string {
+	if g.reserved[name] {
+		return name + "Schema"
+	}
+	return name
+}
+
+// goOperation is an operation handled by the generated code.
+type goOperation struct {
+	name, method, path string
+}
+
+// operations returns the operations of the document in path and method
+// order, declaring types for their inline request and response bodies.
+func (g *goGen) operations(doc *openapi3.T) []goOperation {
+	if doc.Paths == nil {
+		return nil
+	}
+	var ops []goOperation
+	names := map[string]bool{}
+	for _, path := range sortedKeys(doc.Paths.Map()) {
+		pathOps := MapPathOperations(doc.Paths.Value(path))
+		for _, method := range sortedKeys(pathOps) {
+			op := pathOps[method]
+			name := goName(op.OperationID)
+			if name == "" {
+				name = goName(method + " " + path)
+			}
+			for names[name] {
+				name += "_"
+			}
+			names[name] = true
+			if body := jsonSchema(requestContent(op)); body != nil {
+				g.typeExpr(body, name+"Request", requestDoc(name))
+			}
+			if body := jsonSchema(successContent(op)); body != nil {
+				g.typeExpr(body, name+"Response", responseDoc(name))
+			}
+			ops = append(ops, goOperation{name: name, method: strings.ToUpper(method), path: path})
+		}
+	}
+	return ops
+}
+
+// requestDoc documents the type declared for the inline JSON request body of
+// an operation.
+func requestDoc(op string) string {
+	return "the JSON request body of the " + op + " operation"
+}
+
+// responseDoc documents the type declared for the inline JSON response body
+// of an operation.
+func responseDoc(op string) string {
+	return "the JSON response body of the " + op + " operation"
+}
+
+// declare declares a named type for a schema, unless already declared. The
+// type is documented with the schema description, or otherwise as doc.
+func (g *goGen) declare(name string, s *openapi3.Schema, doc string) {
+	if _, ok := g.types[name]; ok {
+		return
+	}
+	// Reserve the name first, so that recursive schemas refer to it.
+	g.types[name] = ""
+	g.order = append(g.order, name)
+
+	var buf bytes.Buffer
+	if s != nil && s.Description != "" {
+		for _, line := range strings.Split(strings.TrimSpace(s.Description), "\n") {
+			fmt.Fprintf(&buf, "// %s\n", line)
+		}
+	} else {
+		fmt.Fprintf(&buf, "// %s is %s.\n", name, doc)
+	}
+	if isStruct(s) {
+		fmt.Fprintf(&buf, "type %s struct {\n", name)
+		g.writeFields(&buf, name, s)
+		buf.WriteString("}\n\n")
+	} else {
+		fmt.Fprintf(&buf, "type %s %s\n\n", name, g.schemaExpr(s, name+"Item", "an item of "+name))
+	}
+	g.types[name] = buf.String()
+}
+
+// writeFields writes the fields of a struct type declared for an object
+// schema, or for the schemas combined with allOf.
+func (g *goGen) writeFields(buf *bytes.Buffer, typeName string, s *openapi3.Schema) {
+	props := openapi3.Schemas{}
+	required := map[string]bool{}
+	for _, part := range append([]*openapi3.Schema{s}, allOfSchemas(s)...) {
+		for prop, ref := range part.Properties {
+			props[prop] = ref
+		}
+		for _, prop := range part.Required {
+			required[prop] = true
+		}
+	}
+	fieldNames := map[string]bool{}
+	for _, prop := range sortedKeys(props) {
+		fieldName := goName(prop)
+		if fieldName == "" {
+			fieldName = "Field"
+		}
+		for fieldNames[fieldName] {
+			fieldName += "_"
+		}
+		fieldNames[fieldName] = true
+		fieldType := g.typeExpr(props[prop], typeName+fieldName,
+			fmt.Sprintf("the %q property of %s", prop, typeName))
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+			if !isNillable(fieldType) {
+				fieldType = "*" + fieldType
+			}
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:%s`\n", fieldName, fieldType, strconv.Quote(tag))
+	}
+}
+
+// typeExpr returns the Go type expression of a schema reference. Referenced
+// schemas are declared as types named after the reference. Inline object
+// schemas are declared once as types with the name given, documented as doc,
+// or with a suffixed name if the name is already declared.
+func (g *goGen) typeExpr(ref *openapi3.SchemaRef, name, doc string) string {
+	if ref == nil || ref.Value == nil {
+		return "any"
+	}
+	if ref.Ref != "" {
+		if refName := g.typeName(goName(refTypeName(ref.Ref))); refName != "" {
+			g.declare(refName, ref.Value, componentDoc)
+			return refName
+		}
+	}
+	if isStruct(ref.Value) {
+		if inlineName, ok := g.inline[ref.Value]; ok {
+			return inlineName
+		}
+		name = g.inlineName(name)
+		g.inline[ref.Value] = name
+		g.declare(name, ref.Value, doc)
+		return name
+	}
+	return g.schemaExpr(ref.Value, name, doc)
+}
+
+// inlineName returns the name of the type declared for an inline schema,
+// which is suffixed with "Body", and then underscores, if it is already
+// declared for another schema, such as a component schema of the same name.
+func (g *goGen) inlineName(name string) string {
+	declared := func(name string) bool {
+		_, ok := g.types[name]
+		return ok || g.reserved[name]
+	}
+	if !declared(name) {
+		return name
+	}
+	name += "Body"
+	for declared(name) {
+		name += "_"
+	}
+	return name
+}
+
+// schemaExpr returns the Go type expression of a schema which is not
+// declared as a struct.
+func (g *goGen) schemaExpr(s *openapi3.Schema, name, doc string) string {
+	switch {
+	case s == nil:
+		return "any"
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0 || len(s.AllOf) > 0:
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	case s.Type.Is("array"):
+		return "[]" + g.typeExpr(s.Items, name, "an item of "+doc)
+	case s.Type.Is("object"):
+		if s.AdditionalProperties.Schema != nil {
+			return "map[string]" + g.typeExpr(s.AdditionalProperties.Schema, name, "a value of "+doc)
+		}
+		return "map[string]any"
+	case s.Type.Is("string"):
+		if s.Format == "date-time" {
+			g.imports["time"] = true
+			return "time.Time"
+		}
+		return "string"
+	case s.Type.Is("integer"):
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case s.Type.Is("number"):
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case s.Type.Is("boolean"):
+		return "bool"
+	}
+	return "any"
+}
+
+func (g *goGen) writeImports(buf *bytes.Buffer) {
+	var std, other []string
+	for path := range g.imports {
+		if strings.Contains(path, ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	buf.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(buf, "\t%q\n", path)
+	}
+	if len(std) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, path := range other {
+		fmt.Fprintf(buf, "\t%q\n", path)
+	}
+	buf.WriteString(")\n\n")
+}
+
+// writeHandlers writes the Handler interface and the version handlers of
+// each operation.
+func writeHandlers(buf *bytes.Buffer, resource string, ops []goOperation) {
+	fmt.Fprintf(buf, "// Handler handles the operations of the %s resource at this version.\n", resource)
+	buf.WriteString("type Handler interface {\n")
+	for _, op := range ops {
+		fmt.Fprintf(buf, "\t// %s handles %s %s.\n", op.name, op.method, op.path)
+		fmt.Fprintf(buf, "\t%s(w http.ResponseWriter, r *http.Request)\n", op.name)
+	}
+	buf.WriteString("}\n\n")
+	buf.WriteString(`// VersionHandlers returns the version handler of each operation, keyed by
+// its method and path, such as "GET /things/{id}", for routing requests with
+// versionware.NewHandler.
+func VersionHandlers(h Handler) map[string]versionware.VersionHandler {
+	return map[string]versionware.VersionHandler{
+`)
+	for _, op := range ops {
+		fmt.Fprintf(buf, "\t\t%q: {Version: Version, Handler: http.HandlerFunc(h.%s)},\n",
+			op.method+" "+op.path, op.name)
+	}
+	buf.WriteString("\t}\n}\n")
+}
+
+// requestContent returns the content of an operation's request body.
+func requestContent(op *openapi3.Operation) openapi3.Content {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return nil
+	}
+	return op.RequestBody.Value.Content
+}
+
+// successContent returns the content of an operation's first successful
+// response.
+func successContent(op *openapi3.Operation) openapi3.Content {
+	if op.Responses == nil {
+		return nil
+	}
+	responses := op.Responses.Map()
+	for _, code := range sortedKeys(responses) {
+		if strings.HasPrefix(code, "2") && responses[code].Value != nil {
+			return responses[code].Value.Content
+		}
+	}
+	return nil
+}
+
+// jsonSchema returns the schema of the first JSON media type in content.
+func jsonSchema(content openapi3.Content) *openapi3.SchemaRef {
+	for _, mediaType := range sortedKeys(content) {
+		if isJSONMediaType(mediaType) && content[mediaType].Schema != nil {
+			return content[mediaType].Schema
+		}
+	}
+	return nil
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// isStruct returns whether a schema is declared as a struct: an object with
+// properties, or the combination of such objects with allOf.
+func isStruct(s *openapi3.Schema) bool {
+	if s == nil || len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return false
+	}
+	if len(s.AllOf) > 0 {
+		for _, part := range allOfSchemas(s) {
+			if !isStruct(part) {
+				return false
+			}
+		}
+		return true
+	}
+	return len(s.Properties) > 0 && (s.Type == nil || s.Type.Is("object"))
+}
+
+func allOfSchemas(s *openapi3.Schema) []*openapi3.Schema {
+	var parts []*openapi3.Schema
+	for _, ref := range s.AllOf {
+		if ref != nil && ref.Value != nil {
+			parts = append(parts, ref.Value)
+		}
+	}
+	return parts
+}
+
+// isNillable returns whether a Go type expression may be nil, so that it
+// need not be a pointer to be omitted.
+func isNillable(expr string) bool {
+	return expr == "any" || expr == "json.RawMessage" ||
+		strings.HasPrefix(expr, "[]") || strings.HasPrefix(expr, "map[")
+}
+
+// refTypeName returns the name of the schema a reference refers to: the last
+// element of its JSON pointer, or the name of the file referenced.
+func refTypeName(ref string) string {
+	if i := strings.LastIndex(ref, "/"); i >= 0 && strings.Contains(ref, "#") {
+		return ref[i+1:]
+	}
+	ref = ref[strings.LastIndex(ref, "/")+1:]
+	if i := strings.Index(ref, "."); i >= 0 {
+		ref = ref[:i]
+	}
+	return ref
+}
+
+// goName returns an exported Go identifier for a name such as an operation
+// ID or property name, such as "ThingsID" for "things_id" or "thingsId".
+func goName(s string) string {
+	words := splitWords(s)
+	var sb strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); goInitialisms[upper] {
+			sb.WriteString(upper)
+		} else {
+			sb.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+	name := sb.String()
+	if name != "" && unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// goPackageName returns the name of the Go package for a resource version,
+// such as "release_2021_11_01".
+func goPackageName(v vervet.Version) string {
+	v.Stability = vervet.StabilityGA
+	return "release_" + strings.NewReplacer("-", "_", ".", "_").Replace(v.String())
+}
+
+// goVersionExpr returns a Go expression of the given version.
+func goVersionExpr(v vervet.Version) string {
+	scheme := ""
+	switch {
+	case v.Date.IsZero():
+		scheme = "SemverScheme"
+	case v.Sequence > 0:
+		scheme = "DateSequenceScheme"
+	default:
+		return fmt.Sprintf("vervet.MustParseVersion(%q)", v.String())
+	}
+	return fmt.Sprintf(`func() vervet.Version {
+	v, err := vervet.%s.ParseVersion(%q)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}()`, scheme, v.String())
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,244 @@
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/snyk/vervet/v8/config"
+)
+
+const goServerSpec = `
+openapi: 3.0.3
+x-snyk-api-stability: experimental
+info:
+  title: things
+  version: 3.0.0
+paths:
+  /things:
+    post:
+      operationId: createThing
+      requestBody:
+        content:
+          application/json:
+            schema: { $ref: '../../common.yaml#/schemas/ThingAttributes' }
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema: { $ref: '#/components/schemas/ThingResponse' }
+    get:
+      operationId: listThings
+      responses:
+        '200':
+          description: Things
+          content:
+            application/vnd.api+json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: array
+                    items: { $ref: '#/components/schemas/ThingResponse' }
+                  next_url:
+                    type: string
+                required: [data]
+  /things/{thing_id}:
+    delete:
+      parameters:
+        - name: thing_id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: Deleted
+components:
+  schemas:
+    ListThingsResponse:
+      type: object
+      properties:
+        count:
+          type: integer
+    ThingResponse:
+      description: A thing.
+      type: object
+      properties:
+        id:
+          type: string
+        created:
+          type: string
+          format: date-time
+        attributes: { $ref: '../../common.yaml#/schemas/ThingAttributes' }
+        tags:
+          type: object
+          additionalProperties:
+            type: string
+      required: [id, attributes]
+`
+
+const goServerCommon = `
+schemas:
+  ThingAttributes:
+    type: object
+    properties:
+      name:
+        type: string
+      strangeness:
+        type: integer
+        format: int32
+    required: [name]
+`
+
+func TestGoServer(t *testing.T) {
+	c := qt.New(t)
+	dir := c.TempDir()
+	c.Assert(os.MkdirAll(filepath.Join(dir, "resources", "things", "2021-11-01"), 0777), qt.IsNil)
+	for path, contents := range map[string]string{
+		"resources/common.yaml":                 goServerCommon,
+		"resources/things/2021-11-01/spec.yaml": goServerSpec,
+		"CODEOWNERS":                            "* @snyk/things\n",
+	} {
+		c.Assert(os.WriteFile(filepath.Join(dir, path), []byte(contents), 0666), qt.IsNil)
+	}
+	cwd, err := os.Getwd()
+	c.Assert(err, qt.IsNil)
+	c.Assert(os.Chdir(dir), qt.IsNil)
+	c.Cleanup(func() {
+		c.Assert(os.Chdir(cwd), qt.IsNil)
+	})
+
+	proj, err := config.Load(bytes.NewBufferString(`
+apis:
+  test:
+    resources:
+      - path: resources
+        excludes: [resources/common.yaml]
+generators:
+  server:
+    builtin: go-server
+`))
+	c.Assert(err, qt.IsNil)
+	c.Assert(proj.Generators["server"].Filename, qt.Equals, "{{ .Path }}/server_gen.go")
+	gen, err := New(proj.Generators["server"], Force(true))
+	c.Assert(err, qt.IsNil)
+	resources, err := MapResources(proj)
+	c.Assert(err, qt.IsNil)
+	files, err := gen.Execute(resources)
+	c.Assert(err, qt.IsNil)
+	c.Assert(files, qt.DeepEquals, []string{"resources/things/2021-11-01/server_gen.go"})
+
+	contents, err := os.ReadFile(files[0])
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(contents), qt.Equals, `// Code generated by vervet. DO NOT EDIT.
+
+package release_2021_11_01
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/versionware"
+)
+
+// Version is the resource release version of handlers in this package.
+var Version = vervet.MustParseVersion("2021-11-01~experimental")
+
+// ListThingsResponse is generated from the OpenAPI schema of the same name.
+type ListThingsResponse struct {
+	Count *int64 `+"`"+`json:"count,omitempty"`+"`"+`
+}
+
+// ThingAttributes is generated from the OpenAPI schema of the same name.
+type ThingAttributes struct {
+	Name        string `+"`"+`json:"name"`+"`"+`
+	Strangeness *int32 `+"`"+`json:"strangeness,omitempty"`+"`"+`
+}
+
+// A thing.
+type ThingResponse struct {
+	Attributes ThingAttributes   `+"`"+`json:"attributes"`+"`"+`
+	Created    *time.Time        `+"`"+`json:"created,omitempty"`+"`"+`
+	ID         string            `+"`"+`json:"id"`+"`"+`
+	Tags       map[string]string `+"`"+`json:"tags,omitempty"`+"`"+`
+}
+
+// ListThingsResponseBody is the JSON response body of the ListThings operation.
+type ListThingsResponseBody struct {
+	Data    []ThingResponse `+"`"+`json:"data"`+"`"+`
+	NextURL *string         `+"`"+`json:"next_url,omitempty"`+"`"+`
+}
+
+// Handler handles the operations of the things resource at this version.
+type Handler interface {
+	// ListThings handles GET /things.
+	ListThings(w http.ResponseWriter, r *http.Request)
+	// CreateThing handles POST /things.
+	CreateThing(w http.ResponseWriter, r *http.Request)
+	// DeleteThingsThingID handles DELETE /things/{thing_id}.
+	DeleteThingsThingID(w http.ResponseWriter, r *http.Request)
+}
+
+// VersionHandlers returns the version handler of each operation, keyed by
+// its method and path, such as "GET /things/{id}", for routing requests with
+// versionware.NewHandler.
+func VersionHandlers(h Handler) map[string]versionware.VersionHandler {
+	return map[string]versionware.VersionHandler{
+		"GET /things":               {Version: Version, Handler: http.HandlerFunc(h.ListThings)},
+		"POST /things":              {Version: Version, Handler: http.HandlerFunc(h.CreateThing)},
+		"DELETE /things/{thing_id}": {Version: Version, Handler: http.HandlerFunc(h.DeleteThingsThingID)},
+	}
+}
+`)
+}
+
+func TestGoName(t *testing.T) {
+	c := qt.New(t)
+	for in, out := range map[string]string{
+		"getThings":    "GetThings",
+		"thing_id":     "ThingID",
+		"thingsId":     "ThingsID",
+		"JsonApi":      "JSONAPI",
+		"tags.include": "TagsInclude",
+		"2fa":          "X2fa",
+		"":             "",
+	} {
+		c.Assert(goName(in), qt.Equals, out, qt.Commentf("%q", in))
+	}
+}
+
+func TestBuiltinConfig(t *testing.T) {
+	c := qt.New(t)
+	for _, test := range []struct {
+		conf, err string
+	}{{
+		conf: `
+server:
+  builtin: go-client
+`,
+		err: `unknown builtin generator "go-client" \(generators.server.builtin\)`,
+	}, {
+		conf: `
+server:
+  builtin: go-server
+  scope: resource
+`,
+		err: `builtin generator "go-server" requires scope "version" \(generators.server.scope\)`,
+	}, {
+		conf: `
+server:
+  builtin: go-server
+  template: server.go.tmpl
+`,
+		err: `builtin generators do not use a template or files \(generators.server\)`,
+	}} {
+		_, err := config.LoadGenerators(bytes.NewBufferString(test.conf))
+		c.Assert(err, qt.ErrorMatches, test.err)
+	}
+}
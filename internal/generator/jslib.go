@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/dop251/goja"
+)
+
+// vervetModule returns the "vervet" module of OpenAPI helpers available to
+// generator JavaScript.
+//
+// OpenAPI objects passed to the helpers, whether from templates or scripts,
+// are converted to plain JavaScript objects with the property names of the
+// OpenAPI specification.
+func (rt *jsRuntime) vervetModule() (*goja.Object, error) {
+	if rt.vervet != nil {
+		return rt.vervet, nil
+	}
+	module := rt.vm.NewObject()
+	for name, fn := range map[string]any{
+		"toJSON":     jsPlain,
+		"walkSchema": rt.walkSchema,
+		"resolveRef": jsResolveRef,
+		"camelCase":  camelCase,
+		"pascalCase": pascalCase,
+		"snakeCase":  snakeCase,
+		"kebabCase":  kebabCase,
+		"words":      splitWords,
+	} {
+		if err := module.Set(name, fn); err != nil {
+			return nil, err
+		}
+	}
+	rt.vervet = module
+	return module, nil
+}
+
+// jsPlain converts a value to plain data, as it would be represented in
+// JSON. OpenAPI objects are converted to the structure of the OpenAPI
+// specification.
+func jsPlain(v any) (any, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var plain any
+	if err := json.Unmarshal(buf, &plain); err != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+// walkSchema calls visit with each schema in a schema, and the JSON pointer
+// of each relative to it: properties, items, additional properties and the
+// schemas it composes. Schemas within a schema for which visit returns false
+// are not visited. References are not followed.
+func (rt *jsRuntime) walkSchema(schema any, visit goja.Callable) error {
+	plain, err := jsPlain(schema)
+	if err != nil {
+		return err
+	}
+	return rt.walk(plain, "#", visit)
+}
+
+func (rt *jsRuntime) walk(schema any, pointer string, visit goja.Callable) error {
+	obj, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+	result, err := visit(goja.Undefined(), rt.vm.ToValue(obj), rt.vm.ToValue(pointer))
+	if err != nil {
+		return err
+	}
+	if result.Equals(rt.vm.ToValue(false)) {
+		return nil
+	}
+	if props, ok := obj["properties"].(map[string]any); ok {
+		for _, name := range sortedKeys(props) {
+			if err := rt.walk(props[name], pointer+"/properties/"+escapePointer(name), visit); err != nil {
+				return err
+			}
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		if err := rt.walk(obj[key], pointer+"/"+key, visit); err != nil {
+			return err
+		}
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		items, _ := obj[key].([]any)
+		for i := range items {
+			if err := rt.walk(items[i], fmt.Sprintf("%s/%s/%d", pointer, key, i), visit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsResolveRef returns the value in a document referenced by a local
+// reference, such as "#/components/schemas/Thing".
+func jsResolveRef(doc any, ref string) (any, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("not a local reference: %q", ref)
+	}
+	pointer, err := url.PathUnescape(strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return nil, err
+	}
+	value, err := jsPlain(doc)
+	if err != nil {
+		return nil, err
+	}
+	if pointer == "" {
+		return value, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := value.(type) {
+		case map[string]any:
+			var ok bool
+			if value, ok = v[token]; !ok {
+				return nil, fmt.Errorf("reference not found: %q", ref)
+			}
+		case []any:
+			var i int
+			if _, err := fmt.Sscanf(token, "%d", &i); err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("reference not found: %q", ref)
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("reference not found: %q", ref)
+		}
+	}
+	return value, nil
+}
+
+// escapePointer escapes a JSON pointer token.
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// splitWords splits an identifier into words, at non-alphanumeric
+// characters and changes of case, such as "thing_id", "thingId" or
+// "HTTPServer".
+func splitWords(s string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			flush()
+			word = append(word, r)
+		case unicode.IsUpper(r) && i > 0 && unicode.IsUpper(runes[i-1]) &&
+			i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// The last capital of an acronym begins the next word.
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// camelCase converts an identifier to camelCase.
+func camelCase(s string) string {
+	var sb strings.Builder
+	for i, w := range splitWords(s) {
+		if i == 0 {
+			sb.WriteString(strings.ToLower(w))
+		} else {
+			sb.WriteString(capitalizeWord(w))
+		}
+	}
+	return sb.String()
+}
+
+// pascalCase converts an identifier to PascalCase.
+func pascalCase(s string) string {
+	var sb strings.Builder
+	for _, w := range splitWords(s) {
+		sb.WriteString(capitalizeWord(w))
+	}
+	return sb.String()
+}
+
+// snakeCase converts an identifier to snake_case.
+func snakeCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "_"))
+}
+
+// kebabCase converts an identifier to kebab-case.
+func kebabCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "-"))
+}
+
+func capitalizeWord(w string) string {
+	runes := []rune(strings.ToLower(w))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
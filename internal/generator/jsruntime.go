@@ -0,0 +1,300 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"runtime/metrics"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+const (
+	// DefaultFunctionTimeout is how long a generator's JavaScript may run,
+	// when loaded or each time one of its functions is called, unless
+	// configured otherwise with FunctionTimeout.
+	DefaultFunctionTimeout = 10 * time.Second
+
+	// DefaultFunctionMemoryLimit is roughly how much memory a generator's
+	// JavaScript may allocate, when loaded or each time one of its functions
+	// is called, unless configured otherwise with FunctionMemoryLimit. The
+	// limit is best-effort: see jsRuntime.guard.
+	DefaultFunctionMemoryLimit = 256 << 20
+
+	// memoryCheckInterval is how often memory use is checked while
+	// JavaScript runs.
+	memoryCheckInterval = 10 * time.Millisecond
+
+	// heapMetric is the runtime metric of memory allocated on the heap.
+	heapMetric = "/memory/classes/heap/objects:bytes"
+)
+
+var (
+	// ErrFunctionTimeout is returned when a generator's JavaScript runs for
+	// longer than allowed.
+	ErrFunctionTimeout = errors.New("timed out")
+
+	// ErrFunctionMemoryLimit is returned when a generator's JavaScript
+	// allocates more memory than allowed.
+	ErrFunctionMemoryLimit = errors.New("memory limit exceeded")
+
+	// ErrModuleNotFound is returned when a module required by a generator's
+	// JavaScript cannot be found.
+	ErrModuleNotFound = errors.New("module not found")
+
+	// ErrModuleNotPreloaded is returned when a generator's JavaScript first
+	// requires a module after the functions file has been loaded, such as
+	// within a function. Such a module would not be an input of the files
+	// generated.
+	ErrModuleNotPreloaded = errors.New("module not required when the functions file was loaded")
+)
+
+// jsGlobals are the names of the globals defined by the runtime, which are
+// not exposed as template functions.
+var jsGlobals = map[string]bool{"console": true, "require": true, "module": true, "exports": true}
+
+// jsRuntime runs the JavaScript functions of a generator.
+//
+// Scripts may require CommonJS modules from files in the directory of the
+// functions file, or its subdirectories, as well as the "vervet" module of
+// OpenAPI helpers. Modules are first required while the functions file is
+// loaded, so that the sources of all the modules used are known before
+// generating. Scripts are interrupted if they run for too long or, on a
+// best-effort basis, allocate too much memory. They have no other access to
+// the host.
+type jsRuntime struct {
+	vm      *goja.Runtime
+	fsys    fs.FS
+	root    string
+	modules map[string]*goja.Object
+	vervet  *goja.Object
+
+	// loaded is set once the functions file has been loaded, and sources
+	// are the filenames and sources of the modules it required.
+	loaded  bool
+	sources []string
+
+	timeout     time.Duration
+	memoryLimit uint64
+
+	// mu serializes calls into the VM, which is not safe for concurrent use.
+	mu sync.Mutex
+}
+
+// newJSRuntime returns a runtime for the functions file at filename in fsys.
+func newJSRuntime(fsys fs.FS, filename string, timeout time.Duration, memoryLimit uint64) (*jsRuntime, error) {
+	rt := &jsRuntime{
+		vm:          goja.New(),
+		fsys:        fsys,
+		root:        path.Dir(filename),
+		modules:     map[string]*goja.Object{},
+		timeout:     timeout,
+		memoryLimit: memoryLimit,
+	}
+	for name, value := range map[string]any{
+		"console": jsConsole,
+		"require": rt.requireFrom(rt.root),
+	} {
+		if err := rt.vm.Set(name, value); err != nil {
+			return nil, err
+		}
+	}
+	return rt, nil
+}
+
+// load runs the functions file at filename and returns its functions: those
+// declared globally, as well as those exported with module.exports.
+func (rt *jsRuntime) load(filename, src string) (map[string]goja.Callable, error) {
+	module := rt.vm.NewObject()
+	exports := rt.vm.NewObject()
+	if err := module.Set("exports", exports); err != nil {
+		return nil, err
+	}
+	if err := rt.vm.Set("module", module); err != nil {
+		return nil, err
+	}
+	if err := rt.vm.Set("exports", exports); err != nil {
+		return nil, err
+	}
+	prg, err := goja.Compile(filename, src, false)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := rt.guard(func() (goja.Value, error) {
+		return rt.vm.RunProgram(prg)
+	}); err != nil {
+		return nil, err
+	}
+	rt.loaded = true
+
+	functions := map[string]goja.Callable{}
+	global := rt.vm.GlobalObject()
+	for _, key := range global.Keys() {
+		if fn, ok := goja.AssertFunction(global.Get(key)); ok && !jsGlobals[key] {
+			functions[key] = fn
+		}
+	}
+	if exported, ok := module.Get("exports").(*goja.Object); ok {
+		for _, key := range exported.Keys() {
+			if fn, ok := goja.AssertFunction(exported.Get(key)); ok {
+				functions[key] = fn
+			}
+		}
+	}
+	return functions, nil
+}
+
+// call calls a function with the given template arguments.
+func (rt *jsRuntime) call(fn goja.Callable, args ...any) (goja.Value, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	jsArgs := make([]goja.Value, len(args))
+	for i := range args {
+		jsArgs[i] = rt.vm.ToValue(args[i])
+	}
+	return rt.guard(func() (goja.Value, error) {
+		return fn(goja.Undefined(), jsArgs...)
+	})
+}
+
+// guard runs f, interrupting the VM if it runs for longer than the timeout
+// or the heap grows by more than the memory limit.
+//
+// The memory limit is best-effort. Memory is measured for the whole process
+// every memoryCheckInterval, so allocations elsewhere in the process count
+// towards it, memory freed by the garbage collector does not, and a script
+// may allocate well beyond the limit between checks.
+func (rt *jsRuntime) guard(f func() (goja.Value, error)) (goja.Value, error) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		start := heapBytes()
+		ticker := time.NewTicker(memoryCheckInterval)
+		defer ticker.Stop()
+		deadline := time.NewTimer(rt.timeout)
+		defer deadline.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-deadline.C:
+				rt.vm.Interrupt(fmt.Errorf("%w after %s", ErrFunctionTimeout, rt.timeout))
+				return
+			case <-ticker.C:
+				if used := heapBytes(); used > start && used-start > rt.memoryLimit {
+					rt.vm.Interrupt(fmt.Errorf("%w: more than %d bytes allocated", ErrFunctionMemoryLimit, rt.memoryLimit))
+					return
+				}
+			}
+		}
+	}()
+	v, err := f()
+	close(done)
+	wg.Wait()
+	rt.vm.ClearInterrupt()
+
+	var interrupted *goja.InterruptedError
+	if errors.As(err, &interrupted) {
+		if cause, ok := interrupted.Value().(error); ok {
+			return nil, cause
+		}
+	}
+	return v, err
+}
+
+// heapBytes returns the memory allocated on the heap.
+func heapBytes() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// requireFrom returns the require function of modules in dir.
+func (rt *jsRuntime) requireFrom(dir string) func(name string) (*goja.Object, error) {
+	return func(name string) (*goja.Object, error) {
+		if name == "vervet" {
+			return rt.vervetModule()
+		}
+		if !strings.HasPrefix(name, "./") && !strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("%w: %q", ErrModuleNotFound, name)
+		}
+		filename, src, err := rt.resolve(path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, name)
+		}
+		if _, ok := rt.modules[filename]; !ok && rt.loaded {
+			return nil, fmt.Errorf("%w: %q", ErrModuleNotPreloaded, name)
+		}
+		return rt.require(filename, src)
+	}
+}
+
+// moduleSources returns the filename and source of each module required
+// while the functions file was loaded, in the order they were required.
+func (rt *jsRuntime) moduleSources() []string {
+	return rt.sources
+}
+
+// resolve returns the filename and contents of a module, trying the given
+// name as a file, with a .js extension, and as a directory with an index.js.
+// Modules outside the directory of the functions file cannot be required.
+func (rt *jsRuntime) resolve(name string) (string, string, error) {
+	inside := name == rt.root || strings.HasPrefix(name, rt.root+"/")
+	if rt.root == "." {
+		inside = name != ".." && !strings.HasPrefix(name, "../")
+	}
+	if !inside {
+		return "", "", ErrModuleNotFound
+	}
+	for _, filename := range []string{name, name + ".js", path.Join(name, "index.js")} {
+		src, err := fs.ReadFile(rt.fsys, filename)
+		if err == nil {
+			return filename, string(src), nil
+		}
+	}
+	return "", "", ErrModuleNotFound
+}
+
+// require runs a CommonJS module once, returning its exports. A module
+// required again while it is still running, in a cycle, returns the exports
+// it has defined so far.
+func (rt *jsRuntime) require(filename, src string) (*goja.Object, error) {
+	if module, ok := rt.modules[filename]; ok {
+		return module.Get("exports").ToObject(rt.vm), nil
+	}
+	module := rt.vm.NewObject()
+	exports := rt.vm.NewObject()
+	if err := module.Set("exports", exports); err != nil {
+		return nil, err
+	}
+	rt.modules[filename] = module
+	rt.sources = append(rt.sources, filename, src)
+	wrapper, err := rt.vm.RunScript(filename,
+		"(function(exports, require, module, __filename, __dirname) {"+src+"\n})")
+	if err != nil {
+		delete(rt.modules, filename)
+		return nil, err
+	}
+	fn, ok := goja.AssertFunction(wrapper)
+	if !ok {
+		delete(rt.modules, filename)
+		return nil, fmt.Errorf("failed to load module %q", filename)
+	}
+	dir := path.Dir(filename)
+	_, err = fn(goja.Undefined(), exports, rt.vm.ToValue(rt.requireFrom(dir)), module,
+		rt.vm.ToValue(filename), rt.vm.ToValue(dir))
+	if err != nil {
+		delete(rt.modules, filename)
+		return nil, err
+	}
+	return module.Get("exports").ToObject(rt.vm), nil
+}
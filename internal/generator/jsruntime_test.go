@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"testing"
+	"testing/fstest"
+	"text/template"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var jsFS = fstest.MapFS{
+	"gen/functions.js": &fstest.MapFile{Data: []byte(`
+const util = require("./lib/util");
+const vervet = require("vervet");
+
+function greet(s) {
+	return util.exclaim(vervet.pascalCase(s));
+}
+
+module.exports.schemaPaths = function(schema) {
+	const paths = [];
+	vervet.walkSchema(schema, (s, pointer) => {
+		paths.push(pointer + ":" + (s.type || "any"));
+	});
+	return paths.join(" ");
+};
+
+module.exports.refTitle = function(doc, ref) {
+	return vervet.resolveRef(doc, ref).title;
+};
+
+function escape() {
+	return require("../secret.js");
+}
+
+function late() {
+	require("./lib/util");
+	return require("./lib/late.js");
+}
+
+function spin() {
+	for (;;) {}
+}
+
+function hog() {
+	const all = [];
+	for (;;) {
+		all.push(new Array(1024).fill("x"));
+	}
+}
+`)},
+	"gen/lib/util.js": &fstest.MapFile{Data: []byte(`
+const self = require("./index.js");
+exports.exclaim = s => s + self.suffix;
+`)},
+	"gen/lib/index.js": &fstest.MapFile{Data: []byte(`
+exports.suffix = "!";
+`)},
+	"gen/lib/late.js": &fstest.MapFile{Data: []byte(`
+module.exports = "late";
+`)},
+	"secret.js": &fstest.MapFile{Data: []byte(`
+module.exports = "secret";
+`)},
+}
+
+func loadJS(c *qt.C, timeout time.Duration, memoryLimit uint64) (*jsRuntime, map[string]func(...any) (any, error)) {
+	rt, err := newJSRuntime(jsFS, "gen/functions.js", timeout, memoryLimit)
+	c.Assert(err, qt.IsNil)
+	functions, err := rt.load("gen/functions.js", string(jsFS["gen/functions.js"].Data))
+	c.Assert(err, qt.IsNil)
+	result := map[string]func(...any) (any, error){}
+	for name, fn := range functions {
+		fn := fn
+		result[name] = func(args ...any) (any, error) {
+			v, err := rt.call(fn, args...)
+			if err != nil {
+				return nil, err
+			}
+			return v.Export(), nil
+		}
+	}
+	return rt, result
+}
+
+func TestJSRuntime(t *testing.T) {
+	c := qt.New(t)
+	_, functions := loadJS(c, DefaultFunctionTimeout, DefaultFunctionMemoryLimit)
+	c.Assert(sortedKeys(functions), qt.DeepEquals,
+		[]string{"escape", "greet", "hog", "late", "refTitle", "schemaPaths", "spin"})
+
+	v, err := functions["greet"]("hello_world")
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, "HelloWorld!")
+
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.3
+info: {title: things, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Thing:
+      title: A thing
+      type: object
+      properties:
+        tags:
+          type: array
+          items: {type: string}
+        a/b:
+          allOf: [{type: integer}]
+`))
+	c.Assert(err, qt.IsNil)
+	v, err = functions["schemaPaths"](doc.Components.Schemas["Thing"])
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, "#:object #/properties/a~1b:any #/properties/a~1b/allOf/0:integer "+
+		"#/properties/tags:array #/properties/tags/items:string")
+	v, err = functions["refTitle"](doc, "#/components/schemas/Thing")
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, "A thing")
+	_, err = functions["refTitle"](doc, "#/components/schemas/Nothing")
+	c.Assert(err, qt.ErrorMatches, `.*reference not found: "#/components/schemas/Nothing".*`)
+
+	_, err = functions["escape"]()
+	c.Assert(err, qt.ErrorMatches, `.*module not found: "../secret.js".*`)
+	_, err = functions["late"]()
+	c.Assert(err, qt.ErrorMatches, `.*module not required when the functions file was loaded: "./lib/late.js".*`)
+}
+
+func TestJSRuntimeModuleSources(t *testing.T) {
+	c := qt.New(t)
+	g := &Generator{
+		fs:                  jsFS,
+		functions:           template.FuncMap{},
+		functionTimeout:     DefaultFunctionTimeout,
+		functionMemoryLimit: DefaultFunctionMemoryLimit,
+	}
+	c.Assert(g.loadFunctions("gen/functions.js"), qt.IsNil)
+	c.Assert(g.sources, qt.DeepEquals, []string{
+		string(jsFS["gen/functions.js"].Data),
+		"gen/lib/util.js", string(jsFS["gen/lib/util.js"].Data),
+		"gen/lib/index.js", string(jsFS["gen/lib/index.js"].Data),
+	})
+}
+
+func TestJSRuntimeLimits(t *testing.T) {
+	c := qt.New(t)
+	_, functions := loadJS(c, 100*time.Millisecond, DefaultFunctionMemoryLimit)
+	_, err := functions["spin"]()
+	c.Assert(err, qt.ErrorIs, ErrFunctionTimeout)
+	// The runtime may still be used after a function is interrupted.
+	v, err := functions["greet"]("again")
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, "Again!")
+}
+
+func TestJSRuntimeMemoryLimit(t *testing.T) {
+	c := qt.New(t)
+	// The memory limit is best-effort, but a script which allocates without
+	// bound is always interrupted.
+	_, functions := loadJS(c, DefaultFunctionTimeout, 16<<20)
+	_, err := functions["hog"]()
+	c.Assert(err, qt.ErrorIs, ErrFunctionMemoryLimit)
+	// The runtime may still be used after a function is interrupted.
+	v, err := functions["greet"]("again")
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, "Again!")
+}
+
+func TestCaseConversion(t *testing.T) {
+	c := qt.New(t)
+	for in, out := range map[string][4]string{
+		"thing_id":        {"thingId", "ThingId", "thing_id", "thing-id"},
+		"HTTPServerError": {"httpServerError", "HttpServerError", "http_server_error", "http-server-error"},
+		"org-ids":         {"orgIds", "OrgIds", "org_ids", "org-ids"},
+		"v2Things":        {"v2Things", "V2Things", "v2_things", "v2-things"},
+	} {
+		c.Assert([4]string{camelCase(in), pascalCase(in), snakeCase(in), kebabCase(in)}, qt.Equals, out,
+			qt.Commentf("%q", in))
+	}
+}
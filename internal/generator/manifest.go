@@ -0,0 +1,254 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/snyk/vervet/v8/internal/buildcache"
+)
+
+// DefaultManifestFile is the conventional name of the generator manifest, in
+// the project directory.
+const DefaultManifestFile = ".vervet-generated.json"
+
+// Manifest records the files written by each generator, along with the
+// digests of the inputs they were generated from. Generators use it to skip
+// output which is already up to date, and to find files which are no longer
+// generated.
+type Manifest struct {
+	path   string
+	dir    string
+	inputs *buildcache.Inputs
+
+	// previous contains the entries loaded from the manifest file, and
+	// current those recorded by generators since.
+	previous manifestGenerators
+	current  manifestGenerators
+}
+
+// manifestGenerators maps generator names to the entries of each scope the
+// generator has run in.
+type manifestGenerators map[string]map[string]ManifestEntry
+
+// ManifestEntry records the files generated in a scope.
+type ManifestEntry struct {
+	// Inputs is the digest of the inputs the files were generated from.
+	Inputs buildcache.Digest `json:"inputs"`
+
+	// Files maps the files generated to the digests of their contents.
+	Files map[string]buildcache.Digest `json:"files"`
+}
+
+// manifestJSON is the representation of the manifest file.
+type manifestJSON struct {
+	Generators manifestGenerators `json:"generators"`
+}
+
+// OpenManifest returns the manifest stored at path. An empty manifest is
+// returned if none has been saved yet.
+func OpenManifest(path string) (*Manifest, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{
+		path:     path,
+		dir:      filepath.Dir(path),
+		inputs:   buildcache.NewInputs(),
+		previous: manifestGenerators{},
+		current:  manifestGenerators{},
+	}
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read generator manifest: %w", err)
+	}
+	var mj manifestJSON
+	if err := json.Unmarshal(contents, &mj); err != nil {
+		return nil, fmt.Errorf("failed to read generator manifest %q: %w", path, err)
+	}
+	if mj.Generators != nil {
+		m.previous = mj.Generators
+	}
+	return m, nil
+}
+
+// lookup returns the files generated in a scope, if they were generated from
+// the same inputs and are unmodified since.
+func (m *Manifest) lookup(generator, scopeKey string, inputs buildcache.Digest) ([]string, bool) {
+	entry, ok := m.previous[generator][m.relPath(scopeKey)]
+	if !ok || entry.Inputs != inputs {
+		return nil, false
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, false
+	}
+	files := make([]string, 0, len(entry.Files))
+	for _, filename := range sortedKeys(entry.Files) {
+		path := m.absPath(filename)
+		digest, err := buildcache.FileContentsDigest(path)
+		if err != nil || digest != entry.Files[filename] {
+			return nil, false
+		}
+		// Files are named relative to the working directory where possible,
+		// as they would be if generated.
+		if relPath, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(relPath, "..") {
+			path = relPath
+		}
+		files = append(files, path)
+	}
+	m.record(generator, scopeKey, entry)
+	return files, true
+}
+
+// store records the files generated in a scope.
+func (m *Manifest) store(generator, scopeKey string, inputs buildcache.Digest, files []generatedFile) {
+	entry := ManifestEntry{Inputs: inputs, Files: map[string]buildcache.Digest{}}
+	for _, f := range files {
+		entry.Files[m.relPath(f.name)] = buildcache.NewDigest(string(f.contents))
+	}
+	m.record(generator, scopeKey, entry)
+}
+
+func (m *Manifest) record(generator, scopeKey string, entry ManifestEntry) {
+	if m.current[generator] == nil {
+		m.current[generator] = map[string]ManifestEntry{}
+	}
+	m.current[generator][m.relPath(scopeKey)] = entry
+}
+
+// outputsDigest returns a digest of the files generated by the given
+// generators, so that generators depending on them run again when they
+// change.
+func (m *Manifest) outputsDigest(generators []string) string {
+	var parts []string
+	for _, generator := range generators {
+		entries, ok := m.current[generator]
+		if !ok {
+			entries = m.previous[generator]
+		}
+		for _, scopeKey := range sortedKeys(entries) {
+			for _, filename := range sortedKeys(entries[scopeKey].Files) {
+				parts = append(parts, filename, string(entries[scopeKey].Files[filename]))
+			}
+		}
+	}
+	return string(buildcache.NewDigest(parts...))
+}
+
+// Stale returns the files previously generated by the given generators
+// which they no longer generate, in sorted order. Only files within the
+// directory containing the manifest are stale; files recorded outside of it,
+// with absolute paths or paths leaving the directory, are never removed.
+func (m *Manifest) Stale(generators []string) []string {
+	var stale []string
+	for _, generator := range generators {
+		current := map[string]bool{}
+		for _, entry := range m.current[generator] {
+			for filename := range entry.Files {
+				current[filename] = true
+			}
+		}
+		for _, entry := range m.previous[generator] {
+			for filename := range entry.Files {
+				if !current[filename] && filepath.IsLocal(filepath.FromSlash(filename)) {
+					stale = append(stale, m.absPath(filename))
+				}
+			}
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// Prune removes the files previously generated by the given generators which
+// they no longer generate, along with any directories left empty, and
+// returns the files removed. The manifest then records only what these
+// generators currently generate.
+func (m *Manifest) Prune(generators []string) ([]string, error) {
+	stale := m.Stale(generators)
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale generated file: %w", err)
+		}
+		// Remove parent directories until one is not empty.
+		inside := m.dir + string(filepath.Separator)
+		for dir := filepath.Dir(path); strings.HasPrefix(dir, inside); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+	for _, generator := range generators {
+		if entries, ok := m.current[generator]; ok {
+			m.previous[generator] = entries
+		} else {
+			delete(m.previous, generator)
+		}
+	}
+	return stale, nil
+}
+
+// Generators returns the names of the generators recorded in the manifest,
+// in sorted order.
+func (m *Manifest) Generators() []string {
+	names := map[string]bool{}
+	for name := range m.previous {
+		names[name] = true
+	}
+	for name := range m.current {
+		names[name] = true
+	}
+	return sortedKeys(names)
+}
+
+// Save writes the manifest to disk, recording the files generated by
+// generators which have run since it was opened.
+func (m *Manifest) Save() error {
+	generators := manifestGenerators{}
+	for name, entries := range m.previous {
+		generators[name] = entries
+	}
+	for name, entries := range m.current {
+		generators[name] = entries
+	}
+	contents, err := json.MarshalIndent(manifestJSON{Generators: generators}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(m.path, append(contents, '\n'), 0666); err != nil {
+		return fmt.Errorf("failed to save generator manifest: %w", err)
+	}
+	return nil
+}
+
+// relPath returns a path relative to the directory containing the manifest,
+// so that the manifest may be committed along with the generated files. Paths
+// outside of the directory remain absolute.
+func (m *Manifest) relPath(path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	relPath, err := filepath.Rel(m.dir, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return absPath
+	}
+	return filepath.ToSlash(relPath)
+}
+
+// absPath returns the location of a path recorded in the manifest.
+func (m *Manifest) absPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(m.dir, filepath.FromSlash(path))
+}
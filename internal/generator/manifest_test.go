@@ -0,0 +1,190 @@
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"text/template"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/snyk/vervet/v8/config"
+)
+
+const manifestSpec = `
+openapi: 3.0.3
+x-snyk-api-stability: experimental
+info:
+  title: things
+  version: 3.0.0
+paths:
+  /things:
+    get:
+      operationId: listThings
+      responses:
+        '204':
+          description: Things
+`
+
+func TestManifest(t *testing.T) {
+	c := qt.New(t)
+	dir := c.TempDir()
+	for _, version := range []string{"2021-11-01", "2021-11-02"} {
+		c.Assert(os.MkdirAll(filepath.Join(dir, "resources", "things", version), 0777), qt.IsNil)
+		c.Assert(os.WriteFile(filepath.Join(dir, "resources", "things", version, "spec.yaml"),
+			[]byte(manifestSpec), 0666), qt.IsNil)
+	}
+	for path, contents := range map[string]string{
+		"CODEOWNERS":  "* @snyk/things\n",
+		"README.tmpl": "{{ render }}{{ .Resource }} {{ .Version }} {{ .Document.Info.Title }}\n",
+	} {
+		c.Assert(os.WriteFile(filepath.Join(dir, path), []byte(contents), 0666), qt.IsNil)
+	}
+	cwd, err := os.Getwd()
+	c.Assert(err, qt.IsNil)
+	c.Assert(os.Chdir(dir), qt.IsNil)
+	c.Cleanup(func() {
+		c.Assert(os.Chdir(cwd), qt.IsNil)
+	})
+
+	proj, err := config.Load(bytes.NewBufferString(`
+apis:
+  test:
+    resources:
+      - path: resources
+generators:
+  readme:
+    template: README.tmpl
+    filename: "{{ .Path }}/README"
+`))
+	c.Assert(err, qt.IsNil)
+	manifestFile := filepath.Join(dir, DefaultManifestFile)
+
+	// run generates the project with a new manifest, returning the files
+	// generated, those which were rendered, and those out of date.
+	run := func(c *qt.C, options ...Option) (files, rendered, outOfDate []string) {
+		manifest, err := OpenManifest(manifestFile)
+		c.Assert(err, qt.IsNil)
+		gen, err := New(proj.Generators["readme"], append([]Option{
+			Force(true),
+			WithManifest(manifest),
+			Functions(template.FuncMap{
+				"render": func() string {
+					rendered = append(rendered, "rendered")
+					return ""
+				},
+			}),
+		}, options...)...)
+		c.Assert(err, qt.IsNil)
+		resources, err := MapResources(proj)
+		c.Assert(err, qt.IsNil)
+		files, err = gen.Execute(resources)
+		c.Assert(err, qt.IsNil)
+		outOfDate = append(gen.OutOfDate(), manifest.Stale([]string{"readme"})...)
+		if !gen.check {
+			_, err = manifest.Prune([]string{"readme"})
+			c.Assert(err, qt.IsNil)
+			c.Assert(manifest.Save(), qt.IsNil)
+		}
+		return files, rendered, outOfDate
+	}
+
+	files, rendered, _ := run(c)
+	c.Assert(files, qt.ContentEquals, []string{
+		"resources/things/2021-11-01/README",
+		"resources/things/2021-11-02/README",
+	})
+	c.Assert(rendered, qt.HasLen, 2)
+	contents, err := os.ReadFile("resources/things/2021-11-01/README")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(contents), qt.Equals, "things 2021-11-01~experimental things\n")
+	manifestContents, err := os.ReadFile(manifestFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(manifestContents), qt.Contains, `"resources/things/2021-11-01/README": "sha256:`)
+
+	// Nothing has changed, so nothing is rendered.
+	files, rendered, outOfDate := run(c, Check(true))
+	c.Assert(files, qt.HasLen, 2)
+	c.Assert(rendered, qt.HasLen, 0)
+	c.Assert(outOfDate, qt.HasLen, 0)
+
+	// Changing a spec or a generated file causes it to be generated again.
+	c.Assert(os.WriteFile("resources/things/2021-11-01/spec.yaml",
+		bytes.Replace([]byte(manifestSpec), []byte("title: things"), []byte("title: widgets"), 1), 0666), qt.IsNil)
+	c.Assert(os.WriteFile("resources/things/2021-11-02/README", []byte("edited\n"), 0666), qt.IsNil)
+	_, rendered, outOfDate = run(c, Check(true))
+	c.Assert(rendered, qt.HasLen, 2)
+	c.Assert(outOfDate, qt.DeepEquals, []string{
+		"resources/things/2021-11-01/README",
+		"resources/things/2021-11-02/README",
+	})
+	contents, err = os.ReadFile("resources/things/2021-11-02/README")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(contents), qt.Equals, "edited\n")
+
+	_, rendered, _ = run(c)
+	c.Assert(rendered, qt.HasLen, 2)
+	contents, err = os.ReadFile("resources/things/2021-11-01/README")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(contents), qt.Equals, "things 2021-11-01~experimental widgets\n")
+
+	// Files of deleted resource versions are stale, and pruned.
+	c.Assert(os.Remove("resources/things/2021-11-02/spec.yaml"), qt.IsNil)
+	_, _, outOfDate = run(c, Check(true))
+	c.Assert(outOfDate, qt.DeepEquals, []string{filepath.Join(dir, "resources/things/2021-11-02/README")})
+	files, rendered, _ = run(c)
+	c.Assert(files, qt.DeepEquals, []string{"resources/things/2021-11-01/README"})
+	c.Assert(rendered, qt.HasLen, 0)
+	_, err = os.Stat("resources/things/2021-11-02")
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+}
+
+func TestManifestPruneOutside(t *testing.T) {
+	c := qt.New(t)
+	root := c.TempDir()
+	dir := filepath.Join(root, "project")
+	c.Assert(os.MkdirAll(filepath.Join(dir, "gen"), 0777), qt.IsNil)
+	outside := filepath.Join(root, "outside")
+	for _, path := range []string{outside, filepath.Join(dir, "gen", "stale")} {
+		c.Assert(os.WriteFile(path, []byte("generated\n"), 0666), qt.IsNil)
+	}
+	manifestFile := filepath.Join(dir, DefaultManifestFile)
+	c.Assert(os.WriteFile(manifestFile, []byte(`{"generators": {"readme": {"gen": {"inputs": "sha256:0", "files": {
+		"gen/stale": "sha256:0",
+		"../outside": "sha256:0",
+		"gen/../../outside": "sha256:0",
+		`+strconv.Quote(outside)+`: "sha256:0"
+	}}}}}`), 0666), qt.IsNil)
+
+	manifest, err := OpenManifest(manifestFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(manifest.Stale([]string{"readme"}), qt.DeepEquals, []string{filepath.Join(dir, "gen", "stale")})
+	removed, err := manifest.Prune([]string{"readme"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(removed, qt.DeepEquals, []string{filepath.Join(dir, "gen", "stale")})
+	_, err = os.Stat(outside)
+	c.Assert(err, qt.IsNil)
+	_, err = os.Stat(filepath.Join(dir, "gen"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+}
+
+func TestTemplateSources(t *testing.T) {
+	c := qt.New(t)
+	sources := func(included string) []string {
+		tmpl, err := withIncludeFunc(template.New("contents").Funcs(builtinFuncs)).
+			Parse(`{{ include "greeting" . }}`)
+		c.Assert(err, qt.IsNil)
+		_, err = tmpl.New("files").Parse(`{{ define "greeting" }}` + included + `{{ end }}`)
+		c.Assert(err, qt.IsNil)
+		return templateSources(tmpl)
+	}
+	c.Assert(sources("hello"), qt.DeepEquals, []string{
+		"contents", `{{include "greeting" .}}`,
+		"files", "",
+		"greeting", "hello",
+	})
+	// Templates which are included are inputs.
+	c.Assert(sources("hello"), qt.Not(qt.DeepEquals), sources("goodbye"))
+}
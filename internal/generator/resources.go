@@ -99,6 +99,10 @@ func MapPathOperations(p *openapi3.PathItem) map[string]*openapi3.Operation {
 func MapResources(proj *config.Project) (ResourceMap, error) {
 	resources := ResourceMap{}
 	for apiName, apiConfig := range proj.APIs {
+		policy, err := apiConfig.VersioningPolicy()
+		if err != nil {
+			return nil, err
+		}
 		for _, rcConfig := range apiConfig.Resources {
 			specFiles, err := compiler.ResourceSpecFiles(rcConfig)
 			if err != nil {
@@ -109,7 +113,7 @@ func MapResources(proj *config.Project) (ResourceMap, error) {
 				resourceDir := filepath.Dir(versionDir)
 				resourceKey := ResourceKey{API: apiName, Resource: filepath.Base(resourceDir), Path: resourceDir}
 				if _, ok := resources[resourceKey]; !ok {
-					rcVersions, err := vervet.LoadResourceVersions(resourceDir)
+					rcVersions, err := vervet.LoadResourceVersions(resourceDir, vervet.WithVersioningPolicy(policy))
 					if err != nil {
 						return nil, err
 					}
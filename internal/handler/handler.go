@@ -27,15 +27,26 @@ type Handler struct {
 	cfg    *config.ServerConfig
 	store  storage.ReadOnlyStorage
 	router chi.Router
+	policy *vervet.VersioningPolicy
 }
 
-// New returns a new Handler.
-func New(cfg *config.ServerConfig, store storage.ReadOnlyStorage, routerOptions ...func(r chi.Router)) *Handler {
+// New returns a new Handler, or an error if the configured version aliases
+// are invalid.
+func New(
+	cfg *config.ServerConfig,
+	store storage.ReadOnlyStorage,
+	routerOptions ...func(r chi.Router),
+) (*Handler, error) {
 	h := &Handler{
 		cfg:    cfg,
 		store:  store,
 		router: chi.NewRouter(),
 	}
+	policy, err := cfg.VersioningPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("invalid versioning policy: %w", err)
+	}
+	h.policy = policy
 	for i := range routerOptions {
 		routerOptions[i](h.router)
 	}
@@ -43,7 +54,7 @@ func New(cfg *config.ServerConfig, store storage.ReadOnlyStorage, routerOptions
 	h.router.Get("/openapi", h.openapiVersions)
 	h.router.Get("/metrics", promhttp.Handler().ServeHTTP)
 	h.router.Get("/", h.health)
-	return h
+	return h, nil
 }
 
 var promMiddlewareConfig = prommiddleware.Config{
@@ -95,8 +106,19 @@ func (h *Handler) openapiVersion(w http.ResponseWriter, r *http.Request) {
 	versionString := chi.URLParam(r, "version")
 	w.Header().Set(versionware.HeaderSnykVersionRequested, versionString)
 
-	version, err := vervet.ParseVersion(versionString)
+	ctx := r.Context()
+	versionIndex, err := h.store.VersionIndex(ctx)
 	if err != nil {
+		logError(err)
+		http.Error(w, "Cannot get versions", http.StatusInternalServerError)
+		return
+	}
+
+	version, err := h.policy.ParseRequestedVersion(versionString, &versionIndex)
+	if errors.Is(err, vervet.ErrNoMatchingVersion) {
+		http.Error(w, "Version not found", http.StatusNotFound)
+		return
+	} else if err != nil {
 		// Assume current date if only stability provided
 		if stability, err := vervet.ParseStability(versionString); err == nil {
 			version = vervet.Version{
@@ -110,13 +132,6 @@ func (h *Handler) openapiVersion(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	ctx := r.Context()
-	versionIndex, err := h.store.VersionIndex(ctx)
-	if err != nil {
-		logError(err)
-		http.Error(w, "Cannot get versions", http.StatusInternalServerError)
-		return
-	}
 	resolvedVersion, err := versionIndex.Resolve(version)
 	if errors.Is(err, vervet.ErrNoMatchingVersion) {
 		http.Error(w, "Version not found", http.StatusNotFound)
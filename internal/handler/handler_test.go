@@ -12,11 +12,12 @@ import (
 	"github.com/snyk/vervet/v8"
 	"github.com/snyk/vervet/v8/config"
 	"github.com/snyk/vervet/v8/internal/handler"
+	"github.com/snyk/vervet/v8/versionware"
 )
 
 func TestHealth(t *testing.T) {
 	c := qt.New(t)
-	cfg, h := setup()
+	cfg, h := setup(c)
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest("GET", "/", nil)
@@ -32,7 +33,7 @@ func TestHealth(t *testing.T) {
 
 func TestOpenapi(t *testing.T) {
 	c := qt.New(t)
-	_, h := setup()
+	_, h := setup(c)
 
 	for _, path := range []string{"/openapi", "/openapi/"} {
 		w := httptest.NewRecorder()
@@ -54,7 +55,7 @@ func TestOpenapi(t *testing.T) {
 
 func TestMetrics(t *testing.T) {
 	c := qt.New(t)
-	_, h := setup()
+	_, h := setup(c)
 
 	w := httptest.NewRecorder()
 	// NOTE: Metrics are counted globally, so in order for this metrics test to
@@ -81,7 +82,7 @@ func TestMetrics(t *testing.T) {
 
 func TestOpenapiVersion(t *testing.T) {
 	c := qt.New(t)
-	_, h := setup()
+	_, h := setup(c)
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest("GET", "/openapi/2022-01-16~beta", nil)
@@ -94,7 +95,7 @@ func TestOpenapiVersion(t *testing.T) {
 
 func TestOpenapiVersionNotFound(t *testing.T) {
 	c := qt.New(t)
-	_, h := setup()
+	_, h := setup(c)
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest("GET", "/openapi/2021-01-16~beta", nil)
@@ -105,16 +106,59 @@ func TestOpenapiVersionNotFound(t *testing.T) {
 	c.Assert(contents, qt.DeepEquals, []byte("Version not found\n"))
 }
 
-func setup() (*config.ServerConfig, *handler.Handler) {
+func TestOpenapiVersionAlias(t *testing.T) {
+	c := qt.New(t)
+	_, h := setup(c)
+
+	tests := []struct {
+		requested, served string
+	}{{
+		"latest", "2022-01-16",
+	}, {
+		"latest~beta", "2022-01-16~beta",
+	}, {
+		"sdk-v1", "2021-10-20~beta",
+	}}
+	for _, test := range tests {
+		c.Run(test.requested, func(c *qt.C) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/openapi/"+test.requested, nil)
+			h.ServeHTTP(w, req)
+			c.Assert(w.Code, qt.Equals, 200)
+			c.Assert(w.Header().Get(versionware.HeaderSnykVersionRequested), qt.Equals, test.requested)
+			c.Assert(w.Header().Get(versionware.HeaderSnykVersionServed), qt.Equals, test.served)
+			contents, err := io.ReadAll(w.Result().Body)
+			c.Assert(err, qt.IsNil)
+			c.Assert(contents, qt.DeepEquals, []byte("got "+test.served))
+		})
+	}
+}
+
+func TestNewInvalidAlias(t *testing.T) {
+	c := qt.New(t)
+	cfg := &config.ServerConfig{
+		Aliases: map[string]string{
+			"sdk-v1": "not-a-version",
+		},
+	}
+	_, err := handler.New(cfg, &mockStorage{})
+	c.Assert(err, qt.ErrorMatches, `invalid versioning policy: invalid version for alias "sdk-v1": .*`)
+}
+
+func setup(c *qt.C) (*config.ServerConfig, *handler.Handler) {
 	cfg := &config.ServerConfig{
 		Services: []config.ServiceConfig{{
 			Name: "petfood", URL: "http://petfood.svc.cluster.local",
 		}, {
 			Name: "animals", URL: "http://animals.svc.cluster.local",
 		}},
+		Aliases: map[string]string{
+			"sdk-v1": "2021-12-01~beta",
+		},
 	}
 	st := &mockStorage{}
-	h := handler.New(cfg, st, handler.UseDefaultMiddleware)
+	h, err := handler.New(cfg, st, handler.UseDefaultMiddleware)
+	c.Assert(err, qt.IsNil)
 	return cfg, h
 }
 
@@ -0,0 +1,161 @@
+package mock
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// maxExampleDepth limits how deeply nested schemas are synthesized, so that
+// recursive schemas terminate.
+const maxExampleDepth = 16
+
+// Example returns an example value for the given schema.
+//
+// Examples, defaults and enums declared in the schema are preferred. Otherwise
+// a value is synthesized from the schema type and format. Synthesized values
+// are deterministic, so that mock responses are stable across requests.
+func Example(s *openapi3.SchemaRef) interface{} {
+	return example(s, 0)
+}
+
+func example(s *openapi3.SchemaRef, depth int) interface{} {
+	if s == nil || s.Value == nil || depth > maxExampleDepth {
+		return nil
+	}
+	schema := s.Value
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if schema.Default != nil {
+		return schema.Default
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	if len(schema.AllOf) > 0 {
+		return exampleAllOf(schema, depth)
+	}
+	if len(schema.OneOf) > 0 {
+		return example(schema.OneOf[0], depth+1)
+	}
+	if len(schema.AnyOf) > 0 {
+		return example(schema.AnyOf[0], depth+1)
+	}
+	switch {
+	case schema.Type.Is(openapi3.TypeObject), len(schema.Properties) > 0:
+		return exampleObject(schema, depth)
+	case schema.Type.Is(openapi3.TypeArray):
+		return exampleArray(schema, depth)
+	case schema.Type.Is(openapi3.TypeString):
+		return exampleString(schema)
+	case schema.Type.Is(openapi3.TypeInteger):
+		if schema.Min != nil {
+			return int64(*schema.Min)
+		}
+		return int64(0)
+	case schema.Type.Is(openapi3.TypeNumber):
+		if schema.Min != nil {
+			return *schema.Min
+		}
+		return float64(0)
+	case schema.Type.Is(openapi3.TypeBoolean):
+		return true
+	}
+	return nil
+}
+
+func exampleAllOf(schema *openapi3.Schema, depth int) interface{} {
+	var result interface{}
+	merged := map[string]interface{}{}
+	for _, sub := range schema.AllOf {
+		v := example(sub, depth+1)
+		if m, ok := v.(map[string]interface{}); ok {
+			for k := range m {
+				merged[k] = m[k]
+			}
+			result = merged
+		} else if result == nil {
+			result = v
+		}
+	}
+	if len(schema.Properties) > 0 {
+		if m, ok := exampleObject(schema, depth).(map[string]interface{}); ok {
+			for k := range m {
+				merged[k] = m[k]
+			}
+			result = merged
+		}
+	}
+	return result
+}
+
+func exampleObject(schema *openapi3.Schema, depth int) interface{} {
+	result := make(map[string]interface{}, len(schema.Properties))
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop := schema.Properties[name]
+		if prop.Value != nil && prop.Value.WriteOnly {
+			continue
+		}
+		if v := example(prop, depth+1); v != nil {
+			result[name] = v
+		}
+	}
+	return result
+}
+
+func exampleArray(schema *openapi3.Schema, depth int) interface{} {
+	n := 1
+	if schema.MinItems > 1 {
+		n = int(schema.MinItems)
+	}
+	if schema.MaxItems != nil && uint64(n) > *schema.MaxItems {
+		n = int(*schema.MaxItems)
+	}
+	item := example(schema.Items, depth+1)
+	if item == nil {
+		return []interface{}{}
+	}
+	result := make([]interface{}, n)
+	for i := range result {
+		result[i] = item
+	}
+	return result
+}
+
+func exampleString(schema *openapi3.Schema) string {
+	var s string
+	switch schema.Format {
+	case "date-time":
+		s = "2021-01-01T00:00:00Z"
+	case "date":
+		s = "2021-01-01"
+	case "uuid":
+		s = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "email":
+		s = "user@example.com"
+	case "uri", "url":
+		s = "https://example.com"
+	case "hostname":
+		s = "example.com"
+	case "ipv4":
+		s = "192.0.2.1"
+	case "ipv6":
+		s = "2001:db8::1"
+	default:
+		s = "string"
+	}
+	if n := int(schema.MinLength); len(s) < n {
+		s += strings.Repeat("x", n-len(s))
+	}
+	if schema.MaxLength != nil && uint64(len(s)) > *schema.MaxLength {
+		s = s[:*schema.MaxLength]
+	}
+	return s
+}
@@ -0,0 +1,242 @@
+// Package mock provides an HTTP handler which answers requests for any
+// version of a compiled API with responses generated from its OpenAPI specs.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/versionware"
+)
+
+// Option configures a mock handler.
+type Option func(*options)
+
+type options struct {
+	serverURL string
+	validate  bool
+	strict    bool
+	policy    *vervet.VersioningPolicy
+}
+
+// ServerURL sets the URL at which the mock API is served, overriding the
+// servers declared in the OpenAPI specs.
+func ServerURL(serverURL string) Option {
+	return func(o *options) {
+		o.serverURL = serverURL
+	}
+}
+
+// Validate determines whether requests and responses are validated against
+// the OpenAPI spec of the requested version. Default is true.
+func Validate(validate bool) Option {
+	return func(o *options) {
+		o.validate = validate
+	}
+}
+
+// Strict determines whether a mock response that fails validation results in
+// an error response. Otherwise invalid responses are logged and sent anyway.
+// Default is false.
+func Strict(strict bool) Option {
+	return func(o *options) {
+		o.strict = strict
+	}
+}
+
+// Policy sets the versioning policy of the API, which determines the scheme
+// requested versions are written in and how they are resolved. Default is the
+// default versioning policy.
+func Policy(policy *vervet.VersioningPolicy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// NewHandler returns an http.Handler which responds to requests for any of the
+// given compiled OpenAPI spec versions. Requests are routed to a version with
+// versionware.Handler, and answered with the examples declared in the
+// matching operation's success response, or values synthesized from its
+// schema.
+func NewHandler(docs []*openapi3.T, opts ...Option) (http.Handler, error) {
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no OpenAPI versions provided")
+	}
+	o := &options{validate: true}
+	for i := range opts {
+		opts[i](o)
+	}
+	// Routing and validation override the servers of the given documents, so
+	// each is cloned rather than modified in place.
+	clones := make([]*openapi3.T, len(docs))
+	for i := range docs {
+		clone := *docs[i]
+		clones[i] = &clone
+	}
+	vhs := make([]versionware.VersionHandler, len(clones))
+	for i, doc := range clones {
+		versionStr, err := vervet.ExtensionString(doc.Extensions, vervet.ExtSnykApiVersion)
+		if err != nil {
+			return nil, err
+		}
+		version, err := vervet.ParseAnyVersion(versionStr)
+		if err != nil {
+			return nil, err
+		}
+		if o.serverURL != "" {
+			doc.Servers = openapi3.Servers{{URL: o.serverURL}}
+		}
+		router, err := gorillamux.NewRouter(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to route version %s: %w", version, err)
+		}
+		vhs[i] = versionware.VersionHandler{
+			Version: version,
+			Handler: &responder{router: router},
+		}
+	}
+	vh := versionware.NewHandler(vhs...)
+	if o.policy != nil {
+		vh.UsePolicy(o.policy)
+	}
+	var h http.Handler = vh
+	if o.validate {
+		validator, err := versionware.NewValidator(&versionware.ValidatorConfig{
+			ServerURL: o.serverURL,
+			Policy:    o.policy,
+			Options: []openapi3filter.ValidatorOption{
+				openapi3filter.Strict(o.strict),
+				openapi3filter.ValidationOptions(openapi3filter.Options{
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				}),
+			},
+		}, clones...)
+		if err != nil {
+			return nil, err
+		}
+		h = validator.Middleware(h)
+	}
+	return h, nil
+}
+
+// responder answers requests for a single API version.
+type responder struct {
+	router routers.Router
+}
+
+// ServeHTTP implements http.Handler.
+func (r *responder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	route, _, err := r.router.FindRoute(req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	status, resp := successResponse(route.Operation)
+	if resp == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	for name, header := range resp.Headers {
+		if w.Header().Get(name) != "" || header.Value == nil {
+			continue
+		}
+		if v := Example(header.Value.Schema); v != nil {
+			w.Header().Set(name, fmt.Sprint(v))
+		}
+	}
+	contentType, mediaType := responseMediaType(resp)
+	if mediaType == nil {
+		w.WriteHeader(status)
+		return
+	}
+	body, err := json.Marshal(mediaTypeExample(mediaType))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("failed to write mock response: %v", err)
+	}
+}
+
+// successResponse returns the status code and declaration of the response an
+// operation gives on success: the lowest declared 2xx status, falling back to
+// a 2XX range or the default response.
+func successResponse(op *openapi3.Operation) (int, *openapi3.Response) {
+	if op.Responses == nil {
+		return http.StatusNoContent, nil
+	}
+	codes := make([]string, 0, op.Responses.Len())
+	for code := range op.Responses.Map() {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			// A range such as 2XX
+			status = http.StatusOK
+		}
+		return status, op.Responses.Value(code).Value
+	}
+	if resp := op.Responses.Default(); resp != nil {
+		return http.StatusOK, resp.Value
+	}
+	return http.StatusNoContent, nil
+}
+
+// responseMediaType returns the content type to respond with, preferring JSON
+// representations.
+func responseMediaType(resp *openapi3.Response) (string, *openapi3.MediaType) {
+	if len(resp.Content) == 0 {
+		return "", nil
+	}
+	contentTypes := make([]string, 0, len(resp.Content))
+	for contentType := range resp.Content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+	for _, contentType := range contentTypes {
+		if strings.HasSuffix(contentType, "json") {
+			return contentType, resp.Content[contentType]
+		}
+	}
+	return contentTypes[0], resp.Content[contentTypes[0]]
+}
+
+// mediaTypeExample returns the example declared for a media type, or one
+// synthesized from its schema.
+func mediaTypeExample(mt *openapi3.MediaType) interface{} {
+	if mt.Example != nil {
+		return mt.Example
+	}
+	if len(mt.Examples) > 0 {
+		names := make([]string, 0, len(mt.Examples))
+		for name := range mt.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if ex := mt.Examples[name]; ex != nil && ex.Value != nil && ex.Value.Value != nil {
+				return ex.Value.Value
+			}
+		}
+	}
+	return Example(mt.Schema)
+}
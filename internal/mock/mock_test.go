@@ -0,0 +1,125 @@
+package mock_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/internal/mock"
+	"github.com/snyk/vervet/v8/testdata"
+	"github.com/snyk/vervet/v8/versionware"
+)
+
+func TestHandler(t *testing.T) {
+	c := qt.New(t)
+	docs, err := vervet.LoadVersions(os.DirFS(testdata.Path("output")))
+	c.Assert(err, qt.IsNil)
+
+	var h http.Handler
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r)
+	}))
+	c.Cleanup(srv.Close)
+	servers := make([]openapi3.Servers, len(docs))
+	for i := range docs {
+		servers[i] = docs[i].Servers
+	}
+	h, err = mock.NewHandler(docs, mock.ServerURL(srv.URL+"/api/rest"))
+	c.Assert(err, qt.IsNil)
+
+	c.Run("does not modify the given documents", func(c *qt.C) {
+		for i := range docs {
+			c.Assert(docs[i].Servers, qt.DeepEquals, servers[i])
+		}
+	})
+
+	c.Run("responds with a synthesized example", func(c *qt.C) {
+		resp, err := srv.Client().Get(srv.URL + "/api/rest/examples/hello-world/42?version=2021-06-20~beta")
+		c.Assert(err, qt.IsNil)
+		defer resp.Body.Close()
+		c.Assert(resp.StatusCode, qt.Equals, http.StatusOK)
+		c.Assert(resp.Header.Get("Content-Type"), qt.Equals, "application/vnd.api+json")
+		c.Assert(resp.Header.Get(versionware.HeaderSnykVersionServed), qt.Equals, "2021-06-13~beta")
+		var body map[string]interface{}
+		c.Assert(json.NewDecoder(resp.Body).Decode(&body), qt.IsNil)
+		c.Assert(body["data"], qt.Not(qt.IsNil))
+		c.Assert(body["jsonapi"], qt.Not(qt.IsNil))
+	})
+
+	c.Run("rejects an invalid request", func(c *qt.C) {
+		resp, err := srv.Client().Post(srv.URL+"/api/rest/examples/hello-world?version=2021-06-20~beta",
+			"application/vnd.api+json", strings.NewReader(`{"attributes":{"message":"hi"}}`))
+		c.Assert(err, qt.IsNil)
+		defer resp.Body.Close()
+		c.Assert(resp.StatusCode, qt.Equals, http.StatusBadRequest)
+	})
+
+	c.Run("rejects an unknown version", func(c *qt.C) {
+		resp, err := srv.Client().Get(srv.URL + "/api/rest/examples/hello-world/42?version=2020-01-01")
+		c.Assert(err, qt.IsNil)
+		defer resp.Body.Close()
+		c.Assert(resp.StatusCode, qt.Equals, http.StatusNotFound)
+	})
+}
+
+func TestHandlerPolicy(t *testing.T) {
+	c := qt.New(t)
+	var docs []*openapi3.T
+	for _, version := range []string{"2024-01-01", "2024-01-01.2"} {
+		doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.3
+x-snyk-api-version: '` + version + `'
+info: {title: things, version: 3.0.0}
+paths:
+  /things:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema: {type: object, properties: {version: {type: string, example: '` + version + `'}}}
+`))
+		c.Assert(err, qt.IsNil)
+		docs = append(docs, doc)
+	}
+	policy := vervet.DefaultVersioningPolicy()
+	policy.Scheme = vervet.DateSequenceScheme
+	h, err := mock.NewHandler(docs, mock.ServerURL("http://localhost"), mock.Policy(policy))
+	c.Assert(err, qt.IsNil)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost/things?version=2024-01-01.3", nil))
+	c.Assert(w.Code, qt.Equals, http.StatusOK)
+	c.Assert(w.Header().Get(versionware.HeaderSnykVersionServed), qt.Equals, "2024-01-01.2")
+	c.Assert(w.Body.String(), qt.JSONEquals, map[string]string{"version": "2024-01-01.2"})
+
+	// Versions in the date-sequence scheme are not valid in the default
+	// policy.
+	_, err = mock.NewHandler(docs, mock.ServerURL("http://localhost"))
+	c.Assert(err, qt.ErrorMatches, `.*invalid version "2024-01-01.2"`)
+}
+
+func TestExample(t *testing.T) {
+	c := qt.New(t)
+	schema := openapi3.NewObjectSchema().
+		WithProperty("id", openapi3.NewUUIDSchema()).
+		WithProperty("count", openapi3.NewIntegerSchema().WithMin(3)).
+		WithProperty("tags", openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())).
+		WithProperty("kind", openapi3.NewStringSchema().WithEnum("thing", "other")).
+		WithProperty("created", openapi3.NewDateTimeSchema())
+	c.Assert(mock.Example(schema.NewRef()), qt.DeepEquals, map[string]interface{}{
+		"id":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+		"count":   int64(3),
+		"tags":    []interface{}{"string"},
+		"kind":    "thing",
+		"created": "2021-01-01T00:00:00Z",
+	})
+}
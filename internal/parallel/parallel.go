@@ -0,0 +1,101 @@
+// Package parallel provides bounded concurrent processing which produces
+// deterministic results.
+package parallel
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"go.uber.org/multierr"
+)
+
+// DefaultLimit returns the default maximum number of tasks run concurrently:
+// the number of CPUs available to the process.
+func DefaultLimit() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+// Pool bounds the number of tasks run at once across all the calls to MapPool
+// and ForEachPool which share it, so that work fanned out at several levels,
+// such as for each API and then for each of its versions, is bounded as a
+// whole.
+//
+// A task must not wait on other tasks in the same pool, as it holds its slot
+// while waiting and the pool could be exhausted. Work which only coordinates
+// pooled tasks should run outside the pool.
+type Pool struct {
+	sem chan struct{}
+}
+
+// NewPool returns a pool which runs at most limit tasks at once. A limit less
+// than 1 uses DefaultLimit.
+func NewPool(limit int) *Pool {
+	if limit < 1 {
+		limit = DefaultLimit()
+	}
+	return &Pool{sem: make(chan struct{}, limit)}
+}
+
+// Limit returns the maximum number of tasks the pool runs at once.
+func (p *Pool) Limit() int {
+	return cap(p.sem)
+}
+
+// Map calls fn on each item, with at most limit calls in flight at once. A
+// limit less than 1 uses DefaultLimit. See MapPool.
+func Map[T, R any](ctx context.Context, limit int, items []T, fn func(context.Context, T) (R, error)) ([]R, error) {
+	return MapPool(ctx, NewPool(limit), items, fn)
+}
+
+// MapPool calls fn on each item, with each call holding a slot in the pool
+// while it runs.
+//
+// Results are returned in the same order as the items they were produced
+// from, regardless of the order in which calls complete. Errors from all calls
+// are combined with multierr, also in item order. Items not yet started when
+// the context is cancelled are skipped, and the context error is included.
+func MapPool[T, R any](ctx context.Context, p *Pool, items []T, fn func(context.Context, T) (R, error)) ([]R, error) {
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	var wg sync.WaitGroup
+	var ctxErr error
+	for i := range items {
+		if ctxErr = ctx.Err(); ctxErr != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			ctxErr = ctx.Err()
+		case p.sem <- struct{}{}:
+		}
+		if ctxErr != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-p.sem
+				wg.Done()
+			}()
+			results[i], errs[i] = fn(ctx, items[i])
+		}(i)
+	}
+	wg.Wait()
+	return results, multierr.Combine(append(errs, ctxErr)...)
+}
+
+// ForEach calls fn on each item, with at most limit calls in flight at once.
+// Errors are combined as described for MapPool.
+func ForEach[T any](ctx context.Context, limit int, items []T, fn func(context.Context, T) error) error {
+	return ForEachPool(ctx, NewPool(limit), items, fn)
+}
+
+// ForEachPool calls fn on each item, with each call holding a slot in the
+// pool while it runs. Errors are combined as described for MapPool.
+func ForEachPool[T any](ctx context.Context, p *Pool, items []T, fn func(context.Context, T) error) error {
+	_, err := MapPool(ctx, p, items, func(ctx context.Context, item T) (struct{}, error) {
+		return struct{}{}, fn(ctx, item)
+	})
+	return err
+}
@@ -0,0 +1,91 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"go.uber.org/multierr"
+
+	"github.com/snyk/vervet/v8/internal/parallel"
+)
+
+func TestMap(t *testing.T) {
+	c := qt.New(t)
+	items := []int{5, 1, 4, 2, 3}
+	var inFlight, maxInFlight int32
+	results, err := parallel.Map(context.Background(), 2, items, func(ctx context.Context, n int) (string, error) {
+		cur := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			prev := atomic.LoadInt32(&maxInFlight)
+			if cur <= prev || atomic.CompareAndSwapInt32(&maxInFlight, prev, cur) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		return fmt.Sprintf("item %d", n), nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(results, qt.DeepEquals, []string{"item 5", "item 1", "item 4", "item 2", "item 3"})
+	c.Assert(atomic.LoadInt32(&maxInFlight) <= 2, qt.IsTrue)
+}
+
+func TestMapErrors(t *testing.T) {
+	c := qt.New(t)
+	items := []int{1, 2, 3, 4}
+	_, err := parallel.Map(context.Background(), 0, items, func(ctx context.Context, n int) (int, error) {
+		if n%2 == 0 {
+			return 0, fmt.Errorf("even %d", n)
+		}
+		return n, nil
+	})
+	errs := multierr.Errors(err)
+	c.Assert(errs, qt.HasLen, 2)
+	c.Assert(errs[0], qt.ErrorMatches, "even 2")
+	c.Assert(errs[1], qt.ErrorMatches, "even 4")
+}
+
+func TestForEachCancelled(t *testing.T) {
+	c := qt.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var calls int32
+	err := parallel.ForEach(ctx, 1, []int{1, 2, 3}, func(ctx context.Context, n int) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	c.Assert(errors.Is(err, context.Canceled), qt.IsTrue)
+	c.Assert(atomic.LoadInt32(&calls), qt.Equals, int32(0))
+}
+
+func TestPoolShared(t *testing.T) {
+	c := qt.New(t)
+	pool := parallel.NewPool(3)
+	c.Assert(pool.Limit(), qt.Equals, 3)
+	var inFlight, maxInFlight int32
+	task := func(ctx context.Context, n int) (int, error) {
+		cur := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			prev := atomic.LoadInt32(&maxInFlight)
+			if cur <= prev || atomic.CompareAndSwapInt32(&maxInFlight, prev, cur) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return n * 2, nil
+	}
+	// Coordinators run outside the pool, and fan out into it concurrently.
+	results, err := parallel.Map(context.Background(), 4, []int{0, 1, 2, 3},
+		func(ctx context.Context, n int) ([]int, error) {
+			return parallel.MapPool(ctx, pool, []int{n, n + 10, n + 20}, task)
+		})
+	c.Assert(err, qt.IsNil)
+	c.Assert(results, qt.DeepEquals, [][]int{{0, 20, 40}, {2, 22, 42}, {4, 24, 44}, {6, 26, 46}})
+	c.Assert(atomic.LoadInt32(&maxInFlight) <= 3, qt.IsTrue)
+}
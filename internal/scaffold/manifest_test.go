@@ -9,10 +9,12 @@ import (
 )
 
 var manifestTests = []struct {
-	desc     string
-	version  string
-	organize map[string]string
-	err      string
+	desc      string
+	version   string
+	organize  map[string]string
+	variables []Variable
+	render    []string
+	err       string
 }{{
 	desc:     "invalid version",
 	version:  "nope",
@@ -37,6 +39,64 @@ var manifestTests = []struct {
 	desc:     "ok, version 1",
 	version:  "1",
 	organize: map[string]string{"foo": "foo"},
+}, {
+	desc:      "variables require version 2",
+	version:   "1",
+	organize:  map[string]string{"foo": "foo"},
+	variables: []Variable{{Name: "foo"}},
+	err:       `variables and render require manifest version "2"`,
+}, {
+	desc:      "invalid variable name",
+	version:   "2",
+	organize:  map[string]string{"foo": "foo"},
+	variables: []Variable{{Name: "foo-bar"}},
+	err:       `invalid variable name "foo-bar"`,
+}, {
+	desc:      "duplicate variable",
+	version:   "2",
+	organize:  map[string]string{"foo": "foo"},
+	variables: []Variable{{Name: "foo"}, {Name: "foo"}},
+	err:       `variable "foo" declared more than once`,
+}, {
+	desc:      "unsupported variable type",
+	version:   "2",
+	organize:  map[string]string{"foo": "foo"},
+	variables: []Variable{{Name: "foo", Type: "int"}},
+	err:       `unsupported variable type "int" \(variables.foo.type\)`,
+}, {
+	desc:      "choice without choices",
+	version:   "2",
+	organize:  map[string]string{"foo": "foo"},
+	variables: []Variable{{Name: "foo", Type: "choice"}},
+	err:       `choices are required \(variables.foo.choices\)`,
+}, {
+	desc:      "invalid pattern",
+	version:   "2",
+	organize:  map[string]string{"foo": "foo"},
+	variables: []Variable{{Name: "foo", Pattern: "["}},
+	err:       `invalid pattern \(variables.foo.pattern\): .*`,
+}, {
+	desc:      "invalid default",
+	version:   "2",
+	organize:  map[string]string{"foo": "foo"},
+	variables: []Variable{{Name: "foo", Type: "bool", Default: "maybe"}},
+	err:       `invalid default \(variables.foo.default\): invalid value "maybe" for variable "foo": not a bool`,
+}, {
+	desc:     "invalid render pattern",
+	version:  "2",
+	organize: map[string]string{"foo": "foo"},
+	render:   []string{"[foo"},
+	err:      `invalid render pattern "\[foo"`,
+}, {
+	desc:     "ok, version 2",
+	version:  "2",
+	organize: map[string]string{"{{ .foo }}": "foo"},
+	variables: []Variable{
+		{Name: "foo", Default: "foo", Pattern: "^[a-z]+$"},
+		{Name: "bar", Type: "bool", Default: false},
+		{Name: "baz", Type: "choice", Choices: []string{"a", "b"}},
+	},
+	render: []string{"**/*.tmpl"},
 }}
 
 func TestManifestValidate(t *testing.T) {
@@ -45,8 +105,10 @@ func TestManifestValidate(t *testing.T) {
 	c.Assert(os.WriteFile(filepath.Join(fakeSrc, "foo"), []byte("foo"), 0666), qt.IsNil)
 	for _, t := range manifestTests {
 		m := &Manifest{
-			Version:  t.version,
-			Organize: t.organize,
+			Version:   t.version,
+			Organize:  t.organize,
+			Variables: t.variables,
+			Render:    t.render,
 		}
 		if t.err == "" {
 			c.Assert(m.validate(fakeSrc), qt.IsNil)
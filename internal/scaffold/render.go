@@ -0,0 +1,137 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"github.com/snyk/vervet/v8/internal/files"
+)
+
+// organizeV2 provisions files from a version 2 scaffold source into its
+// destination, rendering destination paths and file contents with the values
+// of the scaffold's variables.
+func (s *Scaffold) organizeV2() error {
+	if s.values == nil {
+		if err := s.SetValues(nil); err != nil {
+			return err
+		}
+	}
+	items := map[string]string{}
+	for dstItem, srcItem := range s.manifest.Organize {
+		rendered, err := s.render("organize."+dstItem, dstItem)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(s.dst, rendered)
+		if rel, err := filepath.Rel(s.dst, dstPath); err != nil || !filepath.IsLocal(rel) {
+			return fmt.Errorf("organized path %q is outside the destination directory", rendered)
+		}
+		if _, ok := items[dstPath]; ok {
+			return fmt.Errorf("more than one source item organized into %q", dstPath)
+		}
+		items[dstPath] = filepath.Join(s.src, srcItem)
+	}
+	dstPaths := make([]string, 0, len(items))
+	for dstPath := range items {
+		dstPaths = append(dstPaths, dstPath)
+	}
+	sort.Strings(dstPaths)
+
+	// If we're not force overwriting, check if files already exist before
+	// copying any of them.
+	if !s.force {
+		for _, dstPath := range dstPaths {
+			_, err := os.Stat(dstPath)
+			if err == nil {
+				return ErrAlreadyInitialized
+			}
+			if !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	for _, dstPath := range dstPaths {
+		srcPath := items[dstPath]
+		err := filepath.WalkDir(srcPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			name, err := filepath.Rel(srcPath, path)
+			if err != nil {
+				return err
+			}
+			return s.copyFile(filepath.Join(dstPath, name), path)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy %q to %q: %w", srcPath, dstPath, err)
+		}
+	}
+	return nil
+}
+
+// copyFile copies a file from the scaffold source to dst, rendering its
+// contents if it matches one of the manifest's render patterns.
+func (s *Scaffold) copyFile(dst, src string) error {
+	name, err := filepath.Rel(s.src, src)
+	if err != nil {
+		return err
+	}
+	name = filepath.ToSlash(name)
+	if !s.rendered(name) {
+		return files.CopyFile(dst, src, s.force)
+	}
+	contents, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	rendered, err := s.render(name, string(contents))
+	if err != nil {
+		return err
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if !s.force {
+		flags |= os.O_EXCL
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dst, flags, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(rendered)
+	return err
+}
+
+// rendered returns whether the contents of a source file, relative to the
+// scaffold source, are rendered.
+func (s *Scaffold) rendered(name string) bool {
+	for _, pattern := range s.manifest.Render {
+		if ok, _ := doublestar.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// render renders text as a Go template with the values of the scaffold's
+// variables. Templates referencing undeclared variables fail to render.
+func (s *Scaffold) render(name, text string) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %q: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, s.values); err != nil {
+		return "", fmt.Errorf("failed to render template %q: %w", name, err)
+	}
+	return buf.String(), nil
+}
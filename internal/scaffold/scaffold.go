@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/ghodss/yaml"
 
 	"github.com/snyk/vervet/v8/internal/files"
@@ -19,9 +20,13 @@ type Scaffold struct {
 	dst, src string
 	force    bool
 	manifest *Manifest
+	values   map[string]any
 }
 
-const manifestV1 = "1"
+const (
+	manifestV1 = "1"
+	manifestV2 = "2"
+)
 
 // Manifest defines the scaffold manifest model.
 type Manifest struct {
@@ -30,7 +35,19 @@ type Manifest struct {
 	// Organize contains a mapping of files relative to Scaffold src, to be
 	// copied into dst, relative to dst. Missing intermediate directories will
 	// be created as needed.
+	//
+	// In a version 2 manifest, destination paths are rendered as Go
+	// templates with the values of the scaffold's variables.
 	Organize map[string]string `json:"organize"`
+
+	// Variables declares the values used to render a version 2 scaffold.
+	Variables []Variable `json:"variables,omitempty"`
+
+	// Render contains glob patterns matching files, relative to Scaffold
+	// src, whose contents are rendered as Go templates with the values of
+	// the scaffold's variables when copied. Other files are copied as-is.
+	// Only supported in a version 2 manifest.
+	Render []string `json:"render,omitempty"`
 }
 
 // Option defines a functional option that modifies a new Scaffold in the
@@ -83,8 +100,48 @@ func New(dst, src string, options ...Option) (*Scaffold, error) {
 	return s, nil
 }
 
+// Variables returns the variables declared by the scaffold manifest.
+func (s *Scaffold) Variables() []Variable {
+	return s.manifest.Variables
+}
+
+// SetValues sets the values of the scaffold's variables, from their string
+// representations. Variables without a value are given their default value.
+// An error is returned if a value is not valid, or if a variable without a
+// default value is not given one.
+func (s *Scaffold) SetValues(values map[string]string) error {
+	result := map[string]any{}
+	declared := map[string]bool{}
+	for i := range s.manifest.Variables {
+		v := &s.manifest.Variables[i]
+		declared[v.Name] = true
+		value, ok := values[v.Name]
+		if !ok {
+			if !v.HasDefault() {
+				return fmt.Errorf("a value is required for variable %q", v.Name)
+			}
+			value = v.DefaultString()
+		}
+		parsed, err := v.Parse(value)
+		if err != nil {
+			return err
+		}
+		result[v.Name] = parsed
+	}
+	for name := range values {
+		if !declared[name] {
+			return fmt.Errorf("unknown variable %q", name)
+		}
+	}
+	s.values = result
+	return nil
+}
+
 // Organize provisions files from the scaffold source into its destination.
 func (s *Scaffold) Organize() error {
+	if s.manifest.Version == manifestV2 {
+		return s.organizeV2()
+	}
 	for dstItem, srcItem := range s.manifest.Organize {
 		dstPath := filepath.Join(s.dst, dstItem)
 		// If we're not force overwriting, check if files already exist.
@@ -133,7 +190,7 @@ func (m *Manifest) validate(src string) error {
 	if m.Version == "" {
 		m.Version = manifestV1
 	}
-	if m.Version != manifestV1 {
+	if m.Version != manifestV1 && m.Version != manifestV2 {
 		return fmt.Errorf("unsupported manifest version %q", m.Version)
 	}
 
@@ -146,5 +203,28 @@ func (m *Manifest) validate(src string) error {
 			return fmt.Errorf("cannot stat source item %q: %w", srcPath, err)
 		}
 	}
+	if m.Version == manifestV1 {
+		if len(m.Variables) > 0 || len(m.Render) > 0 {
+			return fmt.Errorf("variables and render require manifest version %q", manifestV2)
+		}
+		return nil
+	}
+
+	names := map[string]bool{}
+	for i := range m.Variables {
+		v := &m.Variables[i]
+		if err := v.validate(); err != nil {
+			return err
+		}
+		if names[v.Name] {
+			return fmt.Errorf("variable %q declared more than once", v.Name)
+		}
+		names[v.Name] = true
+	}
+	for _, pattern := range m.Render {
+		if !doublestar.ValidatePattern(pattern) {
+			return fmt.Errorf("invalid render pattern %q", pattern)
+		}
+	}
 	return nil
 }
@@ -26,3 +26,87 @@ This is a generated scaffold for version {{ .Version }}~{{ .Stability }} of the
 
 `[1:])
 }
+
+func TestScaffoldVariables(t *testing.T) {
+	c := qt.New(t)
+	dstDir := c.TempDir()
+	s, err := scaffold.New(dstDir, testdata.Path("test-scaffold-v2"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Variables(), qt.HasLen, 4)
+
+	// Variables without a default value are required, and values must be
+	// valid.
+	c.Assert(s.Organize(), qt.ErrorMatches, `a value is required for variable "owner"`)
+	c.Assert(s.SetValues(map[string]string{"owner": "@snyk/things", "api": "My API"}), qt.ErrorMatches,
+		`invalid value "My API" for variable "api": must match .*`)
+	c.Assert(s.SetValues(map[string]string{"owner": "@snyk/things", "versionScheme": "calver"}), qt.ErrorMatches,
+		`invalid value "calver" for variable "versionScheme": must be one of .*`)
+	c.Assert(s.SetValues(map[string]string{"owner": "@snyk/things", "nope": "nope"}), qt.ErrorMatches,
+		`unknown variable "nope"`)
+
+	c.Assert(s.SetValues(map[string]string{
+		"owner":         "@snyk/things",
+		"api":           "things",
+		"versionScheme": "semver",
+	}), qt.IsNil)
+	c.Assert(s.Organize(), qt.IsNil)
+	vervetYAML, err := os.ReadFile(filepath.Join(dstDir, ".vervet.yaml"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(vervetYAML), qt.Equals, `
+versionScheme: semver
+generators:
+  version-readme:
+    scope: version
+    filename: "things/resources/{{ .Resource }}/{{ .Version }}/README"
+    template: ".vervet/templates/README.tmpl"
+
+apis:
+  things:
+    resources:
+      - path: 'things/resources'
+        generators:
+          - version-readme
+    output:
+      path: 'things/versions'
+`[1:])
+	codeowners, err := os.ReadFile(filepath.Join(dstDir, "CODEOWNERS"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(codeowners), qt.Equals, "* @snyk/things\n")
+	// Files not matching a render pattern are copied as-is.
+	readmeTmpl, err := os.ReadFile(filepath.Join(dstDir, ".vervet", "templates", "README.tmpl"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(readmeTmpl), qt.Contains, "{{ .Version }}")
+	_, err = os.Stat(filepath.Join(dstDir, "things", "resources", ".keep"))
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(s.Organize(), qt.Equals, scaffold.ErrAlreadyInitialized)
+}
+
+func TestScaffoldOrganizeOutside(t *testing.T) {
+	c := qt.New(t)
+	srcDir := c.TempDir()
+	c.Assert(os.WriteFile(filepath.Join(srcDir, "manifest.yaml"), []byte(`
+version: "2"
+variables:
+  - name: dir
+organize:
+  "{{ .dir }}/out.txt": out.txt
+`), 0666), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(srcDir, "out.txt"), []byte("out\n"), 0666), qt.IsNil)
+	parentDir := c.TempDir()
+	dstDir := filepath.Join(parentDir, "project")
+	s, err := scaffold.New(dstDir, srcDir)
+	c.Assert(err, qt.IsNil)
+
+	// Rendered paths may not organize files outside the destination.
+	c.Assert(s.SetValues(map[string]string{"dir": "../.."}), qt.IsNil)
+	c.Assert(s.Organize(), qt.ErrorMatches, `organized path "../../out.txt" is outside the destination directory`)
+	_, err = os.Stat(filepath.Join(parentDir, "..", "out.txt"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+
+	c.Assert(s.SetValues(map[string]string{"dir": "sub/.."}), qt.IsNil)
+	c.Assert(s.Organize(), qt.IsNil)
+	contents, err := os.ReadFile(filepath.Join(dstDir, "out.txt"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(contents), qt.Equals, "out\n")
+}
@@ -0,0 +1,127 @@
+package scaffold
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// VariableString is a variable with a freeform string value. This is the
+	// default variable type.
+	VariableString = "string"
+
+	// VariableBool is a variable which is either true or false.
+	VariableBool = "bool"
+
+	// VariableChoice is a variable whose value is one of a list of choices.
+	VariableChoice = "choice"
+)
+
+var variableNameRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Variable declares a value used to render a version 2 scaffold. Variables
+// are referenced by name in the scaffold's templates, such as
+// "{{ .apiName }}".
+type Variable struct {
+	// Name is the name of the variable in templates.
+	Name string `json:"name"`
+
+	// Description is used to prompt for the value of the variable.
+	Description string `json:"description,omitempty"`
+
+	// Type is one of "string", "bool" or "choice". Default is "string".
+	Type string `json:"type,omitempty"`
+
+	// Default is the value of the variable when one is not provided. A
+	// variable without a default value is required.
+	Default any `json:"default,omitempty"`
+
+	// Pattern is a regular expression which string values must match.
+	Pattern string `json:"pattern,omitempty"`
+
+	// Choices are the values a choice variable may have.
+	Choices []string `json:"choices,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Label returns the label used to prompt for the variable's value.
+func (v *Variable) Label() string {
+	if v.Description != "" {
+		return v.Description
+	}
+	return v.Name
+}
+
+// HasDefault returns whether the variable has a default value.
+func (v *Variable) HasDefault() bool {
+	return v.Default != nil
+}
+
+// DefaultString returns the default value of the variable as a string, as it
+// would be provided on the command line.
+func (v *Variable) DefaultString() string {
+	if v.Default == nil {
+		return ""
+	}
+	return fmt.Sprint(v.Default)
+}
+
+// Parse returns the template value of the variable from its string
+// representation, or an error if the value is not valid.
+func (v *Variable) Parse(value string) (any, error) {
+	switch v.Type {
+	case VariableBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for variable %q: not a bool", value, v.Name)
+		}
+		return b, nil
+	case VariableChoice:
+		for i := range v.Choices {
+			if v.Choices[i] == value {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid value %q for variable %q: must be one of %q", value, v.Name, v.Choices)
+	default:
+		if v.pattern != nil && !v.pattern.MatchString(value) {
+			return nil, fmt.Errorf("invalid value %q for variable %q: must match %q", value, v.Name, v.Pattern)
+		}
+		return value, nil
+	}
+}
+
+func (v *Variable) validate() error {
+	if !variableNameRE.MatchString(v.Name) {
+		return fmt.Errorf("invalid variable name %q", v.Name)
+	}
+	switch v.Type {
+	case "":
+		v.Type = VariableString
+	case VariableString, VariableBool:
+	case VariableChoice:
+		if len(v.Choices) == 0 {
+			return fmt.Errorf("choices are required (variables.%s.choices)", v.Name)
+		}
+	default:
+		return fmt.Errorf("unsupported variable type %q (variables.%s.type)", v.Type, v.Name)
+	}
+	if v.Pattern != "" {
+		if v.Type != VariableString {
+			return fmt.Errorf("pattern is only supported for string variables (variables.%s.pattern)", v.Name)
+		}
+		pattern, err := regexp.Compile(v.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern (variables.%s.pattern): %w", v.Name, err)
+		}
+		v.pattern = pattern
+	}
+	if v.HasDefault() {
+		if _, err := v.Parse(v.DefaultString()); err != nil {
+			return fmt.Errorf("invalid default (variables.%s.default): %w", v.Name, err)
+		}
+	}
+	return nil
+}
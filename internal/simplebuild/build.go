@@ -1,10 +1,13 @@
 package simplebuild
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
+	"maps"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -14,17 +17,65 @@ import (
 	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
-	"github.com/hairyhenderson/go-codeowners"
 	"github.com/oasdiff/oasdiff/checker"
 	"github.com/oasdiff/oasdiff/diff"
 	"github.com/oasdiff/oasdiff/load"
+	"go.uber.org/multierr"
 
 	"github.com/snyk/vervet/v8"
 	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/buildcache"
 	"github.com/snyk/vervet/v8/internal/files"
-	"github.com/snyk/vervet/v8/specs"
+	"github.com/snyk/vervet/v8/internal/parallel"
 )
 
+// BuildOption configures how Build compiles a project.
+type BuildOption func(*buildOptions)
+
+type buildOptions struct {
+	concurrency int
+	cache       *buildcache.Cache
+	report      *BuildReport
+	fsys        fs.FS
+}
+
+// Concurrency sets the maximum number of tasks run at once across all the APIs
+// being built: loading a spec file, checking a pair of versions for breaking
+// changes, or writing an output version. Values less than 1 use the number of
+// available CPUs.
+func Concurrency(n int) BuildOption {
+	return func(o *buildOptions) {
+		o.concurrency = n
+	}
+}
+
+// Cache sets the build cache used to skip output versions whose inputs have
+// not changed since they were last built. Default is no caching.
+func Cache(cache *buildcache.Cache) BuildOption {
+	return func(o *buildOptions) {
+		o.cache = cache
+	}
+}
+
+// FS sets the filesystem from which resource specs and overlays are loaded.
+// Paths in the project configuration are relative to the root of fsys.
+// Default is the local filesystem, relative to the working directory.
+//
+// The build cache tracks inputs on the local filesystem, so FS cannot be used
+// with Cache.
+func FS(fsys fs.FS) BuildOption {
+	return func(o *buildOptions) {
+		o.fsys = fsys
+	}
+}
+
+// Report sets a report to be populated with an account of the build.
+func Report(report *BuildReport) BuildOption {
+	return func(o *buildOptions) {
+		o.report = report
+	}
+}
+
 // Build compiles the versioned resources in a project configuration based on
 // simplified versioning rules, after the start date.
 func Build(
@@ -33,119 +84,290 @@ func Build(
 	startDate vervet.Version,
 	versioningUrl string,
 	appendOutputFiles bool,
+	options ...BuildOption,
 ) error {
 	if time.Now().Before(startDate.Date) {
 		return nil
 	}
+	opts := buildOptions{}
+	for i := range options {
+		options[i](&opts)
+	}
+	if opts.fsys != nil && opts.cache != nil {
+		return fmt.Errorf("cannot cache builds loaded from a filesystem")
+	}
+	report := opts.report
+	if report == nil {
+		report = &BuildReport{}
+	}
+	report.StartVersion = startDate.String()
+	report.APIs, report.Warnings = []*APIReport{}, []string{}
+	buildStart := time.Now()
+	defer func() {
+		report.Timings.Total = since(buildStart)
+	}()
 
 	latestVersion, fetchErr := fetchLatestVersion(versioningUrl)
 	if fetchErr != nil {
-		fmt.Printf("[WARNING] Could not fetch latest version from %q: %v\n", versioningUrl, fetchErr)
-		fmt.Println("[WARNING] Proceeding with the build without validating resources against the global latest version.")
+		report.warnf("Could not fetch latest version from %q: %v", versioningUrl, fetchErr)
+		report.warnf("Proceeding with the build without validating resources against the global latest version.")
 	}
 
-	for _, apiConfig := range project.APIs {
+	b := &builder{
+		startDate:         startDate,
+		appendOutputFiles: appendOutputFiles,
+		inputs:            buildcache.NewInputs(),
+		opts:              opts,
+		pool:              parallel.NewPool(opts.concurrency),
+		src:               specSource{fsys: opts.fsys},
+	}
+	if fetchErr == nil {
+		b.latestVersion = &latestVersion
+	}
+	var builds []*apiBuild
+	for _, apiName := range project.APINames() {
+		apiConfig := project.APIs[apiName]
 		if apiConfig.Output == nil {
-			fmt.Printf("No output specified for %s, skipping\n", apiConfig.Name)
+			report.warnf("No output specified for %s, skipping", apiConfig.Name)
 			continue
 		}
+		builds = append(builds, &apiBuild{config: apiConfig, report: report.addAPI(apiConfig)})
+	}
+
+	// APIs are built concurrently. Building an API only coordinates its
+	// tasks, which run in the builder's pool, so it does not take a slot in
+	// the pool itself. Each API's warnings and output are reported once all
+	// are built, in API order, so that these are deterministic.
+	err := parallel.ForEach(ctx, len(builds), builds, func(ctx context.Context, ab *apiBuild) error {
+		return b.buildAPI(ctx, ab)
+	})
+	for _, ab := range builds {
+		report.Warnings = append(report.Warnings, ab.warnings...)
+		if _, writeErr := ab.log.WriteTo(os.Stdout); writeErr != nil {
+			err = multierr.Append(err, writeErr)
+		}
+	}
+	return err
+}
+
+// builder builds the APIs in a project.
+type builder struct {
+	startDate         vervet.Version
+	appendOutputFiles bool
+	// latestVersion is the latest version of the global API, if known.
+	latestVersion *vervet.Version
+	inputs        *buildcache.Inputs
+	opts          buildOptions
+	pool          *parallel.Pool
+	src           specSource
+}
 
+// apiBuild is the build of a single API.
+type apiBuild struct {
+	config *config.API
+	report *APIReport
+
+	// warnings and log are the warnings and output of the build, which are
+	// reported when all APIs have been built.
+	warnings []string
+	log      bytes.Buffer
+}
+
+// warnf records a warning about the API's build.
+func (ab *apiBuild) warnf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	ab.warnings = append(ab.warnings, msg)
+	fmt.Fprintln(&ab.log, "[WARNING] "+msg)
+}
+
+// buildAPI compiles the versioned resources of an API.
+func (b *builder) buildAPI(ctx context.Context, ab *apiBuild) error {
+	apiConfig, apiReport := ab.config, ab.report
+	apiStart := time.Now()
+	defer func() {
+		apiReport.Timings.Total = since(apiStart)
+	}()
+
+	policy, err := apiConfig.VersioningPolicy()
+	if err != nil {
+		return fmt.Errorf("%w (apis.%s.versioning)", err, apiConfig.Name)
+	}
+	startDate, err := apiConfig.PivotVersion(b.startDate)
+	if err != nil {
+		return fmt.Errorf("%w (apis.%s.versioning.pivotVersion)", err, apiConfig.Name)
+	}
+	if policy.Scheme == vervet.SemverScheme {
+		// Semantic versions are not dated, so they all precede the pivot
+		// version and are compiled by the compiler.
+		ab.warnf("Versions of %s are not dated, so none are on or after the pivot version %s", apiConfig.Name, startDate)
+		return nil
+	}
+	if b.latestVersion != nil {
 		for _, resource := range apiConfig.Resources {
-			paths, err := ResourceSpecFiles(resource)
+			paths, err := b.src.match(resource)
 			if err != nil {
 				return err
 			}
-			if fetchErr == nil {
-				if err := CheckSingleVersionResourceToBeBeforeLatestVersion(paths, latestVersion); err != nil {
-					return err
-				}
+			if err := CheckSingleVersionResourceToBeBeforeLatestVersion(paths, *b.latestVersion); err != nil {
+				return err
 			}
 		}
+	}
 
-		operations, err := LoadPaths(ctx, apiConfig)
-		if err != nil {
-			return err
-		}
-		for _, op := range operations {
-			op.Annotate()
-		}
-		servers, err := FindServers(apiConfig)
+	if time.Now().Before(startDate.Date) {
+		ab.warnf("Pivot version %s of %s is in the future, skipping", startDate, apiConfig.Name)
+		return nil
+	}
+
+	versions, err := lookupCachedVersions(b.opts.cache, b.inputs, apiConfig, startDate, b.latestVersion)
+	if err != nil {
+		ab.warnf("Not caching the build of %s: %v", apiConfig.Name, err)
+	}
+	if versions.upToDate() {
+		// Nothing has changed since the last build, though the output is
+		// still copied to any other output paths.
+		apiReport.UpToDate = true
+		for _, release := range versions.cached {
+			apiReport.addVersion(release, true, nil)
+		}
+		writer, err := NewCachedWriter(*apiConfig.Output, b.appendOutputFiles, versions.cached, nil)
 		if err != nil {
 			return err
 		}
-		docs := operations.Build(startDate, servers)
-		writer, err := NewWriter(*apiConfig.Output, appendOutputFiles)
+		writer.log = &ab.log
+		return writer.Finalize()
+	}
+
+	loadStart := time.Now()
+	operations, err := loadPaths(ctx, b.pool, b.src, apiConfig)
+	if err != nil {
+		return err
+	}
+	apiReport.Timings.Load = since(loadStart)
+
+	compileStart := time.Now()
+	for _, op := range operations {
+		op.AnnotateWithPolicy(policy)
+	}
+	docConfig, err := documentConfig(b.src, apiConfig)
+	if err != nil {
+		return err
+	}
+	docs := operations.BuildDocuments(startDate, docConfig)
+	writer, err := NewCachedWriter(*apiConfig.Output, b.appendOutputFiles, versions.cached, versions.stale)
+	if err != nil {
+		return err
+	}
+	writer.log = &ab.log
+	sortDocsByVersion(docs)
+	releasedOps := filterBetaAndGAVersions(operations)
+	for _, doc := range docs {
+		apiReport.addVersion(doc.Version, slices.Contains(versions.cached, doc.Version), releasedOps)
+	}
+	apiReport.Timings.Compile = since(compileStart)
+
+	checkStart := time.Now()
+	err = checkBreakingChanges(ctx, b.pool, docs)
+	if err != nil {
+		return err
+	}
+	apiReport.Timings.CheckBreakingChanges = since(checkStart)
+
+	// Process each document. Documents share references which are
+	// mutated when resolved, so each is rendered in turn rather than in the
+	// pool; writing the rendered output is independent per version. Cached
+	// versions are still resolved, so that later versions are resolved as
+	// they would be without the cache.
+	renderStart := time.Now()
+	rendered := make([]RenderedDoc, 0, len(docs))
+	for _, doc := range docs {
+		err := doc.applyOverlays(ctx, b.src, apiConfig.Overlays)
 		if err != nil {
 			return err
 		}
 
-		sortDocsByVersionDate(docs)
+		if doc.Doc.Extensions == nil {
+			doc.Doc.Extensions = make(map[string]interface{})
+		}
+		doc.Doc.Extensions[vervet.ExtSnykApiVersion] = doc.Version.String()
 
-		err = CheckBreakingChanges(docs)
+		refResolver := NewRefResolver()
+		err = refResolver.ResolveRefs(doc.Doc)
 		if err != nil {
 			return err
 		}
 
-		// Process each document
-		for _, doc := range docs {
-			err := doc.ApplyOverlays(ctx, apiConfig.Overlays)
-			if err != nil {
-				return err
-			}
-
-			if doc.Doc.Extensions == nil {
-				doc.Doc.Extensions = make(map[string]interface{})
-			}
-			doc.Doc.Extensions[vervet.ExtSnykApiVersion] = doc.VersionDate.Format(time.DateOnly)
-
-			refResolver := NewRefResolver()
-			err = refResolver.ResolveRefs(doc.Doc)
-			if err != nil {
-				return err
-			}
-
-			err = writer.Write(ctx, doc)
-			if err != nil {
-				return err
-			}
+		if slices.Contains(versions.cached, doc.Version) {
+			continue
 		}
-
-		err = writer.Finalize()
+		renderedDoc, err := writer.Render(ctx, doc)
 		if err != nil {
 			return err
 		}
+		rendered = append(rendered, renderedDoc)
 	}
+	apiReport.Timings.Render = since(renderStart)
+
+	writeStart := time.Now()
+	err = writer.writeAll(ctx, b.pool, rendered)
+	if err != nil {
+		return err
+	}
+	err = versions.store(b.opts.cache, writer, rendered)
+	if err != nil {
+		return err
+	}
+	err = writer.Finalize()
+	if err != nil {
+		return err
+	}
+	apiReport.Timings.Write = since(writeStart)
 	return nil
 }
 
+// documentConfig returns the document metadata of an API. Servers are found
+// in the API resources if not configured.
+func documentConfig(src specSource, apiConfig *config.API) (*config.Document, error) {
+	docConfig := &config.Document{}
+	if apiConfig.Document != nil {
+		*docConfig = *apiConfig.Document
+	}
+	if len(docConfig.Servers) == 0 {
+		servers, err := findServers(src, apiConfig)
+		if err != nil {
+			return nil, err
+		}
+		docConfig.Servers = servers
+	}
+	return docConfig, nil
+}
+
 // FindServers returns the servers defined in the first version in the first resource of the API type.
 func FindServers(api *config.API) (openapi3.Servers, error) {
+	return findServers(specSource{}, api)
+}
+
+func findServers(src specSource, api *config.API) (openapi3.Servers, error) {
 	if len(api.Resources) == 0 {
 		return nil, nil
 	}
-	paths, err := ResourceSpecFiles(api.Resources[0])
+	paths, err := src.match(api.Resources[0])
 	if err != nil {
 		return nil, err
 	}
 	if len(paths) == 0 {
 		return nil, nil
 	}
-	doc, err := vervet.NewDocumentFile(paths[0])
+	doc, err := src.load(paths[0])
 	if err != nil {
 		return nil, err
 	}
 	return doc.Servers, nil
 }
 
-func sortDocsByVersionDate(docs DocSet) {
+func sortDocsByVersion(docs DocSet) {
 	slices.SortFunc(docs, func(a, b VersionedDoc) int {
-		if a.VersionDate.Before(b.VersionDate) {
-			return -1
-		}
-		if a.VersionDate.After(b.VersionDate) {
-			return 1
-		}
-		return 0
+		return a.Version.Compare(b.Version)
 	})
 }
 
@@ -158,38 +380,43 @@ type VersionedOp struct {
 	Version      vervet.Version
 	Operation    *openapi3.Operation
 	ResourceName string
+	// Source is the location of the spec file declaring the operation.
+	Source string
 }
 
 type VersionSet []VersionedOp
 
 type Operations map[OpKey]VersionSet
 
+// VersionedDoc is a document compiled for an output version. The version is
+// that of a release, such as "2024-01-01" or "2024-01-01.2", which is always
+// GA as output versions include both beta and GA operations.
 type VersionedDoc struct {
-	VersionDate time.Time
-	Doc         *openapi3.T
+	Version vervet.Version
+	Doc     *openapi3.T
 }
 type DocSet []VersionedDoc
 
+// Build compiles operations into a document for each version on or after
+// the start version, with the default document metadata.
 func (ops Operations) Build(startVersion vervet.Version, servers openapi3.Servers) DocSet {
+	return ops.BuildDocuments(startVersion, &config.Document{Servers: servers})
+}
+
+// BuildDocuments compiles operations into a document for each version on or
+// after the start version, with the document metadata given. Metadata not
+// given has default values.
+func (ops Operations) BuildDocuments(startVersion vervet.Version, docConfig *config.Document) DocSet {
 	filteredOps := filterBetaAndGAVersions(ops)
-	versionDates := filteredOps.VersionDates()
-	versionDates = filterVersionByStartDate(versionDates, startVersion.Date)
-	output := make(DocSet, len(versionDates))
-	for idx, versionDate := range versionDates {
+	releases := filterReleasesByStart(filteredOps.Releases(), startVersion)
+	output := make(DocSet, len(releases))
+	for idx, release := range releases {
 		output[idx] = VersionedDoc{
-			Doc: &openapi3.T{
-				OpenAPI: "3.0.3",
-				Info: &openapi3.Info{
-					Title:   "Snyk API",
-					Version: "1.0.0",
-				},
-				Paths:   openapi3.NewPaths(),
-				Servers: servers,
-			},
-			VersionDate: versionDate,
+			Doc:     NewDocument(docConfig),
+			Version: release,
 		}
 		for path, spec := range filteredOps {
-			op := spec.GetLatest(versionDate)
+			op := spec.GetLatest(release)
 			if op == nil {
 				continue
 			}
@@ -199,13 +426,45 @@ func (ops Operations) Build(startVersion vervet.Version, servers openapi3.Server
 	return output
 }
 
+// NewDocument returns an empty document with the metadata given in
+// docConfig. Each document has its own copy of the metadata, so that
+// documents may be modified independently.
+func NewDocument(docConfig *config.Document) *openapi3.T {
+	if docConfig == nil {
+		docConfig = &config.Document{}
+	}
+	doc := &openapi3.T{
+		OpenAPI: docConfig.OpenAPI,
+		Info: &openapi3.Info{
+			Title:   "Snyk API",
+			Version: "1.0.0",
+		},
+		Paths:    openapi3.NewPaths(),
+		Servers:  slices.Clone(docConfig.Servers),
+		Security: slices.Clone(docConfig.Security),
+		Tags:     slices.Clone(docConfig.Tags),
+	}
+	if doc.OpenAPI == "" {
+		doc.OpenAPI = "3.0.3"
+	}
+	if docConfig.Info != nil {
+		info := *docConfig.Info
+		doc.Info = &info
+	}
+	if len(docConfig.SecuritySchemes) > 0 {
+		doc.Components = &openapi3.Components{
+			SecuritySchemes: maps.Clone(docConfig.SecuritySchemes),
+		}
+	}
+	return doc
+}
+
 func filterBetaAndGAVersions(ops Operations) Operations {
 	filteredOps := make(Operations, len(ops))
 	for opKey, versionSet := range ops {
 		filteredVersionSet := VersionSet{}
 		for _, versionedOp := range versionSet {
-			if versionedOp.Version.Stability != vervet.StabilityGA &&
-				versionedOp.Version.Stability != vervet.StabilityBeta {
+			if !isReleased(versionedOp.Version) {
 				continue
 			}
 			filteredVersionSet = append(filteredVersionSet, versionedOp)
@@ -217,60 +476,98 @@ func filterBetaAndGAVersions(ops Operations) Operations {
 	return filteredOps
 }
 
-func filterVersionByStartDate(dates []time.Time, startDate time.Time) []time.Time {
-	resultDates := []time.Time{startDate}
-	for _, d := range dates {
-		if d.After(startDate) {
-			resultDates = append(resultDates, d)
+// isReleased returns whether resources in a version are compiled into the
+// output versions.
+func isReleased(version vervet.Version) bool {
+	return version.Stability == vervet.StabilityGA || version.Stability == vervet.StabilityBeta
+}
+
+// releaseOf returns the release of a version, regardless of its stability.
+// Releases on the same date in the date-sequence scheme are distinct.
+func releaseOf(version vervet.Version) vervet.Version {
+	version.Stability = vervet.StabilityGA
+	return version
+}
+
+// filterReleasesByStart returns the start release followed by the releases
+// after it.
+func filterReleasesByStart(releases []vervet.Version, start vervet.Version) []vervet.Version {
+	start = releaseOf(start)
+	result := []vervet.Version{start}
+	for _, release := range releases {
+		if release.Compare(start) > 0 {
+			result = append(result, release)
 		}
 	}
-	return resultDates
+	return result
 }
 
-func (ops Operations) VersionDates() []time.Time {
-	versionSet := map[time.Time]struct{}{}
+// Releases returns the distinct releases of the operations, in no
+// particular order.
+func (ops Operations) Releases() []vervet.Version {
+	releaseSet := map[vervet.Version]struct{}{}
 	for _, opSet := range ops {
 		for _, op := range opSet {
-			versionSet[op.Version.Date] = struct{}{}
+			releaseSet[releaseOf(op.Version)] = struct{}{}
 		}
 	}
-	uniqueVersions := make([]time.Time, len(versionSet))
-	idx := 0
-	for version := range versionSet {
-		uniqueVersions[idx] = version
-		idx++
-	}
-	return uniqueVersions
+	return slices.Collect(maps.Keys(releaseSet))
 }
 
+// LoadPaths loads the operations of each version of the resources in an API.
 func LoadPaths(ctx context.Context, api *config.API) (Operations, error) {
+	return loadPaths(ctx, parallel.NewPool(0), specSource{}, api)
+}
+
+// loadPaths loads the operations of an API as LoadPaths does, loading spec
+// files from src concurrently in the given pool.
+func loadPaths(ctx context.Context, pool *parallel.Pool, src specSource, api *config.API) (Operations, error) {
 	operations := map[OpKey]VersionSet{}
-	cwd, err := os.Getwd()
+	policy, err := api.VersioningPolicy()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w (apis.%s.versioning)", err, api.Name)
 	}
-	ownerFinder, err := codeowners.FromFile(cwd)
+	if policy.Scheme == vervet.SemverScheme {
+		return nil, fmt.Errorf("simplified versioning requires a dated version scheme, not %s (apis.%s)",
+			policy.Scheme.Name(), api.Name)
+	}
+	ownerFinder, err := src.codeowners()
 	if err != nil {
 		return nil, err
 	}
-	for doc, err := range specs.GetInputSpecsItr(ctx, api) {
+	var paths []string
+	for _, resource := range api.Resources {
+		resourcePaths, err := src.match(resource)
 		if err != nil {
 			return nil, err
 		}
-
+		paths = append(paths, resourcePaths...)
+	}
+	docs, err := parallel.MapPool(ctx, pool, paths, src.loadInputSpec)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range docs {
 		version, err := doc.Version()
 		if err != nil {
-			return nil, fmt.Errorf("invalid version on path %q", doc.Location().String())
+			return nil, doc.Diagnostic(fmt.Errorf("invalid version on path %q", doc.Location().String()))
 		}
 		stabilityStr, err := vervet.ExtensionString(doc.T.Extensions, vervet.ExtSnykApiStability)
 		if err != nil {
-			return nil, err
+			return nil, doc.Diagnostic(err, vervet.ExtSnykApiStability)
 		}
 		version.Stability, err = vervet.ParseStability(stabilityStr)
 		if err != nil {
-			return nil, fmt.Errorf("invalid stability %q", stabilityStr)
+			return nil, doc.Diagnostic(fmt.Errorf("invalid stability %q", stabilityStr), vervet.ExtSnykApiStability)
+		}
+		if !policy.Allows(version.Stability) {
+			return nil, doc.Diagnostic(
+				fmt.Errorf("stability %q is not allowed by the versioning policy of %s", stabilityStr, api.Name),
+				vervet.ExtSnykApiStability,
+			)
 		}
 		resourceName := filepath.Base(filepath.Dir(doc.RelativePath()))
+		source := src.relativePath(doc)
 
 		for _, pathName := range doc.T.Paths.InMatchingOrder() {
 			pathDef := doc.T.Paths.Value(pathName)
@@ -290,6 +587,7 @@ func LoadPaths(ctx context.Context, api *config.API) (Operations, error) {
 					Version:      version,
 					Operation:    opDef,
 					ResourceName: resourceName,
+					Source:       source,
 				})
 			}
 		}
@@ -302,10 +600,24 @@ func ResourceSpecFiles(resource *config.ResourceSet) ([]string, error) {
 	return files.LocalFSSource{}.Match(resource)
 }
 
-func (vs VersionSet) GetLatest(before time.Time) *openapi3.Operation {
+// GetLatest returns the operation which is current at the given release, or
+// nil if there is none.
+func (vs VersionSet) GetLatest(release vervet.Version) *openapi3.Operation {
+	latest := vs.GetLatestVersion(release)
+	if latest == nil {
+		return nil
+	}
+	return latest.Operation
+}
+
+// GetLatestVersion returns the version of an operation which is current at
+// the given release, or nil if there is none. The stability of the release
+// is disregarded.
+func (vs VersionSet) GetLatestVersion(release vervet.Version) *VersionedOp {
+	release = releaseOf(release)
 	var latest *VersionedOp
 	for _, versionedOp := range vs {
-		if versionedOp.Version.Date.After(before) {
+		if releaseOf(versionedOp.Version).Compare(release) > 0 {
 			continue
 		}
 		if latest == nil {
@@ -320,10 +632,7 @@ func (vs VersionSet) GetLatest(before time.Time) *openapi3.Operation {
 			latest = &versionedOp
 		}
 	}
-	if latest == nil {
-		return nil
-	}
-	return latest.Operation
+	return latest
 }
 
 // Annotate adds Snyk specific extensions to openapi operations. These
@@ -336,7 +645,16 @@ func (vs VersionSet) GetLatest(before time.Time) *openapi3.Operation {
 //   - x-snyk-api-resource: what resource this operation acts on
 //   - x-snyk-api-lifecycle: status of the operation, can be one of:
 //     [ unreleased, released, deprecated, sunset ]
+//
+// Lifecycles and sunset dates are those of the default versioning policy.
 func (vs VersionSet) Annotate() {
+	vs.AnnotateWithPolicy(vervet.DefaultVersioningPolicy())
+}
+
+// AnnotateWithPolicy adds Snyk specific extensions to openapi operations, as
+// Annotate does, with lifecycles and sunset dates determined by the given
+// versioning policy.
+func (vs VersionSet) AnnotateWithPolicy(policy *vervet.VersioningPolicy) {
 	slices.SortFunc(vs, func(a, b VersionedOp) int {
 		return a.Version.Compare(b.Version)
 	})
@@ -355,7 +673,7 @@ func (vs VersionSet) Annotate() {
 		op.Operation.Extensions[vervet.ExtSnykApiResource] = op.ResourceName
 		op.Operation.Extensions[vervet.ExtSnykApiVersion] = op.Version.String()
 		op.Operation.Extensions[vervet.ExtSnykApiReleases] = releases
-		op.Operation.Extensions[vervet.ExtSnykApiLifecycle] = op.Version.LifecycleAt(time.Time{}).String()
+		op.Operation.Extensions[vervet.ExtSnykApiLifecycle] = policy.LifecycleAt(op.Version, time.Time{}).String()
 		op.Operation.Extensions[vervet.ExtApiStabilityLevel] = MapStabilityLevel(op.Version.Stability)
 		op.Operation.Extensions[vervet.ExtSnykApiStability] = op.Version.Stability.String()
 
@@ -366,7 +684,7 @@ func (vs VersionSet) Annotate() {
 				continue
 			}
 			op.Operation.Extensions[vervet.ExtSnykDeprecatedBy] = laterVersion.String()
-			sunsetDate, ok := op.Version.Sunset(laterVersion)
+			sunsetDate, ok := policy.Sunset(op.Version, laterVersion)
 			if ok {
 				op.Operation.Extensions[vervet.ExtSnykSunsetEligible] = sunsetDate.Format("2006-01-02")
 			}
@@ -386,32 +704,44 @@ func MapStabilityLevel(s vervet.Stability) string {
 	}
 }
 
+// CheckBreakingChanges checks that each document, in version order, has a
+// breaking change from the document before it.
 func CheckBreakingChanges(docs DocSet) error {
+	return checkBreakingChanges(context.Background(), parallel.NewPool(0), docs)
+}
+
+// checkBreakingChanges checks documents as CheckBreakingChanges does, with
+// each pair of consecutive documents compared concurrently in the given pool.
+// Comparisons only read the documents, so these may share references.
+func checkBreakingChanges(ctx context.Context, pool *parallel.Pool, docs DocSet) error {
+	pairs := make([]int, 0, len(docs))
 	for i := 1; i < len(docs); i++ {
-		prevDoc := docs[i-1]
-		currDoc := docs[i]
+		pairs = append(pairs, i)
+	}
+	return parallel.ForEachPool(ctx, pool, pairs, func(_ context.Context, i int) error {
+		return checkBreakingChange(docs[i-1], docs[i])
+	})
+}
 
-		s1 := &load.SpecInfo{Spec: prevDoc.Doc}
-		s2 := &load.SpecInfo{Spec: currDoc.Doc}
+// checkBreakingChange returns an error if there is no breaking change from
+// prevDoc to currDoc.
+func checkBreakingChange(prevDoc, currDoc VersionedDoc) error {
+	s1 := &load.SpecInfo{Spec: prevDoc.Doc}
+	s2 := &load.SpecInfo{Spec: currDoc.Doc}
 
-		diffReport, sourcesMap, err := diff.GetWithOperationsSourcesMap(diff.NewConfig(), s1, s2)
-		if err != nil {
-			return err
-		}
-		changes := checker.CheckBackwardCompatibilityUntilLevel(
-			checker.NewConfig(checker.GetAllChecks()), diffReport, sourcesMap, checker.INFO)
-		breakingChange := false
-		for _, change := range changes {
-			if change.IsBreaking() {
-				breakingChange = true
-			}
-		}
-		if !breakingChange {
-			return fmt.Errorf("no breaking change detected between versions %s and %s: \n %s",
-				prevDoc.VersionDate, currDoc.VersionDate, changes)
+	diffReport, sourcesMap, err := diff.GetWithOperationsSourcesMap(diff.NewConfig(), s1, s2)
+	if err != nil {
+		return err
+	}
+	changes := checker.CheckBackwardCompatibilityUntilLevel(
+		checker.NewConfig(checker.GetAllChecks()), diffReport, sourcesMap, checker.INFO)
+	for _, change := range changes {
+		if change.IsBreaking() {
+			return nil
 		}
 	}
-	return nil
+	return fmt.Errorf("no breaking change detected between versions %s and %s: \n %s",
+		prevDoc.Version, currDoc.Version, changes)
 }
 
 func fetchLatestVersion(versioningURL string) (vervet.Version, error) {
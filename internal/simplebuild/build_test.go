@@ -3,8 +3,11 @@ package simplebuild_test
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"slices"
+	"strconv"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	qt "github.com/frankban/quicktest"
@@ -12,6 +15,7 @@ import (
 
 	"github.com/snyk/vervet/v8"
 	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/buildcache"
 	"github.com/snyk/vervet/v8/internal/simplebuild"
 )
 
@@ -37,7 +41,7 @@ func TestGetLatest(t *testing.T) {
 				ResourceName: "foo",
 			},
 		}
-		op := vs.GetLatest(before.Date)
+		op := vs.GetLatest(before)
 		c.Assert(op, qt.Equals, vs[1].Operation)
 	})
 
@@ -60,7 +64,7 @@ func TestGetLatest(t *testing.T) {
 				ResourceName: "foo",
 			},
 		}
-		op := vs.GetLatest(before.Date)
+		op := vs.GetLatest(before)
 		c.Assert(op, qt.Equals, vs[2].Operation)
 	})
 
@@ -88,7 +92,7 @@ func TestGetLatest(t *testing.T) {
 				ResourceName: "foo",
 			},
 		}
-		op := vs.GetLatest(before.Date)
+		op := vs.GetLatest(before)
 		c.Assert(op, qt.Equals, vs[2].Operation)
 	})
 }
@@ -108,7 +112,7 @@ func TestBuild(t *testing.T) {
 			}},
 		}
 		output := ops.Build(vervet.MustParseVersion("2024-01-01"), nil)
-		c.Assert(output[0].VersionDate, qt.Equals, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		c.Assert(output[0].Version.Date, qt.Equals, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
 		c.Assert(output[0].Doc.Paths.Value("/foo").Get, qt.IsNotNil)
 	})
 
@@ -129,12 +133,63 @@ func TestBuild(t *testing.T) {
 		output := ops.Build(vervet.MustParseVersion("2024-01-01"), openapi3.Servers{
 			expectedServer,
 		})
-		c.Assert(output[0].VersionDate, qt.Equals, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		c.Assert(output[0].Version.Date, qt.Equals, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
 		c.Assert(output[0].Doc.Paths.Value("/foo").Get, qt.IsNotNil)
 		c.Assert(output[0].Doc.Servers, qt.HasLen, 1)
 		c.Assert(output[0].Doc.Servers[0], qt.Equals, expectedServer)
 	})
 
+	c.Run("uses configured document metadata", func(c *qt.C) {
+		ops := simplebuild.Operations{
+			simplebuild.OpKey{
+				Path:   "/foo",
+				Method: "GET",
+			}: simplebuild.VersionSet{simplebuild.VersionedOp{
+				Version:      vervet.MustParseVersion("2024-01-01"),
+				Operation:    openapi3.NewOperation(),
+				ResourceName: "foo",
+			}, simplebuild.VersionedOp{
+				Version:      vervet.MustParseVersion("2024-02-01"),
+				Operation:    openapi3.NewOperation(),
+				ResourceName: "foo",
+			}},
+		}
+		docConfig := &config.Document{
+			OpenAPI: "3.0.2",
+			Info: &openapi3.Info{
+				Title:       "Foo API",
+				Description: "All about foo",
+				Version:     "2.0.0",
+				License:     &openapi3.License{Name: "MIT"},
+			},
+			SecuritySchemes: openapi3.SecuritySchemes{
+				"BearerAuth": &openapi3.SecuritySchemeRef{Value: openapi3.NewJWTSecurityScheme()},
+			},
+			Tags: openapi3.Tags{{Name: "Foo"}},
+		}
+		output := ops.BuildDocuments(vervet.MustParseVersion("2024-01-01"), docConfig)
+		c.Assert(output, qt.HasLen, 2)
+		for _, doc := range output {
+			c.Assert(doc.Doc.OpenAPI, qt.Equals, "3.0.2")
+			c.Assert(doc.Doc.Info, qt.DeepEquals, docConfig.Info)
+			c.Assert(doc.Doc.Components.SecuritySchemes["BearerAuth"], qt.Not(qt.IsNil))
+			c.Assert(doc.Doc.Tags, qt.DeepEquals, docConfig.Tags)
+		}
+
+		// Documents may be modified independently.
+		output[0].Doc.Info.Title = "Modified"
+		c.Assert(output[1].Doc.Info.Title, qt.Equals, "Foo API")
+		c.Assert(docConfig.Info.Title, qt.Equals, "Foo API")
+	})
+
+	c.Run("defaults document metadata", func(c *qt.C) {
+		doc := simplebuild.NewDocument(nil)
+		c.Assert(doc.OpenAPI, qt.Equals, "3.0.3")
+		c.Assert(doc.Info.Title, qt.Equals, "Snyk API")
+		c.Assert(doc.Info.Version, qt.Equals, "1.0.0")
+		c.Assert(doc.Components, qt.IsNil)
+	})
+
 	c.Run("merges operations from the same version", func(c *qt.C) {
 		version := vervet.MustParseVersion("2024-01-01")
 
@@ -169,7 +224,7 @@ func TestBuild(t *testing.T) {
 			}},
 		}
 		output := ops.Build(vervet.MustParseVersion("2024-01-01"), nil)
-		c.Assert(output[0].VersionDate, qt.Equals, version.Date)
+		c.Assert(output[0].Version.Date, qt.Equals, version.Date)
 		c.Assert(output[0].Doc.Paths.Value("/foo").Get, qt.Equals, getFoo)
 		c.Assert(output[0].Doc.Paths.Value("/foo").Post, qt.Equals, postFoo)
 		c.Assert(output[0].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
@@ -213,7 +268,7 @@ func TestBuild(t *testing.T) {
 
 		outputVersions := make([]time.Time, len(output))
 		for idx, out := range output {
-			outputVersions[idx] = out.VersionDate
+			outputVersions[idx] = out.Version.Date
 		}
 		slices.SortFunc(outputVersions, compareDates)
 
@@ -259,17 +314,17 @@ func TestBuild(t *testing.T) {
 
 		slices.SortFunc(output, compareDocs)
 
-		c.Assert(output[0].VersionDate, qt.Equals, versionA.Date)
+		c.Assert(output[0].Version.Date, qt.Equals, versionA.Date)
 		c.Assert(output[0].Doc.Paths.Value("/foo").Get, qt.Equals, getFoo)
 		c.Assert(output[0].Doc.Paths.Value("/foo").Post, qt.IsNil)
 		c.Assert(output[0].Doc.Paths.Value("/bar"), qt.IsNil)
 
-		c.Assert(output[1].VersionDate, qt.Equals, versionB.Date)
+		c.Assert(output[1].Version.Date, qt.Equals, versionB.Date)
 		c.Assert(output[1].Doc.Paths.Value("/foo").Get, qt.Equals, getFoo)
 		c.Assert(output[1].Doc.Paths.Value("/foo").Post, qt.Equals, postFoo)
 		c.Assert(output[1].Doc.Paths.Value("/bar"), qt.IsNil)
 
-		c.Assert(output[2].VersionDate, qt.Equals, versionC.Date)
+		c.Assert(output[2].Version.Date, qt.Equals, versionC.Date)
 		c.Assert(output[2].Doc.Paths.Value("/foo").Get, qt.Equals, getFoo)
 		c.Assert(output[2].Doc.Paths.Value("/foo").Post, qt.Equals, postFoo)
 		c.Assert(output[2].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
@@ -309,15 +364,15 @@ func TestBuild(t *testing.T) {
 
 		slices.SortFunc(output, compareDocs)
 
-		c.Assert(output[0].VersionDate, qt.Equals, versionA.Date)
+		c.Assert(output[0].Version.Date, qt.Equals, versionA.Date)
 		c.Assert(output[0].Doc.Paths.Value("/foo").Get, qt.Equals, getFooOld)
 		c.Assert(output[0].Doc.Paths.Value("/bar"), qt.IsNil)
 
-		c.Assert(output[1].VersionDate, qt.Equals, versionB.Date)
+		c.Assert(output[1].Version.Date, qt.Equals, versionB.Date)
 		c.Assert(output[1].Doc.Paths.Value("/foo").Get, qt.Equals, getFooOld)
 		c.Assert(output[1].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
 
-		c.Assert(output[2].VersionDate, qt.Equals, versionC.Date)
+		c.Assert(output[2].Version.Date, qt.Equals, versionC.Date)
 		c.Assert(output[2].Doc.Paths.Value("/foo").Get, qt.Equals, getFooNew)
 		c.Assert(output[2].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
 	})
@@ -358,11 +413,11 @@ func TestBuild(t *testing.T) {
 
 		c.Assert(len(output), qt.Equals, 2)
 
-		c.Assert(output[0].VersionDate, qt.Equals, versionB.Date)
+		c.Assert(output[0].Version.Date, qt.Equals, versionB.Date)
 		c.Assert(output[0].Doc.Paths.Value("/foo").Get, qt.Equals, getFooOld)
 		c.Assert(output[0].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
 
-		c.Assert(output[1].VersionDate, qt.Equals, versionC.Date)
+		c.Assert(output[1].Version.Date, qt.Equals, versionC.Date)
 		c.Assert(output[1].Doc.Paths.Value("/foo").Get, qt.Equals, getFooNew)
 		c.Assert(output[1].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
 	})
@@ -406,16 +461,16 @@ func TestBuild(t *testing.T) {
 
 		slices.SortFunc(output, compareDocs)
 
-		c.Assert(output[0].VersionDate, qt.Equals, versionBetaA.Date)
+		c.Assert(output[0].Version.Date, qt.Equals, versionBetaA.Date)
 		c.Assert(output[0].Doc.Paths.Value("/foo"), qt.IsNil)
 		c.Assert(output[0].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
 
-		c.Assert(output[1].VersionDate, qt.Equals, versionGA.Date)
+		c.Assert(output[1].Version.Date, qt.Equals, versionGA.Date)
 		c.Assert(output[1].Doc.Paths.Value("/foo").Get, qt.Equals, getFoo)
 		c.Assert(output[1].Doc.Paths.Value("/foo").Post, qt.IsNil)
 		c.Assert(output[0].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
 
-		c.Assert(output[2].VersionDate, qt.Equals, versionBetaB.Date)
+		c.Assert(output[2].Version.Date, qt.Equals, versionBetaB.Date)
 		c.Assert(output[2].Doc.Paths.Value("/foo").Get, qt.Equals, getFoo)
 		c.Assert(output[2].Doc.Paths.Value("/foo").Post, qt.Equals, postFoo)
 		c.Assert(output[2].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
@@ -480,17 +535,17 @@ func TestBuild(t *testing.T) {
 
 		c.Assert(output, qt.HasLen, 3)
 
-		c.Assert(output[0].VersionDate, qt.Equals, versionBetaA.Date)
+		c.Assert(output[0].Version.Date, qt.Equals, versionBetaA.Date)
 		c.Assert(output[0].Doc.Paths.Value("/foo"), qt.IsNil)
 		c.Assert(output[0].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
 		c.Assert(output[0].Doc.Paths.Value("/experimental-path-before-pivot-date"), qt.IsNil)
 
-		c.Assert(output[1].VersionDate, qt.Equals, versionGA.Date)
+		c.Assert(output[1].Version.Date, qt.Equals, versionGA.Date)
 		c.Assert(output[1].Doc.Paths.Value("/foo").Get, qt.Equals, getFoo)
 		c.Assert(output[1].Doc.Paths.Value("/foo").Post, qt.IsNil)
 		c.Assert(output[0].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
 
-		c.Assert(output[2].VersionDate, qt.Equals, versionBetaB.Date)
+		c.Assert(output[2].Version.Date, qt.Equals, versionBetaB.Date)
 		c.Assert(output[2].Doc.Paths.Value("/foo").Get, qt.Equals, getFoo)
 		c.Assert(output[2].Doc.Paths.Value("/foo").Post, qt.Equals, postFoo)
 		c.Assert(output[2].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
@@ -556,17 +611,17 @@ func TestBuild(t *testing.T) {
 
 		c.Assert(output, qt.HasLen, 3)
 
-		c.Assert(output[0].VersionDate, qt.Equals, versionBetaA.Date)
+		c.Assert(output[0].Version.Date, qt.Equals, versionBetaA.Date)
 		c.Assert(output[0].Doc.Paths.Value("/foo"), qt.IsNil)
 		c.Assert(output[0].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
 		c.Assert(output[0].Doc.Paths.Value("/wip-path-before-pivot-date"), qt.IsNil)
 
-		c.Assert(output[1].VersionDate, qt.Equals, versionGA.Date)
+		c.Assert(output[1].Version.Date, qt.Equals, versionGA.Date)
 		c.Assert(output[1].Doc.Paths.Value("/foo").Get, qt.Equals, getFoo)
 		c.Assert(output[1].Doc.Paths.Value("/foo").Post, qt.IsNil)
 		c.Assert(output[0].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
 
-		c.Assert(output[2].VersionDate, qt.Equals, versionBetaB.Date)
+		c.Assert(output[2].Version.Date, qt.Equals, versionBetaB.Date)
 		c.Assert(output[2].Doc.Paths.Value("/foo").Get, qt.Equals, getFoo)
 		c.Assert(output[2].Doc.Paths.Value("/foo").Post, qt.Equals, postFoo)
 		c.Assert(output[2].Doc.Paths.Value("/bar").Get, qt.Equals, getBar)
@@ -679,6 +734,32 @@ func TestAnnotate(t *testing.T) {
 		c.Assert(vs[0].Operation.Extensions["x-stability-level"], qt.Equals, "beta")
 		c.Assert(vs[0].Operation.Extensions["x-snyk-api-stability"], qt.Equals, "beta")
 	})
+
+	c.Run("sunsets according to the versioning policy", func(c *qt.C) {
+		vs := simplebuild.VersionSet{
+			simplebuild.VersionedOp{
+				Version:      vervet.MustParseVersion("2024-01-01~beta"),
+				Operation:    openapi3.NewOperation(),
+				ResourceName: "foo",
+			},
+			simplebuild.VersionedOp{
+				Version:      vervet.MustParseVersion("2024-02-01"),
+				Operation:    openapi3.NewOperation(),
+				ResourceName: "foo",
+			},
+			simplebuild.VersionedOp{
+				Version:      vervet.MustParseVersion("2024-03-01"),
+				Operation:    openapi3.NewOperation(),
+				ResourceName: "foo",
+			},
+		}
+		policy := vervet.DefaultVersioningPolicy()
+		policy.SunsetBeta = 30 * 24 * time.Hour
+		policy.SunsetGA = 365 * 24 * time.Hour
+		vs.AnnotateWithPolicy(policy)
+		c.Assert(vs[0].Operation.Extensions[vervet.ExtSnykSunsetEligible], qt.Equals, "2024-03-02")
+		c.Assert(vs[1].Operation.Extensions[vervet.ExtSnykSunsetEligible], qt.Equals, "2025-03-01")
+	})
 }
 
 func TestCheckBreakingChanges(t *testing.T) {
@@ -704,12 +785,12 @@ func TestCheckBreakingChanges(t *testing.T) {
 
 		docs := simplebuild.DocSet{
 			{
-				VersionDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-				Doc:         doc1,
+				Version: vervet.MustParseVersion("2024-01-01"),
+				Doc:     doc1,
 			},
 			{
-				VersionDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
-				Doc:         doc2,
+				Version: vervet.MustParseVersion("2024-02-01"),
+				Doc:     doc2,
 			},
 		}
 
@@ -737,12 +818,12 @@ func TestCheckBreakingChanges(t *testing.T) {
 
 		docs := simplebuild.DocSet{
 			{
-				VersionDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-				Doc:         doc1,
+				Version: vervet.MustParseVersion("2024-01-01"),
+				Doc:     doc1,
 			},
 			{
-				VersionDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
-				Doc:         doc2,
+				Version: vervet.MustParseVersion("2024-02-01"),
+				Doc:     doc2,
 			},
 		}
 
@@ -797,7 +878,7 @@ func TestCheckSingleVersionResource(t *testing.T) {
 }
 
 func compareDocs(a, b simplebuild.VersionedDoc) int {
-	return a.VersionDate.Compare(b.VersionDate)
+	return a.Version.Compare(b.Version)
 }
 func compareDates(a, b time.Time) int {
 	return a.Compare(b)
@@ -879,3 +960,59 @@ func TestBuildSkipsVersionCheckWhenFetchFails(t *testing.T) {
 	err = simplebuild.Build(ctx, dummyProject, startDate, failingURL, false)
 	c.Assert(err, qt.IsNil)
 }
+
+func TestBuildFS(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	fsys := fstest.MapFS{
+		"CODEOWNERS":   {Data: []byte("/resources/things/ @snyk/things\n")},
+		"include.yaml": {Data: []byte("servers: [{url: 'https://example.com/api'}]\n")},
+	}
+	// Each version requires a new parameter, so that it is a breaking change.
+	for i, version := range []string{"2024-01-01", "2024-02-01", "2024-03-01"} {
+		fsys["resources/things/"+version+"/spec.yaml"] = &fstest.MapFile{Data: []byte(`
+openapi: 3.0.3
+x-snyk-api-stability: ga
+info: {title: things, version: 3.0.0}
+paths:
+  /things:
+    get:
+      description: ` + version + `
+      parameters:
+        - {name: p` + strconv.Itoa(i) + `, in: query, required: true, schema: {type: string}}
+      responses:
+        '204': {description: No content}
+`)}
+	}
+	outputDir := c.TempDir()
+	project := &config.Project{
+		APIs: config.APIs{
+			"things": &config.API{
+				Name: "things",
+				Resources: []*config.ResourceSet{{
+					Path:     "resources",
+					Excludes: []string{"resources/things/2024-03-01/**"},
+				}},
+				Overlays: []*config.Overlay{{Include: "include.yaml"}},
+				Output:   &config.Output{Paths: []string{outputDir}},
+			},
+		},
+	}
+	startDate := vervet.MustParseVersion("2024-01-01")
+
+	err := simplebuild.Build(ctx, project, startDate, "http://localhost:0", false,
+		simplebuild.FS(fsys), simplebuild.Cache(&buildcache.Cache{}))
+	c.Assert(err, qt.ErrorMatches, "cannot cache builds loaded from a filesystem")
+
+	err = simplebuild.Build(ctx, project, startDate, "http://localhost:0", false, simplebuild.FS(fsys))
+	c.Assert(err, qt.IsNil)
+	doc, err := vervet.NewDocumentFile(filepath.Join(outputDir, "2024-02-01", "spec.yaml"))
+	c.Assert(err, qt.IsNil)
+	op := doc.Paths.Value("/things").Get
+	c.Assert(op.Description, qt.Equals, "2024-02-01")
+	c.Assert(op.Extensions[vervet.ExtSnykApiOwner], qt.DeepEquals, []interface{}{"@snyk/things"})
+	c.Assert(doc.Servers[0].URL, qt.Equals, "https://example.com/api")
+	// Excluded specs are matched within the filesystem.
+	_, err = os.Stat(filepath.Join(outputDir, "2024-03-01"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+}
@@ -0,0 +1,200 @@
+package simplebuild
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/buildcache"
+)
+
+// cacheKey returns the build cache key of an output version of an API.
+func cacheKey(apiName string, release vervet.Version) string {
+	return cacheKeyPrefix(apiName) + release.DirName()
+}
+
+func cacheKeyPrefix(apiName string) string {
+	return "simplebuild/" + apiName + "/"
+}
+
+// cachedVersions describes the output versions of an API with respect to
+// the build cache.
+type cachedVersions struct {
+	apiName   string
+	outputDir string
+
+	// digests are the input digests of the versions to be built.
+	digests map[vervet.Version]buildcache.Digest
+
+	// cached are the versions which are unchanged since the last build.
+	cached []vervet.Version
+
+	// stale are the versions previously built which are no longer built.
+	stale []vervet.Version
+}
+
+// lookupCachedVersions looks up the output versions of an API in the build
+// cache. Without a cache, nothing is cached. If the inputs of the API cannot
+// be determined, an error is returned along with the versions, none of which
+// are cached.
+func lookupCachedVersions(
+	cache *buildcache.Cache, inputs *buildcache.Inputs, apiConfig *config.API,
+	startDate vervet.Version, latestVersion *vervet.Version,
+) (cachedVersions, error) {
+	versions := cachedVersions{apiName: apiConfig.Name, outputDir: apiConfig.Output.Paths[0]}
+	if cache == nil {
+		return versions, nil
+	}
+	digests, err := versionInputs(inputs, apiConfig, startDate, latestVersion)
+	versions.digests = digests
+	versions.stale = staleVersions(cache, apiConfig.Name, digests)
+	for release, digest := range digests {
+		if cache.Lookup(cacheKey(apiConfig.Name, release), digest) {
+			versions.cached = append(versions.cached, release)
+		}
+	}
+	slices.SortFunc(versions.cached, vervet.Version.Compare)
+	return versions, err
+}
+
+// upToDate returns whether all the output versions of the API are unchanged
+// since the last build, so that it need not be built at all.
+func (cv cachedVersions) upToDate() bool {
+	if len(cv.digests) == 0 || len(cv.cached) != len(cv.digests) || len(cv.stale) > 0 {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(cv.outputDir, "embed.go"))
+	return err == nil
+}
+
+// store records the rendered versions in the build cache.
+func (cv cachedVersions) store(cache *buildcache.Cache, writer *DocWriter, rendered []RenderedDoc) error {
+	for _, doc := range rendered {
+		digest, ok := cv.digests[doc.Version]
+		if !ok {
+			continue
+		}
+		err := cache.Store(cacheKey(cv.apiName, doc.Version), digest, writer.OutputFiles(doc.Version))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// versionInputs returns the digest of the inputs of each version expected to
+// be built for an API, keyed by release.
+//
+// An output version contains the latest operations of each resource released
+// on or before its release. Operations are annotated with the releases of all
+// versions of their resource, so an output depends on every version of the
+// resources it includes, as well as any other resources declaring the same
+// paths. The output versions are those of the released resource versions, as
+// selected by BuildDocuments. Each digest also covers the latest version of
+// the global API which resources were checked against, if any.
+func versionInputs(
+	inputs *buildcache.Inputs, apiConfig *config.API, startDate vervet.Version, latestVersion *vervet.Version,
+) (map[vervet.Version]buildcache.Digest, error) {
+	apiParts, err := inputs.APIParts(apiConfig)
+	if err != nil {
+		return nil, err
+	}
+	resourceFiles, err := inputs.ResourceFiles(apiConfig)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := apiConfig.VersioningPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	resources := map[string][]buildcache.ResourceFile{}
+	resourceStart := map[string]vervet.Version{}
+	pathResources := map[string][]string{}
+	releases := map[vervet.Version]struct{}{}
+	for _, rf := range resourceFiles {
+		resources[rf.Resource] = append(resources[rf.Resource], rf)
+		release := releaseOf(rf.Version)
+		if start, ok := resourceStart[rf.Resource]; !ok || release.Compare(start) < 0 {
+			resourceStart[rf.Resource] = release
+		}
+		for _, p := range rf.Paths {
+			pathResources[p] = append(pathResources[p], rf.Resource)
+		}
+		if isReleased(rf.Version) {
+			releases[release] = struct{}{}
+		}
+	}
+	outputReleases := filterReleasesByStart(slices.Collect(maps.Keys(releases)), startDate)
+	latest := ""
+	if latestVersion != nil {
+		latest = latestVersion.String()
+	}
+
+	digests := make(map[vervet.Version]buildcache.Digest, len(outputReleases))
+	for _, outputRelease := range outputReleases {
+		included := map[string]bool{}
+		var pending []string
+		for resource, start := range resourceStart {
+			if start.Compare(outputRelease) <= 0 {
+				included[resource] = true
+				pending = append(pending, resource)
+			}
+		}
+		for len(pending) > 0 {
+			resource := pending[0]
+			pending = pending[1:]
+			for _, rf := range resources[resource] {
+				for _, p := range rf.Paths {
+					for _, other := range pathResources[p] {
+						if !included[other] {
+							included[other] = true
+							pending = append(pending, other)
+						}
+					}
+				}
+			}
+		}
+		includedNames := make([]string, 0, len(included))
+		for resource := range included {
+			includedNames = append(includedNames, resource)
+		}
+		sort.Strings(includedNames)
+
+		parts := append([]string{
+			"simplebuild", startDate.String(), latest, outputRelease.DirName(),
+		}, apiParts...)
+		for _, resource := range includedNames {
+			for _, rf := range resources[resource] {
+				parts = append(parts, rf.Parts(policy)...)
+			}
+		}
+		digests[outputRelease] = buildcache.NewDigest(parts...)
+	}
+	return digests, nil
+}
+
+// staleVersions returns the versions of an API cached by a previous build
+// which are no longer built, removing them from the cache.
+func staleVersions(
+	cache *buildcache.Cache, apiName string, digests map[vervet.Version]buildcache.Digest,
+) []vervet.Version {
+	var stale []vervet.Version
+	prefix := cacheKeyPrefix(apiName)
+	for _, key := range cache.Keys(prefix) {
+		release, err := vervet.ParseAnyVersion(strings.TrimPrefix(key, prefix))
+		if err == nil {
+			if _, ok := digests[release]; ok {
+				continue
+			}
+			stale = append(stale, release)
+		}
+		cache.Delete(key)
+	}
+	return stale
+}
@@ -0,0 +1,208 @@
+package simplebuild_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/buildcache"
+	"github.com/snyk/vervet/v8/internal/simplebuild"
+)
+
+const testSpec = `
+openapi: 3.0.3
+x-snyk-api-stability: beta
+info:
+  title: things
+  version: 3.0.0
+paths:
+  /things:
+    get:
+      operationId: listThings
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [%s]
+                properties:
+%s
+`
+
+// setupTestProject creates a project in a temporary working directory, with
+// a "things" resource at two beta versions.
+func setupTestProject(c *qt.C) (projectDir string, project *config.Project) {
+	projectDir = c.TempDir()
+	origWD, err := os.Getwd()
+	c.Assert(err, qt.IsNil)
+	c.Assert(os.Chdir(projectDir), qt.IsNil)
+	c.Cleanup(func() {
+		c.Assert(os.Chdir(origWD), qt.IsNil)
+	})
+	c.Assert(os.WriteFile("CODEOWNERS", []byte(""), 0644), qt.IsNil)
+
+	writeTestSpec(c, "2024-01-01", "id", "name")
+	writeTestSpec(c, "2024-02-01", "id")
+
+	project = &config.Project{
+		APIs: config.APIs{
+			"things": &config.API{
+				Name:      "things",
+				Resources: []*config.ResourceSet{{Path: "resources"}},
+				Output:    &config.Output{Paths: []string{"out"}},
+			},
+		},
+	}
+	return projectDir, project
+}
+
+// writeTestSpec writes a version of the "things" resource, which responds
+// with the given required properties.
+func writeTestSpec(c *qt.C, version string, properties ...string) {
+	var props string
+	for _, p := range properties {
+		props += "                  " + p + ": {type: string}\n"
+	}
+	dir := filepath.Join("resources", "things", version)
+	c.Assert(os.MkdirAll(dir, 0755), qt.IsNil)
+	spec := []byte(fmt.Sprintf(testSpec, strings.Join(properties, ", "), props))
+	c.Assert(os.WriteFile(filepath.Join(dir, "spec.yaml"), spec, 0644), qt.IsNil)
+}
+
+func TestBuildCache(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	projectDir, project := setupTestProject(c)
+
+	startDate := vervet.MustParseVersion("2024-01-01")
+	cache, err := buildcache.Open(c.TempDir(), projectDir)
+	c.Assert(err, qt.IsNil)
+	build := func(appendOutputFiles bool) {
+		err := simplebuild.Build(ctx, project, startDate, "http://localhost:0", appendOutputFiles,
+			simplebuild.Cache(cache))
+		c.Assert(err, qt.IsNil)
+	}
+
+	build(false)
+	c.Assert(cache.Stats(), qt.Equals, buildcache.Stats{Misses: 2})
+	uncached, err := os.ReadFile(filepath.Join("out", "2024-02-01", "spec.json"))
+	c.Assert(err, qt.IsNil)
+
+	// Unchanged versions are not written again.
+	marker := time.Unix(0, 0)
+	specPath := filepath.Join("out", "2024-01-01", "spec.json")
+	c.Assert(os.Chtimes(specPath, marker, marker), qt.IsNil)
+	build(false)
+	c.Assert(cache.Stats(), qt.Equals, buildcache.Stats{Hits: 2, Misses: 2})
+	info, err := os.Stat(specPath)
+	c.Assert(err, qt.IsNil)
+	c.Assert(info.ModTime().Equal(marker), qt.IsTrue)
+
+	// Modified outputs are written again.
+	c.Assert(os.WriteFile(filepath.Join("out", "2024-02-01", "spec.json"), []byte("{}"), 0644), qt.IsNil)
+	build(false)
+	cached, err := os.ReadFile(filepath.Join("out", "2024-02-01", "spec.json"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(cached), qt.Equals, string(uncached))
+
+	// Versions no longer built are removed, even when appending to the
+	// output.
+	c.Assert(os.RemoveAll(filepath.Join("resources", "things", "2024-02-01")), qt.IsNil)
+	build(true)
+	_, err = os.Stat(filepath.Join("out", "2024-02-01"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+	embed, err := os.ReadFile(filepath.Join("out", "embed.go"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(embed), qt.Not(qt.Contains), "2024-02-01")
+	c.Assert(cache.Keys(""), qt.DeepEquals, []string{"simplebuild/things/2024-01-01"})
+}
+
+func TestBuildCacheUpToDate(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	projectDir, project := setupTestProject(c)
+	project.APIs["things"].Output.Paths = []string{"out", "copy"}
+	// Experimental versions are not built, so do not affect whether the
+	// build is up to date.
+	writeTestSpec(c, "2024-03-01~experimental", "id", "color")
+
+	startDate := vervet.MustParseVersion("2024-01-01")
+	cache, err := buildcache.Open(c.TempDir(), projectDir)
+	c.Assert(err, qt.IsNil)
+	build := func() *simplebuild.BuildReport {
+		report := &simplebuild.BuildReport{}
+		err := simplebuild.Build(ctx, project, startDate, "http://localhost:0", false,
+			simplebuild.Cache(cache), simplebuild.Report(report))
+		c.Assert(err, qt.IsNil)
+		return report
+	}
+
+	c.Assert(build().APIs[0].UpToDate, qt.IsFalse)
+	c.Assert(os.RemoveAll("copy"), qt.IsNil)
+
+	// Other output paths are copied again when the build is up to date.
+	c.Assert(build().APIs[0].UpToDate, qt.IsTrue)
+	for _, path := range []string{"embed.go", "2024-01-01/spec.json", "2024-02-01/spec.json"} {
+		_, err := os.Stat(filepath.Join("copy", path))
+		c.Assert(err, qt.IsNil)
+	}
+	_, err = os.Stat(filepath.Join("copy", "2024-03-01"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+}
+
+func TestBuildVersionScheme(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	projectDir, project := setupTestProject(c)
+	api := project.APIs["things"]
+	api.VersionScheme = vervet.DateSequenceScheme
+	api.Resources[0].VersionScheme = vervet.DateSequenceScheme
+	writeTestSpec(c, "2024-01-01", "id", "name", "color")
+	writeTestSpec(c, "2024-02-01", "id", "name")
+	writeTestSpec(c, "2024-02-01.2", "id")
+
+	// Each release on the same date is built as a version of its own.
+	cache, err := buildcache.Open(c.TempDir(), projectDir)
+	c.Assert(err, qt.IsNil)
+	build := func() *simplebuild.BuildReport {
+		report := &simplebuild.BuildReport{}
+		err := simplebuild.Build(ctx, project, vervet.MustParseVersion("2024-01-01"), "http://localhost:0", false,
+			simplebuild.Cache(cache), simplebuild.Report(report))
+		c.Assert(err, qt.IsNil)
+		return report
+	}
+	report := build()
+	var versions []string
+	for _, version := range report.APIs[0].Versions {
+		versions = append(versions, version.Version)
+	}
+	c.Assert(versions, qt.DeepEquals, []string{"2024-01-01", "2024-02-01", "2024-02-01.2"})
+	for version, required := range map[string][]string{"2024-02-01": {"id", "name"}, "2024-02-01.2": {"id"}} {
+		doc, err := openapi3.NewLoader().LoadFromFile(filepath.Join("out", version, "spec.json"))
+		c.Assert(err, qt.IsNil)
+		c.Assert(doc.Extensions[vervet.ExtSnykApiVersion], qt.Equals, version)
+		schema := doc.Paths.Value("/things").Get.Responses.Status(200).Value.Content.Get("application/json").Schema
+		c.Assert(schema.Value.Required, qt.DeepEquals, required)
+	}
+	c.Assert(build().APIs[0].UpToDate, qt.IsTrue)
+
+	// Semantic versions are not dated, so none are built.
+	api.VersionScheme = vervet.SemverScheme
+	report = &simplebuild.BuildReport{}
+	err = simplebuild.Build(ctx, project, vervet.MustParseVersion("2024-01-01"), "http://localhost:0", false,
+		simplebuild.Report(report))
+	c.Assert(err, qt.IsNil)
+	c.Assert(report.Warnings, qt.Contains,
+		"Versions of things are not dated, so none are on or after the pivot version 2024-01-01")
+}
@@ -0,0 +1,245 @@
+package simplebuild
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/snyk/vervet/v8"
+)
+
+// Explanation describes why an operation appears in a compiled version of an
+// API, and which version of the operation it is.
+type Explanation struct {
+	// Operation is the operation explained.
+	Operation OpKey
+
+	// Requested is the version requested.
+	Requested vervet.Version
+
+	// Compiled is the compiled version which serves the requested version.
+	Compiled vervet.Version
+
+	// Simplified indicates that the compiled version is built with the
+	// simplified versioning rules, on or after the pivot version. Otherwise
+	// each resource is resolved on its own at the requested stability.
+	Simplified bool
+
+	// Pivot is the version on which simplified versioning begins.
+	Pivot vervet.Version
+
+	// Policy is the versioning policy of the API.
+	Policy *vervet.VersioningPolicy
+
+	// Candidates are all the versions declaring the operation, in version
+	// order.
+	Candidates []Candidate
+
+	// Chosen is the candidate included in the compiled version, or nil if the
+	// operation does not appear in it.
+	Chosen *Candidate
+
+	// Rule explains why the chosen candidate was chosen, or why none was.
+	Rule string
+
+	// Lifecycle is the lifecycle of the chosen version today.
+	Lifecycle vervet.Lifecycle
+
+	// DeprecatedBy is the version which deprecates the chosen version, if
+	// any.
+	DeprecatedBy *vervet.Version
+
+	// Sunset is the date after which the chosen version may be sunset, if it
+	// is deprecated.
+	Sunset time.Time
+}
+
+// Candidate is a version of an operation considered for a compiled version.
+type Candidate struct {
+	VersionedOp
+
+	// Reason explains why the candidate was not chosen. Empty if it was.
+	Reason string
+}
+
+// Explain explains why an operation appears in the version of an API
+// requested, given all the versions of the operations in the API, as loaded
+// by LoadPaths.
+//
+// On or after the pivot version, an output version contains the latest beta
+// or GA version of each operation released on or before its date; GA versions
+// take precedence over beta versions. Before the pivot version, each resource
+// is resolved to its most stable version effective on the requested date, at
+// the requested stability or above. The pivot version, lifecycle and sunset
+// dates are those of the given versioning policy.
+func Explain(
+	ops Operations, opKey OpKey, requested vervet.Version, policy *vervet.VersioningPolicy,
+) (*Explanation, error) {
+	versionSet, ok := ops[opKey]
+	if !ok || len(versionSet) == 0 {
+		return nil, fmt.Errorf("operation %s %s is not declared in any resource version", opKey.Method, opKey.Path)
+	}
+	candidates := make([]Candidate, len(versionSet))
+	for i := range versionSet {
+		candidates[i] = Candidate{VersionedOp: versionSet[i]}
+	}
+	slices.SortStableFunc(candidates, func(a, b Candidate) int {
+		return a.Version.Compare(b.Version)
+	})
+
+	e := &Explanation{
+		Operation:  opKey,
+		Requested:  requested,
+		Pivot:      policy.PivotDate,
+		Policy:     policy,
+		Simplified: !requested.Date.Before(policy.PivotDate.Date),
+		Candidates: candidates,
+	}
+	if e.Simplified {
+		e.explainSimplified(ops)
+	} else {
+		e.explainResolved(ops)
+	}
+	e.explainLifecycle()
+	return e, nil
+}
+
+// explainSimplified explains the choice of candidate on or after the pivot
+// version, in the same way as Operations.BuildDocuments.
+func (e *Explanation) explainSimplified(ops Operations) {
+	// The compiled version is the latest output version on or before the
+	// requested date.
+	e.Compiled = releaseOf(e.Pivot)
+	requested := releaseOf(e.Requested)
+	for _, release := range filterBetaAndGAVersions(ops).Releases() {
+		if release.Compare(e.Compiled) > 0 && release.Compare(requested) <= 0 {
+			e.Compiled = release
+		}
+	}
+
+	var eligible VersionSet
+	for i := range e.Candidates {
+		c := &e.Candidates[i]
+		switch {
+		case c.Version.Stability != vervet.StabilityGA && c.Version.Stability != vervet.StabilityBeta:
+			c.Reason = fmt.Sprintf("%s versions are not compiled on or after the pivot version %s",
+				c.Version.Stability, e.Pivot.DateString())
+		case releaseOf(c.Version).Compare(e.Compiled) > 0:
+			c.Reason = "released after " + e.Compiled.String()
+		default:
+			eligible = append(eligible, c.VersionedOp)
+		}
+	}
+
+	chosen := eligible.GetLatestVersion(e.Compiled)
+	if chosen == nil {
+		e.Rule = fmt.Sprintf("no beta or GA version is released on or before %s", e.Compiled.String())
+		return
+	}
+	e.Rule = fmt.Sprintf("the latest beta or GA version released on or before %s", e.Compiled.String())
+	for i := range e.Candidates {
+		c := &e.Candidates[i]
+		if c.Reason != "" {
+			continue
+		}
+		switch {
+		case c.Version.Compare(chosen.Version) == 0 && c.Source == chosen.Source:
+			e.Chosen = c
+		case releaseOf(c.Version).Compare(releaseOf(chosen.Version)) > 0:
+			c.Reason = fmt.Sprintf("%s takes precedence, as GA versions take precedence over beta versions",
+				chosen.Version)
+			e.Rule = fmt.Sprintf("the latest GA version released on or before %s, "+
+				"as GA versions take precedence over beta versions", e.Compiled.String())
+		default:
+			c.Reason = "superseded by " + chosen.Version.String()
+		}
+	}
+}
+
+// explainResolved explains the choice of candidate before the pivot
+// version, where each resource is resolved on its own in the same way as
+// vervet.ResourceVersions.At.
+func (e *Explanation) explainResolved(ops Operations) {
+	e.Compiled = e.Requested
+
+	// All the versions of each resource declaring the operation, including
+	// those versions which do not declare it.
+	resourceVersions := map[string]vervet.VersionSlice{}
+	for _, versionSet := range ops {
+		for _, op := range versionSet {
+			dir := resourceDir(op)
+			sameVersion := func(v vervet.Version) bool { return v.Compare(op.Version) == 0 }
+			if !slices.ContainsFunc(resourceVersions[dir], sameVersion) {
+				resourceVersions[dir] = append(resourceVersions[dir], op.Version)
+			}
+		}
+	}
+
+	resolved := map[string]vervet.Version{}
+	for i := range e.Candidates {
+		c := &e.Candidates[i]
+		dir := resourceDir(c.VersionedOp)
+		resolvedVersion, ok := resolved[dir]
+		if !ok {
+			var err error
+			index := vervet.NewVersionIndex(resourceVersions[dir])
+			resolvedVersion, err = index.ResolveForBuild(e.Requested)
+			if errors.Is(err, vervet.ErrNoMatchingVersion) {
+				resolvedVersion = vervet.Version{}
+			}
+			resolved[dir] = resolvedVersion
+		}
+		switch {
+		case resolvedVersion.Date.IsZero():
+			c.Reason = fmt.Sprintf("resource %s has no %s or more stable version released on or before %s",
+				c.ResourceName, e.Requested.Stability, e.Requested.DateString())
+		case c.Version.Compare(resolvedVersion) != 0:
+			c.Reason = fmt.Sprintf("resource %s resolves to %s", c.ResourceName, resolvedVersion)
+		case e.Chosen != nil:
+			c.Reason = fmt.Sprintf("conflicts with %s in resource %s", e.Chosen.Version, e.Chosen.ResourceName)
+		default:
+			e.Chosen = c
+		}
+	}
+	if e.Chosen == nil {
+		e.Rule = fmt.Sprintf("no resource version declaring the operation is effective on %s", e.Requested)
+		return
+	}
+	e.Rule = fmt.Sprintf("the most stable version of resource %s released on or before %s, at %s stability or above",
+		e.Chosen.ResourceName, e.Requested.DateString(), e.Requested.Stability)
+}
+
+// explainLifecycle determines the lifecycle of the chosen version, and when
+// it may be sunset if it is deprecated, in the same way as
+// VersionSet.AnnotateWithPolicy.
+func (e *Explanation) explainLifecycle() {
+	if e.Chosen == nil {
+		return
+	}
+	e.Lifecycle = e.Policy.LifecycleAt(e.Chosen.Version, time.Time{})
+	idx := -1
+	for i := range e.Candidates {
+		if &e.Candidates[i] == e.Chosen {
+			idx = i
+		}
+	}
+	if idx < 0 || idx == len(e.Candidates)-1 {
+		return
+	}
+	next := e.Candidates[idx+1].Version
+	if !e.Chosen.Version.DeprecatedBy(next) {
+		return
+	}
+	e.DeprecatedBy = &next
+	if sunset, ok := e.Policy.Sunset(e.Chosen.Version, next); ok {
+		e.Sunset = sunset
+	}
+}
+
+// resourceDir returns the directory containing all the versions of the
+// resource declaring an operation.
+func resourceDir(op VersionedOp) string {
+	return filepath.Dir(filepath.Dir(op.Source))
+}
@@ -0,0 +1,96 @@
+package simplebuild_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/internal/simplebuild"
+)
+
+func explainOp(version, resource string) simplebuild.VersionedOp {
+	return simplebuild.VersionedOp{
+		Version:      vervet.MustParseVersion(version),
+		Operation:    openapi3.NewOperation(),
+		ResourceName: resource,
+		Source:       "resources/" + resource + "/" + version + "/spec.yaml",
+	}
+}
+
+func TestExplain(t *testing.T) {
+	c := qt.New(t)
+	getThings := simplebuild.OpKey{Path: "/orgs/{org_id}/things", Method: "GET"}
+	ops := simplebuild.Operations{
+		getThings: {
+			explainOp("2024-12-01~experimental", "things"),
+			explainOp("2023-06-01~beta", "things"),
+			explainOp("2024-11-15~beta", "things"),
+			explainOp("2024-06-01", "things"),
+			explainOp("2025-01-01", "things"),
+		},
+		{Path: "/orgs/{org_id}/other", Method: "GET"}: {
+			explainOp("2024-10-20", "other"),
+			explainOp("2023-01-01~beta", "other"),
+		},
+	}
+	policy := vervet.DefaultVersioningPolicy()
+	policy.PivotDate = vervet.MustParseVersion("2024-10-15")
+
+	c.Run("simplified versioning", func(c *qt.C) {
+		e, err := simplebuild.Explain(ops, getThings, vervet.MustParseVersion("2024-12-10"), policy)
+		c.Assert(err, qt.IsNil)
+		c.Assert(e.Simplified, qt.IsTrue)
+		c.Assert(e.Compiled.DateString(), qt.Equals, "2024-11-15")
+		c.Assert(e.Chosen, qt.Not(qt.IsNil))
+		c.Assert(e.Chosen.Version.String(), qt.Equals, "2024-06-01")
+		c.Assert(e.Rule, qt.Matches, `the latest GA version .* GA versions take precedence over beta versions`)
+
+		reasons := map[string]string{}
+		for _, candidate := range e.Candidates {
+			reasons[candidate.Version.String()] = candidate.Reason
+		}
+		c.Assert(reasons, qt.DeepEquals, map[string]string{
+			"2023-06-01~beta":         "superseded by 2024-06-01",
+			"2024-06-01":              "",
+			"2024-11-15~beta":         "2024-06-01 takes precedence, as GA versions take precedence over beta versions",
+			"2024-12-01~experimental": "experimental versions are not compiled on or after the pivot version 2024-10-15",
+			"2025-01-01":              "released after 2024-11-15",
+		})
+		c.Assert(e.DeprecatedBy, qt.IsNil)
+	})
+
+	c.Run("deprecation and sunset", func(c *qt.C) {
+		e, err := simplebuild.Explain(ops, getThings, vervet.MustParseVersion("2025-02-01"), policy)
+		c.Assert(err, qt.IsNil)
+		c.Assert(e.Chosen.Version.String(), qt.Equals, "2025-01-01")
+
+		e, err = simplebuild.Explain(ops, getThings, vervet.MustParseVersion("2023-07-01~beta"), policy)
+		c.Assert(err, qt.IsNil)
+		c.Assert(e.Simplified, qt.IsFalse)
+		c.Assert(e.Chosen.Version.String(), qt.Equals, "2023-06-01~beta")
+		c.Assert(e.DeprecatedBy.String(), qt.Equals, "2024-06-01")
+		c.Assert(e.Sunset.Format("2006-01-02"), qt.Equals, "2024-08-31")
+	})
+
+	c.Run("resolved before the pivot version", func(c *qt.C) {
+		e, err := simplebuild.Explain(ops, getThings, vervet.MustParseVersion("2023-07-01"), policy)
+		c.Assert(err, qt.IsNil)
+		c.Assert(e.Simplified, qt.IsFalse)
+		c.Assert(e.Chosen, qt.IsNil)
+		c.Assert(e.Candidates[0].Reason, qt.Equals,
+			"resource things has no ga or more stable version released on or before 2023-07-01")
+
+		e, err = simplebuild.Explain(ops, getThings, vervet.MustParseVersion("2024-07-01~beta"), policy)
+		c.Assert(err, qt.IsNil)
+		c.Assert(e.Chosen.Version.String(), qt.Equals, "2024-06-01")
+		c.Assert(e.Candidates[0].Reason, qt.Equals, "resource things resolves to 2024-06-01")
+	})
+
+	c.Run("undeclared operation", func(c *qt.C) {
+		_, err := simplebuild.Explain(ops, simplebuild.OpKey{Path: "/nope", Method: "GET"},
+			vervet.MustParseVersion("2024-12-10"), policy)
+		c.Assert(err, qt.ErrorMatches, `operation GET /nope is not declared in any resource version`)
+	})
+}
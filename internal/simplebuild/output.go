@@ -2,13 +2,15 @@ package simplebuild
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"sort"
-	"time"
 
 	"github.com/ghodss/yaml"
 
@@ -16,31 +18,49 @@ import (
 	"github.com/snyk/vervet/v8/config"
 	"github.com/snyk/vervet/v8/internal/compiler"
 	"github.com/snyk/vervet/v8/internal/files"
+	"github.com/snyk/vervet/v8/internal/parallel"
 )
 
 type DocWriter struct {
 	cfg              config.Output
 	paths            []string
 	versionSpecFiles []string
+	// log is where the paths of written files are printed.
+	log io.Writer
 }
 
 // NewWriter initialises any output paths, removing existing files and
 // directories if they are present.
 func NewWriter(cfg config.Output, appendOutputFiles bool) (*DocWriter, error) {
-	paths := cfg.Paths
-	toClear := paths
-	if appendOutputFiles {
-		// We treat the first path as the source of truth and copy the whole
-		// directory to the other paths in Finalize.
-		toClear = toClear[1:]
-	}
+	return NewCachedWriter(cfg, appendOutputFiles, nil, nil)
+}
 
-	for _, dir := range toClear {
+// NewCachedWriter initialises output paths like NewWriter, except that the
+// output of the cached versions in the first path is kept as is, and the
+// output of stale versions is removed even when appending. Cached versions
+// are listed in the embedded files without being written again.
+func NewCachedWriter(cfg config.Output, appendOutputFiles bool, cached, stale []vervet.Version) (*DocWriter, error) {
+	paths := cfg.Paths
+	// We treat the first path as the source of truth and copy the whole
+	// directory to the other paths in Finalize.
+	for _, dir := range paths[1:] {
 		err := os.RemoveAll(dir)
 		if err != nil {
 			return nil, fmt.Errorf("clear output directory: %w", err)
 		}
 	}
+	if !appendOutputFiles {
+		err := clearOutputDir(paths[0], cached)
+		if err != nil {
+			return nil, fmt.Errorf("clear output directory: %w", err)
+		}
+	}
+	for _, release := range stale {
+		err := os.RemoveAll(path.Join(paths[0], release.DirName()))
+		if err != nil {
+			return nil, fmt.Errorf("remove stale version: %w", err)
+		}
+	}
 	err := os.MkdirAll(paths[0], 0777)
 	if err != nil {
 		return nil, fmt.Errorf("make output directory: %w", err)
@@ -55,63 +75,145 @@ func NewWriter(cfg config.Output, appendOutputFiles bool) (*DocWriter, error) {
 		cfg:              cfg,
 		paths:            paths,
 		versionSpecFiles: versionSpecFiles,
+		log:              os.Stdout,
 	}, nil
 }
 
+// clearOutputDir removes everything in dir other than the output of the
+// given versions.
+func clearOutputDir(dir string, keep []vervet.Version) error {
+	if len(keep) == 0 {
+		return os.RemoveAll(dir)
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	keepNames := map[string]bool{}
+	for _, release := range keep {
+		keepNames[release.DirName()] = true
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && keepNames[entry.Name()] {
+			continue
+		}
+		err := os.RemoveAll(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OutputFiles returns the paths of the files written for a version.
+func (out *DocWriter) OutputFiles(release vervet.Version) []string {
+	versionDir := path.Join(out.paths[0], release.DirName())
+	return []string{path.Join(versionDir, "spec.json"), path.Join(versionDir, "spec.yaml")}
+}
+
+// RenderedDoc is a compiled document which has been validated and serialized,
+// ready to be written to the output directory.
+type RenderedDoc struct {
+	Version vervet.Version
+	JSON    []byte
+}
+
 // Write writes compiled specs to a single directory in YAML and JSON formats.
 // Call Finalize after to populate other directories.
 func (out *DocWriter) Write(ctx context.Context, doc VersionedDoc) error {
+	rendered, err := out.Render(ctx, doc)
+	if err != nil {
+		return err
+	}
+	return out.WriteAll(ctx, []RenderedDoc{rendered}, 1)
+}
+
+// Render validates a compiled document and serializes it to JSON.
+//
+// Compiled documents may share references, which are mutated when they are
+// resolved. A document must therefore be rendered before references are
+// resolved in the next one.
+func (out *DocWriter) Render(ctx context.Context, doc VersionedDoc) (RenderedDoc, error) {
 	err := doc.Doc.Validate(ctx)
 	if err != nil {
-		return fmt.Errorf("invalid compiled document: %w", err)
+		return RenderedDoc{}, fmt.Errorf("invalid compiled document: %w", err)
+	}
+	jsonBuf, err := vervet.ToSpecJSON(doc.Doc)
+	if err != nil {
+		return RenderedDoc{}, fmt.Errorf("serialise spec to json: %w", err)
+	}
+	return RenderedDoc{Version: doc.Version, JSON: jsonBuf}, nil
+}
+
+// WriteAll writes rendered documents to a single directory in YAML and JSON
+// formats, with up to concurrency documents converted and written at once.
+// Written files are listed in the order of the given documents. Call Finalize
+// after to populate other directories.
+func (out *DocWriter) WriteAll(ctx context.Context, docs []RenderedDoc, concurrency int) error {
+	return out.writeAll(ctx, parallel.NewPool(concurrency), docs)
+}
+
+// writeAll writes rendered documents as WriteAll does, in the given pool.
+func (out *DocWriter) writeAll(ctx context.Context, pool *parallel.Pool, docs []RenderedDoc) error {
+	written, err := parallel.MapPool(ctx, pool, docs, out.writeRendered)
+	if err != nil {
+		return err
 	}
+	for _, specFiles := range written {
+		for _, specFile := range specFiles {
+			// Files may already be listed if they were present before
+			// appending to the output.
+			if !slices.Contains(out.versionSpecFiles, specFile) {
+				out.versionSpecFiles = append(out.versionSpecFiles, specFile)
+			}
+			fmt.Fprintln(out.log, path.Join(out.paths[0], specFile))
+		}
+	}
+	return nil
+}
 
+// writeRendered writes a rendered document in JSON and YAML formats, returning
+// the paths of the files written relative to the output directory.
+func (out *DocWriter) writeRendered(_ context.Context, doc RenderedDoc) ([]string, error) {
 	// We write to the first directory then copy the entire directory
 	// afterwards
 	dir := out.paths[0]
 
-	versionDir := path.Join(dir, doc.VersionDate.Format(time.DateOnly))
-	err = os.MkdirAll(versionDir, 0755)
+	outputFiles := out.OutputFiles(doc.Version)
+	jsonSpecPath, yamlSpecPath := outputFiles[0], outputFiles[1]
+	err := os.MkdirAll(path.Dir(jsonSpecPath), 0755)
 	if err != nil {
-		return fmt.Errorf("make output directory: %w", err)
+		return nil, fmt.Errorf("make output directory: %w", err)
 	}
 
-	jsonBuf, err := vervet.ToSpecJSON(doc.Doc)
-	if err != nil {
-		return fmt.Errorf("serialise spec to json: %w", err)
-	}
-	jsonSpecPath := path.Join(versionDir, "spec.json")
 	jsonEmbedPath, err := filepath.Rel(dir, jsonSpecPath)
 	if err != nil {
-		return fmt.Errorf("get relative output path: %w", err)
+		return nil, fmt.Errorf("get relative output path: %w", err)
 	}
-	out.versionSpecFiles = append(out.versionSpecFiles, jsonEmbedPath)
-	err = os.WriteFile(jsonSpecPath, jsonBuf, 0644)
+	err = os.WriteFile(jsonSpecPath, doc.JSON, 0644)
 	if err != nil {
-		return fmt.Errorf("write json file: %w", err)
+		return nil, fmt.Errorf("write json file: %w", err)
 	}
-	fmt.Println(jsonSpecPath)
 
-	yamlBuf, err := yaml.JSONToYAML(jsonBuf)
+	yamlBuf, err := yaml.JSONToYAML(doc.JSON)
 	if err != nil {
-		return fmt.Errorf("convert spec to yaml: %w", err)
+		return nil, fmt.Errorf("convert spec to yaml: %w", err)
 	}
 	yamlBuf, err = vervet.WithGeneratedComment(yamlBuf)
 	if err != nil {
-		return fmt.Errorf("prepend yaml comment: %w", err)
+		return nil, fmt.Errorf("prepend yaml comment: %w", err)
 	}
-	yamlSpecPath := path.Join(versionDir, "spec.yaml")
 	yamlEmbedPath, err := filepath.Rel(dir, yamlSpecPath)
 	if err != nil {
-		return fmt.Errorf("get relative output path: %w", err)
+		return nil, fmt.Errorf("get relative output path: %w", err)
 	}
-	out.versionSpecFiles = append(out.versionSpecFiles, yamlEmbedPath)
 	err = os.WriteFile(yamlSpecPath, yamlBuf, 0644)
 	if err != nil {
-		return fmt.Errorf("write yaml file: %w", err)
+		return nil, fmt.Errorf("write yaml file: %w", err)
 	}
-	fmt.Println(yamlSpecPath)
-	return nil
+	return []string{jsonEmbedPath, yamlEmbedPath}, nil
 }
 
 func (out *DocWriter) Finalize() error {
@@ -41,8 +41,8 @@ func TestDocSet_WriteOutputs(t *testing.T) {
 			},
 			docs: DocSet{
 				{
-					VersionDate: vervet.MustParseVersion("2024-01-01").Date,
-					Doc:         testDoc,
+					Version: vervet.MustParseVersion("2024-01-01"),
+					Doc:     testDoc,
 				},
 			},
 			assert: func(t *testing.T, args args) {
@@ -65,8 +65,8 @@ func TestDocSet_WriteOutputs(t *testing.T) {
 			},
 			docs: DocSet{
 				{
-					VersionDate: vervet.MustParseVersion("2024-01-01").Date,
-					Doc:         testDoc,
+					Version: vervet.MustParseVersion("2024-01-01"),
+					Doc:     testDoc,
 				},
 			},
 			setup: func(t *testing.T, args args) {
@@ -95,8 +95,8 @@ func TestDocSet_WriteOutputs(t *testing.T) {
 			},
 			docs: DocSet{
 				{
-					VersionDate: vervet.MustParseVersion("2024-01-01").Date,
-					Doc:         testDoc,
+					Version: vervet.MustParseVersion("2024-01-01"),
+					Doc:     testDoc,
 				},
 			},
 			setup: func(t *testing.T, args args) {
@@ -12,8 +12,14 @@ import (
 )
 
 func (doc VersionedDoc) ApplyOverlays(ctx context.Context, cfgs []*config.Overlay) error {
+	return doc.applyOverlays(ctx, specSource{}, cfgs)
+}
+
+// applyOverlays merges the overlays into the document, loading included
+// overlays from src.
+func (doc VersionedDoc) applyOverlays(ctx context.Context, src specSource, cfgs []*config.Overlay) error {
 	// TODO: cache
-	overlays, err := loadOverlays(ctx, cfgs)
+	overlays, err := loadOverlays(ctx, src, cfgs)
 	if err != nil {
 		return fmt.Errorf("load overlays: %w", err)
 	}
@@ -28,11 +34,11 @@ func (doc VersionedDoc) ApplyOverlays(ctx context.Context, cfgs []*config.Overla
 	return nil
 }
 
-func loadOverlays(ctx context.Context, cfgs []*config.Overlay) ([]*openapi3.T, error) {
+func loadOverlays(ctx context.Context, src specSource, cfgs []*config.Overlay) ([]*openapi3.T, error) {
 	overlays := make([]*openapi3.T, len(cfgs))
 	for idx, overlayCfg := range cfgs {
 		if overlayCfg.Include != "" {
-			doc, err := vervet.NewDocumentFile(overlayCfg.Include)
+			doc, err := src.load(overlayCfg.Include)
 			if err != nil {
 				return nil, fmt.Errorf("load include overlay: %w", err)
 			}
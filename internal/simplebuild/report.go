@@ -0,0 +1,160 @@
+package simplebuild
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/config"
+)
+
+// BuildReport is a machine-readable account of a build, which CI tools may
+// use to summarize it.
+type BuildReport struct {
+	// StartVersion is the version from which output versions are built.
+	StartVersion string `json:"startVersion"`
+
+	// APIs are the APIs built, in the order they were built.
+	APIs []*APIReport `json:"apis"`
+
+	// Warnings are issues which did not fail the build.
+	Warnings []string `json:"warnings"`
+
+	// Error is the error which failed the build, if any.
+	Error string `json:"error,omitempty"`
+
+	// Timings are the time spent building all APIs.
+	Timings Timings `json:"timings"`
+}
+
+// APIReport describes how an API was built.
+type APIReport struct {
+	Name string `json:"name"`
+
+	// Overlays are the overlays applied to each version: the path of
+	// included overlays, or "inline".
+	Overlays []string `json:"overlays"`
+
+	// UpToDate indicates that no version of the API needed to be built
+	// again, and so it was not loaded at all.
+	UpToDate bool `json:"upToDate,omitempty"`
+
+	// Versions are the output versions of the API, in version order.
+	Versions []*VersionReport `json:"versions"`
+
+	Timings Timings `json:"timings"`
+}
+
+// VersionReport describes an output version of an API.
+type VersionReport struct {
+	Version string `json:"version"`
+
+	// Cached indicates that the version was unchanged since the last build
+	// and was not written again.
+	Cached bool `json:"cached,omitempty"`
+
+	// Operations are the operations included in the version. Operations are
+	// not listed if the API was up to date.
+	Operations []OperationReport `json:"operations,omitempty"`
+}
+
+// OperationReport describes where an operation in an output version comes
+// from.
+type OperationReport struct {
+	Path            string `json:"path"`
+	Method          string `json:"method"`
+	Resource        string `json:"resource"`
+	ResourceVersion string `json:"resourceVersion"`
+	Source          string `json:"source,omitempty"`
+}
+
+// Timings are the time spent in each phase of a build.
+type Timings struct {
+	Total                Duration `json:"totalMs"`
+	Load                 Duration `json:"loadMs,omitempty"`
+	Compile              Duration `json:"compileMs,omitempty"`
+	CheckBreakingChanges Duration `json:"checkBreakingChangesMs,omitempty"`
+	Render               Duration `json:"renderMs,omitempty"`
+	Write                Duration `json:"writeMs,omitempty"`
+}
+
+// Duration is a time.Duration represented in JSON as a number of
+// milliseconds.
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).Milliseconds())
+}
+
+// since returns the Duration elapsed since t.
+func since(t time.Time) Duration {
+	return Duration(time.Since(t))
+}
+
+// WriteFile writes the report to a JSON file.
+func (r *BuildReport) WriteFile(path string) error {
+	buf, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(path, append(buf, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("write build report: %w", err)
+	}
+	return nil
+}
+
+// warnf prints a warning and records it in the report.
+func (r *BuildReport) warnf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	r.Warnings = append(r.Warnings, msg)
+	fmt.Println("[WARNING] " + msg)
+}
+
+// addAPI adds an API to the report.
+func (r *BuildReport) addAPI(apiConfig *config.API) *APIReport {
+	apiReport := &APIReport{Name: apiConfig.Name, Overlays: []string{}, Versions: []*VersionReport{}}
+	for _, overlay := range apiConfig.Overlays {
+		if overlay.Include != "" {
+			apiReport.Overlays = append(apiReport.Overlays, overlay.Include)
+		} else if overlay.Inline != "" {
+			apiReport.Overlays = append(apiReport.Overlays, "inline")
+		}
+	}
+	r.APIs = append(r.APIs, apiReport)
+	return apiReport
+}
+
+// addVersion adds an output version to the report, along with the
+// operations it includes.
+func (r *APIReport) addVersion(release vervet.Version, cached bool, ops Operations) {
+	versionReport := &VersionReport{
+		Version: release.String(),
+		Cached:  cached,
+	}
+	for opKey, versionSet := range ops {
+		op := versionSet.GetLatestVersion(release)
+		if op == nil {
+			continue
+		}
+		versionReport.Operations = append(versionReport.Operations, OperationReport{
+			Path:            opKey.Path,
+			Method:          opKey.Method,
+			Resource:        op.ResourceName,
+			ResourceVersion: op.Version.String(),
+			Source:          op.Source,
+		})
+	}
+	sort.Slice(versionReport.Operations, func(i, j int) bool {
+		a, b := versionReport.Operations[i], versionReport.Operations[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	r.Versions = append(r.Versions, versionReport)
+}
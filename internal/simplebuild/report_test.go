@@ -0,0 +1,87 @@
+package simplebuild_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/simplebuild"
+)
+
+func TestBuildReport(t *testing.T) {
+	c := qt.New(t)
+	_, project := setupTestProject(c)
+
+	report := &simplebuild.BuildReport{}
+	err := simplebuild.Build(context.Background(), project, vervet.MustParseVersion("2024-01-01"),
+		"http://localhost:0", false, simplebuild.Report(report))
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(report.StartVersion, qt.Equals, "2024-01-01")
+	c.Assert(report.Warnings, qt.HasLen, 2)
+	c.Assert(report.Warnings[0], qt.Matches, `Could not fetch latest version from "http://localhost:0": .*`)
+	c.Assert(report.APIs, qt.HasLen, 1)
+	apiReport := report.APIs[0]
+	c.Assert(apiReport.Name, qt.Equals, "things")
+	c.Assert(apiReport.Versions, qt.HasLen, 2)
+	for i, version := range []string{"2024-01-01", "2024-02-01"} {
+		c.Assert(apiReport.Versions[i].Version, qt.Equals, version)
+		c.Assert(apiReport.Versions[i].Operations, qt.DeepEquals, []simplebuild.OperationReport{{
+			Path:            "/things",
+			Method:          "GET",
+			Resource:        "things",
+			ResourceVersion: version + "~beta",
+			Source:          "resources/things/" + version + "/spec.yaml",
+		}})
+	}
+
+	c.Assert(report.WriteFile("build-report.json"), qt.IsNil)
+	contents, err := os.ReadFile("build-report.json")
+	c.Assert(err, qt.IsNil)
+	var decoded map[string]interface{}
+	c.Assert(json.Unmarshal(contents, &decoded), qt.IsNil)
+	c.Assert(decoded["timings"], qt.Not(qt.IsNil))
+}
+
+func TestBuildConcurrentAPIs(t *testing.T) {
+	c := qt.New(t)
+	_, project := setupTestProject(c)
+	// Further APIs are built from the same resources, concurrently with each
+	// other.
+	for _, name := range []string{"more-things", "other-things"} {
+		project.APIs[name] = &config.API{
+			Name:      name,
+			Resources: []*config.ResourceSet{{Path: "resources"}},
+			Output:    &config.Output{Paths: []string{"out-" + name}},
+		}
+	}
+	build := func(concurrency int) *simplebuild.BuildReport {
+		report := &simplebuild.BuildReport{}
+		err := simplebuild.Build(context.Background(), project, vervet.MustParseVersion("2024-01-01"),
+			"http://localhost:0", false, simplebuild.Report(report), simplebuild.Concurrency(concurrency))
+		c.Assert(err, qt.IsNil)
+		return report
+	}
+	serial := build(1)
+	serialOutput, err := os.ReadFile(filepath.Join("out", "2024-02-01", "spec.yaml"))
+	c.Assert(err, qt.IsNil)
+
+	concurrent := build(8)
+	c.Assert(concurrent.APIs, qt.HasLen, 3)
+	for i, name := range []string{"more-things", "other-things", "things"} {
+		c.Assert(concurrent.APIs[i].Name, qt.Equals, name)
+		c.Assert(concurrent.APIs[i].Versions, qt.DeepEquals, serial.APIs[i].Versions)
+	}
+	c.Assert(concurrent.Warnings, qt.DeepEquals, serial.Warnings)
+	for _, dir := range []string{"out", "out-more-things", "out-other-things"} {
+		output, err := os.ReadFile(filepath.Join(dir, "2024-02-01", "spec.yaml"))
+		c.Assert(err, qt.IsNil)
+		c.Assert(string(output), qt.Equals, string(serialOutput), qt.Commentf("%s", dir))
+	}
+}
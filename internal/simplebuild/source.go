@@ -0,0 +1,78 @@
+package simplebuild
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/hairyhenderson/go-codeowners"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/config"
+	"github.com/snyk/vervet/v8/internal/files"
+)
+
+// specSource is where the specs of a project are loaded from: fsys if set,
+// otherwise the local filesystem relative to the working directory.
+type specSource struct {
+	fsys fs.FS
+}
+
+// match returns the spec files of a resource set.
+func (src specSource) match(resource *config.ResourceSet) ([]string, error) {
+	if src.fsys != nil {
+		return files.MatchFS(src.fsys, resource)
+	}
+	return ResourceSpecFiles(resource)
+}
+
+// load loads a document, resolving its references from the same source.
+func (src specSource) load(path string) (*vervet.Document, error) {
+	if src.fsys != nil {
+		return vervet.NewDocumentFS(src.fsys, path)
+	}
+	return vervet.NewDocumentFile(path)
+}
+
+// loadInputSpec loads a resource spec file, with its references resolved.
+func (src specSource) loadInputSpec(ctx context.Context, path string) (*vervet.Document, error) {
+	doc, err := src.load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spec: %w", err)
+	}
+	doc.InternalizeRefs(ctx, vervet.ResolveRefsWithoutSourceName)
+	if err := doc.ResolveRefs(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// codeowners returns the owners of the specs, declared in the CODEOWNERS file
+// of the project.
+func (src specSource) codeowners() (*codeowners.Codeowners, error) {
+	if src.fsys != nil {
+		return codeowners.FromFileWithFS(src.fsys, ".")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return codeowners.FromFile(cwd)
+}
+
+// relativePath returns the path of a document loaded from the source,
+// relative to the working directory or the root of the filesystem.
+func (src specSource) relativePath(doc *vervet.Document) string {
+	if src.fsys != nil {
+		return doc.RelativePath()
+	}
+	source := doc.Location().Path
+	if cwd, err := os.Getwd(); err == nil {
+		if relSource, err := filepath.Rel(cwd, source); err == nil {
+			source = relSource
+		}
+	}
+	return source
+}
@@ -101,7 +101,7 @@ func (s *Storage) CollateVersions(ctx context.Context, serviceFilter map[string]
 		}
 
 		// Assuming version is valid in path uploads
-		parsedVersion, err := vervet.ParseVersion(version)
+		parsedVersion, err := vervet.ParseAnyVersion(version)
 		if err != nil {
 			return err
 		}
@@ -146,7 +146,7 @@ func (s *Storage) NotifyVersion(ctx context.Context,
 ) error {
 	digest := storage.NewDigest(contents)
 	key := s.getServiceVersionRevisionKey(name, version, string(digest))
-	parsedVersion, err := vervet.ParseVersion(version)
+	parsedVersion, err := vervet.ParseAnyVersion(version)
 	if err != nil {
 		return err
 	}
@@ -183,7 +183,7 @@ func (s *Storage) VersionIndex(ctx context.Context) (vervet.VersionIndex, error)
 		if err != nil {
 			return vervet.VersionIndex{}, err
 		}
-		vs[idx], err = vervet.ParseVersion(versionStr)
+		vs[idx], err = vervet.ParseAnyVersion(versionStr)
 		if err != nil {
 			return vervet.VersionIndex{}, err
 		}
@@ -194,7 +194,7 @@ func (s *Storage) VersionIndex(ctx context.Context) (vervet.VersionIndex, error)
 
 // Version implements scraper.Storage.
 func (s *Storage) Version(ctx context.Context, version string) ([]byte, error) {
-	parsedVersion, err := vervet.ParseVersion(version)
+	parsedVersion, err := vervet.ParseAnyVersion(version)
 	if err != nil {
 		return nil, err
 	}
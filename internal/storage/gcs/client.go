@@ -134,7 +134,7 @@ func (s *Storage) CollateVersions(ctx context.Context, serviceFilter map[string]
 		}
 
 		// Assuming version is valid in path uploads
-		parsedVersion, err := vervet.ParseVersion(version)
+		parsedVersion, err := vervet.ParseAnyVersion(version)
 		if err != nil {
 			log.Error().Err(err).Msg("unexpected version path in GCS. Validate Service Revision uploads")
 			return err
@@ -194,7 +194,7 @@ func (s *Storage) NotifyVersion(ctx context.Context,
 ) error {
 	digest := vustorage.NewDigest(contents)
 	key := getServiceVersionRevisionKey(name, version, string(digest))
-	parsedVersion, err := vervet.ParseVersion(version)
+	parsedVersion, err := vervet.ParseAnyVersion(version)
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to resolve Vervet version for %s : %s", name, version)
 		return err
@@ -235,7 +235,7 @@ func (s *Storage) VersionIndex(ctx context.Context) (vervet.VersionIndex, error)
 	}
 	vs := make(vervet.VersionSlice, len(versions))
 	for idx, version := range versions {
-		vs[idx], err = vervet.ParseVersion(version)
+		vs[idx], err = vervet.ParseAnyVersion(version)
 		if err != nil {
 			return vervet.VersionIndex{}, err
 		}
@@ -245,7 +245,7 @@ func (s *Storage) VersionIndex(ctx context.Context) (vervet.VersionIndex, error)
 
 // Version implements scraper.Storage.
 func (s *Storage) Version(ctx context.Context, version string) ([]byte, error) {
-	parsedVersion, err := vervet.ParseVersion(version)
+	parsedVersion, err := vervet.ParseAnyVersion(version)
 	if err != nil {
 		return nil, err
 	}
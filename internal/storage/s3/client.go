@@ -157,7 +157,7 @@ func (s *Storage) NotifyVersion(
 ) error {
 	digest := storage.NewDigest(contents)
 	key := getServiceVersionRevisionKey(name, version, string(digest))
-	parsedVersion, err := vervet.ParseVersion(version)
+	parsedVersion, err := vervet.ParseAnyVersion(version)
 	if err != nil {
 		log.Error().Err(err).Msgf("failed to resolve version for %q: %q", name, version)
 		return err
@@ -198,7 +198,7 @@ func (s *Storage) VersionIndex(ctx context.Context) (vervet.VersionIndex, error)
 	}
 	vs := make(vervet.VersionSlice, len(versions))
 	for idx, version := range versions {
-		vs[idx], err = vervet.ParseVersion(version)
+		vs[idx], err = vervet.ParseAnyVersion(version)
 		if err != nil {
 			return vervet.VersionIndex{}, err
 		}
@@ -208,7 +208,7 @@ func (s *Storage) VersionIndex(ctx context.Context) (vervet.VersionIndex, error)
 
 // Version implements scraper.Storage.
 func (s *Storage) Version(ctx context.Context, version string) ([]byte, error) {
-	parsedVersion, err := vervet.ParseVersion(version)
+	parsedVersion, err := vervet.ParseAnyVersion(version)
 	if err != nil {
 		return nil, err
 	}
@@ -256,7 +256,7 @@ func (s *Storage) CollateVersions(ctx context.Context, serviceFilter map[string]
 		}
 
 		// Assuming version is valid in path uploads
-		parsedVersion, err := vervet.ParseVersion(version)
+		parsedVersion, err := vervet.ParseAnyVersion(version)
 		if err != nil {
 			log.Error().Err(err).Msgf("invalid version %q in s3 storage key", version)
 			return err
@@ -0,0 +1,222 @@
+package vervet
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// VersioningPolicy defines how the versions of an API are resolved,
+// deprecated and sunset. Different APIs may have different policies.
+type VersioningPolicy struct {
+	// SunsetWIP is the duration past deprecation after which a
+	// work-in-progress version may be sunset.
+	SunsetWIP time.Duration
+
+	// SunsetExperimental is the duration past deprecation after which an
+	// experimental version may be sunset.
+	SunsetExperimental time.Duration
+
+	// SunsetBeta is the duration past deprecation after which a beta version
+	// may be sunset.
+	SunsetBeta time.Duration
+
+	// SunsetGA is the duration past deprecation after which a GA version may
+	// be sunset.
+	SunsetGA time.Duration
+
+	// ExperimentalTTL is the duration after which experimental releases
+	// expire and should be considered sunset.
+	ExperimentalTTL time.Duration
+
+	// Stabilities is the stability ladder: the stability levels which
+	// versions of the API may have, from least to most stable.
+	Stabilities []Stability
+
+	// PivotDate is the version on and after which the simplified versioning
+	// strategy is used.
+	PivotDate Version
+
+	// Scheme is the scheme in which versions of the API are written.
+	Scheme VersionScheme
+
+	// Aliases are named versions which API clients may request, such as
+	// "sdk-v3" for "2024-06-01".
+	Aliases map[string]Version
+}
+
+// LoadOption configures how resource versions are loaded.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	policy *VersioningPolicy
+}
+
+func newLoadOptions(options []LoadOption) loadOptions {
+	opts := loadOptions{policy: DefaultVersioningPolicy()}
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
+// WithVersioningPolicy loads resource versions according to the given
+// versioning policy, rather than the default policy. The policy determines
+// the stabilities allowed, when versions are sunset and their lifecycle.
+func WithVersioningPolicy(policy *VersioningPolicy) LoadOption {
+	return func(opts *loadOptions) {
+		if policy != nil {
+			opts.policy = policy
+		}
+	}
+}
+
+// DefaultVersioningPolicy returns the versioning policy used when none is
+// configured.
+func DefaultVersioningPolicy() *VersioningPolicy {
+	return &VersioningPolicy{
+		SunsetWIP:          SunsetWIP,
+		SunsetExperimental: SunsetExperimental,
+		SunsetBeta:         SunsetBeta,
+		SunsetGA:           SunsetGA,
+		ExperimentalTTL:    ExperimentalTTL,
+		Stabilities:        []Stability{StabilityWIP, StabilityExperimental, StabilityBeta, StabilityGA},
+		PivotDate:          DefaultPivotDate,
+		Scheme:             DateScheme,
+	}
+}
+
+// defaultPolicy is the default versioning policy, which is used by the
+// methods of versions and stabilities not given a policy. It must not be
+// modified.
+var defaultPolicy = DefaultVersioningPolicy()
+
+// Validate returns an error if the policy is not valid.
+func (p *VersioningPolicy) Validate() error {
+	for _, d := range []time.Duration{p.SunsetWIP, p.SunsetExperimental, p.SunsetBeta, p.SunsetGA, p.ExperimentalTTL} {
+		if d < 0 {
+			return fmt.Errorf("negative duration %s", d)
+		}
+	}
+	if len(p.Stabilities) == 0 {
+		return fmt.Errorf("no stabilities defined")
+	}
+	for i := range p.Stabilities {
+		if p.Stabilities[i] <= stabilityUndefined || p.Stabilities[i] >= numStabilityLevels {
+			return fmt.Errorf("invalid stability (%d)", int(p.Stabilities[i]))
+		}
+		if i > 0 && p.Stabilities[i-1] >= p.Stabilities[i] {
+			return fmt.Errorf("stabilities must be in order from least to most stable: %s before %s",
+				p.Stabilities[i-1], p.Stabilities[i])
+		}
+	}
+	if p.PivotDate.Date.IsZero() {
+		return fmt.Errorf("missing pivot date")
+	}
+	if p.Scheme == nil {
+		return fmt.Errorf("missing version scheme")
+	}
+	for name := range p.Aliases {
+		if err := validateAliasName(p.Scheme, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseVersion parses a version string in the policy's version scheme.
+func (p *VersioningPolicy) ParseVersion(s string) (Version, error) {
+	if p.Scheme == nil {
+		return DateScheme.ParseVersion(s)
+	}
+	return p.Scheme.ParseVersion(s)
+}
+
+// Allows returns whether versions of the API may have the given stability.
+func (p *VersioningPolicy) Allows(s Stability) bool {
+	return slices.Contains(p.Stabilities, s)
+}
+
+// Resolvable returns the stabilities, in the stability ladder, at which a
+// version of the given stability may be requested. Work-in-progress versions
+// are not resolved unless explicitly requested.
+func (p *VersioningPolicy) Resolvable(s Stability) []Stability {
+	if s == StabilityWIP {
+		return []Stability{s}
+	}
+	var result []Stability
+	for _, stability := range p.Stabilities {
+		if stability != StabilityWIP && stability <= s {
+			result = append(result, stability)
+		}
+	}
+	return result
+}
+
+// SunsetPeriod returns the duration past deprecation after which a version of
+// the given stability may be sunset.
+func (p *VersioningPolicy) SunsetPeriod(s Stability) (time.Duration, bool) {
+	switch s {
+	case StabilityWIP:
+		return p.SunsetWIP, true
+	case StabilityExperimental:
+		return p.SunsetExperimental, true
+	case StabilityBeta:
+		return p.SunsetBeta, true
+	case StabilityGA:
+		return p.SunsetGA, true
+	default:
+		return 0, false
+	}
+}
+
+// Sunset returns, given a version v and a potentially deprecating version vr,
+// the eligible sunset date and whether v would actually be deprecated and
+// sunset by vr. Versions without a date, such as semantic versions, are not
+// sunset on any date.
+func (p *VersioningPolicy) Sunset(v, vr Version) (time.Time, bool) {
+	if !v.DeprecatedBy(vr) || vr.Date.IsZero() {
+		return time.Time{}, false
+	}
+	period, ok := p.SunsetPeriod(v.Stability)
+	if !ok {
+		return time.Time{}, false
+	}
+	return vr.Date.Add(period), true
+}
+
+// LifecycleAt returns the Lifecycle of a version at the given time. A zero
+// time is interpreted as in Version.LifecycleAt. Versions without a date,
+// such as semantic versions, are always released; experimental versions of
+// these are deprecated rather than expiring.
+func (p *VersioningPolicy) LifecycleAt(v Version, t time.Time) Lifecycle {
+	if v.Date.IsZero() {
+		switch v.Stability {
+		case StabilityWIP:
+			return LifecycleSunset
+		case StabilityExperimental:
+			return LifecycleDeprecated
+		default:
+			return LifecycleReleased
+		}
+	}
+	if t.IsZero() {
+		t = defaultLifecycleAt()
+	}
+	deprecationDelta := t.Sub(v.Date)
+	releaseDelta := timeNow().UTC().Sub(v.Date)
+	if releaseDelta < 0 {
+		return LifecycleUnreleased
+	}
+	if v.Stability.Compare(StabilityExperimental) <= 0 {
+		if v.Stability == StabilityWIP {
+			return LifecycleSunset
+		}
+		// experimental
+		if deprecationDelta > p.ExperimentalTTL {
+			return LifecycleSunset
+		}
+		return LifecycleDeprecated
+	}
+	return LifecycleReleased
+}
@@ -0,0 +1,93 @@
+package vervet_test
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	. "github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/testdata"
+)
+
+func TestVersioningPolicyValidate(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(DefaultVersioningPolicy().Validate(), qt.IsNil)
+	tests := []struct {
+		name   string
+		modify func(p *VersioningPolicy)
+		err    string
+	}{{
+		name:   "negative duration",
+		modify: func(p *VersioningPolicy) { p.SunsetGA = -time.Hour },
+		err:    "negative duration -1h0m0s",
+	}, {
+		name:   "no stabilities",
+		modify: func(p *VersioningPolicy) { p.Stabilities = nil },
+		err:    "no stabilities defined",
+	}, {
+		name:   "stabilities out of order",
+		modify: func(p *VersioningPolicy) { p.Stabilities = []Stability{StabilityGA, StabilityBeta} },
+		err:    "stabilities must be in order from least to most stable: ga before beta",
+	}, {
+		name:   "missing pivot date",
+		modify: func(p *VersioningPolicy) { p.PivotDate = Version{} },
+		err:    "missing pivot date",
+	}}
+	for _, test := range tests {
+		c.Run(test.name, func(c *qt.C) {
+			policy := DefaultVersioningPolicy()
+			test.modify(policy)
+			c.Assert(policy.Validate(), qt.ErrorMatches, test.err)
+		})
+	}
+}
+
+func TestVersioningPolicyResolvable(t *testing.T) {
+	c := qt.New(t)
+	policy := DefaultVersioningPolicy()
+	c.Assert(policy.Resolvable(StabilityBeta), qt.DeepEquals, StabilityBeta.Resolvable())
+	policy.Stabilities = []Stability{StabilityBeta, StabilityGA}
+	c.Assert(policy.Allows(StabilityExperimental), qt.IsFalse)
+	c.Assert(policy.Resolvable(StabilityGA), qt.DeepEquals, []Stability{StabilityBeta, StabilityGA})
+	c.Assert(policy.Resolvable(StabilityBeta), qt.DeepEquals, []Stability{StabilityBeta})
+}
+
+func TestVersioningPolicySunset(t *testing.T) {
+	c := qt.New(t)
+	policy := DefaultVersioningPolicy()
+	policy.SunsetGA = 30 * 24 * time.Hour
+	sunset, ok := policy.Sunset(MustParseVersion("2023-01-01"), MustParseVersion("2023-02-01"))
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(sunset.Format(time.DateOnly), qt.Equals, "2023-03-03")
+
+	_, ok = policy.Sunset(MustParseVersion("2023-01-01"), MustParseVersion("2023-02-01~beta"))
+	c.Assert(ok, qt.IsFalse)
+}
+
+func TestVersioningPolicyLifecycleAt(t *testing.T) {
+	c := qt.New(t)
+	c.Patch(TimeNow, func() time.Time { return time.Date(2022, time.September, 6, 0, 0, 0, 0, time.UTC) })
+	policy := DefaultVersioningPolicy()
+	version := MustParseVersion("2022-08-16~experimental")
+	at := time.Date(2022, time.September, 6, 0, 0, 0, 0, time.UTC)
+	c.Assert(policy.LifecycleAt(version, at), qt.Equals, LifecycleDeprecated)
+	policy.ExperimentalTTL = 7 * 24 * time.Hour
+	c.Assert(policy.LifecycleAt(version, at), qt.Equals, LifecycleSunset)
+}
+
+func TestLoadResourceVersionsWithPolicy(t *testing.T) {
+	c := qt.New(t)
+	policy := DefaultVersioningPolicy()
+	policy.SunsetExperimental = 7 * 24 * time.Hour
+	eps, err := LoadResourceVersions(testdata.Path("sunset-specs"), WithVersioningPolicy(policy))
+	c.Assert(err, qt.IsNil)
+	e, err := eps.At("2023-01-01~experimental")
+	c.Assert(err, qt.IsNil)
+	op := e.Paths.Value("/foo").Get
+	c.Assert(op.Extensions[ExtSnykSunsetEligible], qt.Equals, "2023-02-08")
+
+	policy.Stabilities = []Stability{StabilityBeta, StabilityGA}
+	_, err = LoadResourceVersions(testdata.Path("sunset-specs"), WithVersioningPolicy(policy))
+	c.Assert(err, qt.ErrorMatches, `.*stability "experimental" is not allowed by the versioning policy`)
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -109,6 +111,9 @@ func (rv *ResourceVersion) cleanRefs() error {
 		return err
 	}
 	loader := openapi3.NewLoader()
+	if rv.fsys != nil {
+		loader.ReadFromURIFunc = ReadFromFS(rv.fsys)
+	}
 	doc, err := loader.LoadFromData(buf)
 	if err != nil {
 		return err
@@ -124,6 +129,7 @@ func (rv *ResourceVersion) cleanRefs() error {
 type ResourceVersions struct {
 	versions map[Version]*ResourceVersion
 	index    VersionIndex
+	policy   *VersioningPolicy
 }
 
 // Name returns the resource name for a collection of resource versions.
@@ -151,7 +157,7 @@ func (rv *ResourceVersions) At(vs string) (*ResourceVersion, error) {
 	if vs == "" {
 		vs = time.Now().UTC().Format("2006-01-02")
 	}
-	v, err := ParseVersion(vs)
+	v, err := rv.policy.ParseVersion(vs)
 	if err != nil {
 		return nil, fmt.Errorf("invalid version %q: %w", vs, err)
 	}
@@ -187,7 +193,7 @@ func (rv *ResourceVersions) At(vs string) (*ResourceVersion, error) {
 // The resource version stability level is defined by the
 // ExtSnykApiStability extension value at the top-level of the OpenAPI
 // document.
-func LoadResourceVersions(epPath string) (*ResourceVersions, error) {
+func LoadResourceVersions(epPath string, options ...LoadOption) (*ResourceVersions, error) {
 	// Handles case where there is either a spec.yml or spec.yaml file but
 	// not edge case where there are both specs for the same API
 	// It is assumed that duplicate specs would cause an error elsewhere in vervet
@@ -204,20 +210,34 @@ func LoadResourceVersions(epPath string) (*ResourceVersions, error) {
 			specDirs[dir] = struct{}{}
 		}
 	}
-	return LoadResourceVersionsFileset(specs)
+	return LoadResourceVersionsFileset(specs, options...)
 }
 
-// LoadResourceVersionFileset returns a ResourceVersions slice parsed from the
-// directory structure described above for LoadResourceVersions.
-func LoadResourceVersionsFileset(specYamls []string) (*ResourceVersions, error) {
-	resourceVersions := ResourceVersions{
-		versions: map[Version]*ResourceVersion{},
+// LoadResourceVersionsFS returns a ResourceVersions slice parsed from a
+// directory structure of resource specs, as described for
+// LoadResourceVersions, at epPath within fsys.
+//
+// Operations are annotated with their owners if fsys contains a CODEOWNERS
+// file.
+func LoadResourceVersionsFS(fsys fs.FS, epPath string, options ...LoadOption) (*ResourceVersions, error) {
+	specs, err := doublestar.Glob(fsys, path.Clean(epPath)+"/*/spec.{yaml,yml}")
+	if err != nil {
+		return nil, err
 	}
-	var err error
-	type operationKey struct {
-		path, operation string
+	specDirs := map[string]struct{}{}
+	for _, spec := range specs {
+		dir := path.Dir(spec)
+		if _, ok := specDirs[dir]; ok {
+			return nil, fmt.Errorf("duplicate spec found in %s", dir)
+		}
+		specDirs[dir] = struct{}{}
 	}
-	opReleases := map[operationKey]VersionSlice{}
+	return LoadResourceVersionsFilesetFS(fsys, specs, options...)
+}
+
+// LoadResourceVersionFileset returns a ResourceVersions slice parsed from the
+// directory structure described above for LoadResourceVersions.
+func LoadResourceVersionsFileset(specYamls []string, options ...LoadOption) (*ResourceVersions, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, err
@@ -231,9 +251,65 @@ func LoadResourceVersionsFileset(specYamls []string) (*ResourceVersions, error)
 		if err != nil {
 			return nil, fmt.Errorf("failed to canonicalize %q: %w", specYamls[i], err)
 		}
+	}
+	return loadResourceVersionsFileset(specYamls, ownerFinder, NewDocumentFile, newLoadOptions(options))
+}
+
+// LoadResourceVersionsFilesetFS returns a ResourceVersions slice parsed from
+// the given spec files within fsys, as described for LoadResourceVersions.
+//
+// Operations are annotated with their owners if fsys contains a CODEOWNERS
+// file.
+func LoadResourceVersionsFilesetFS(fsys fs.FS, specYamls []string, options ...LoadOption) (*ResourceVersions, error) {
+	ownerFinder, err := codeownersFS(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CODEOWNERS: %w", err)
+	}
+	loadDocument := func(specPath string) (*Document, error) {
+		return NewDocumentFS(fsys, specPath)
+	}
+	return loadResourceVersionsFileset(specYamls, ownerFinder, loadDocument, newLoadOptions(options))
+}
+
+// codeownersFS returns the owners declared by a CODEOWNERS file at the root
+// of fsys, or in one of the directories GitHub and GitLab look in, if there is
+// one. CODEOWNERS are optional when loading from a filesystem, so nil is
+// returned if there is none.
+func codeownersFS(fsys fs.FS) (*codeowners.Codeowners, error) {
+	for _, dir := range []string{".", "docs", ".github", ".gitlab"} {
+		_, err := fs.Stat(fsys, path.Join(dir, "CODEOWNERS"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		return codeowners.FromFileWithFS(fsys, ".")
+	}
+	return nil, nil //nolint:nilnil // no CODEOWNERS is not an error
+}
+
+func loadResourceVersionsFileset(
+	specYamls []string,
+	ownerFinder *codeowners.Codeowners,
+	loadDocument func(string) (*Document, error),
+	opts loadOptions,
+) (*ResourceVersions, error) {
+	resourceVersions := ResourceVersions{
+		versions: map[Version]*ResourceVersion{},
+		policy:   opts.policy,
+	}
+	type operationKey struct {
+		path, operation string
+	}
+	opReleases := map[operationKey]VersionSlice{}
+	for i := range specYamls {
 		versionDir := filepath.Dir(specYamls[i])
 		versionBase := filepath.Base(versionDir)
-		rc, err := loadResource(specYamls[i], versionBase)
+		doc, err := loadDocument(specYamls[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to load spec from %q: %w", specYamls[i], err)
+		}
+		rc, err := loadResource(doc, versionBase, opts.policy)
 		if err != nil {
 			return nil, err
 		}
@@ -255,7 +331,9 @@ func LoadResourceVersionsFileset(specYamls []string) (*ResourceVersions, error)
 						op.Extensions = make(map[string]any)
 					}
 					op.Extensions[ExtSnykApiVersion] = rc.Version.String()
-					op.Extensions[ExtSnykApiOwner] = ownerFinder.Owners(specYamls[i])
+					if ownerFinder != nil {
+						op.Extensions[ExtSnykApiOwner] = ownerFinder.Owners(specYamls[i])
+					}
 					opKey := operationKey{path, opName}
 					opReleases[opKey] = append(opReleases[opKey], rc.Version)
 				}
@@ -285,7 +363,7 @@ func LoadResourceVersionsFileset(specYamls []string) (*ResourceVersions, error)
 				// Annotate operation with deprecated-by and sunset information
 				if deprecatedBy, ok := index.Deprecates(rc.Version); ok {
 					op.Extensions[ExtSnykDeprecatedBy] = deprecatedBy.String()
-					if sunset, ok := rc.Version.Sunset(deprecatedBy); ok {
+					if sunset, ok := opts.policy.Sunset(rc.Version, deprecatedBy); ok {
 						op.Extensions[ExtSnykSunsetEligible] = sunset.Format("2006-01-02")
 					}
 				}
@@ -318,23 +396,25 @@ func IsExtensionNotFound(err error) bool {
 	return errors.Is(err, &extensionNotFoundError{})
 }
 
-func loadResource(specPath string, versionStr string) (*ResourceVersion, error) {
-	name := filepath.Base(filepath.Dir(filepath.Dir(specPath)))
-	doc, err := NewDocumentFile(specPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load spec from %q: %w", specPath, err)
-	}
+func loadResource(doc *Document, versionStr string, policy *VersioningPolicy) (*ResourceVersion, error) {
+	name := filepath.Base(filepath.Dir(doc.RelativePath()))
 
 	stabilityStr, err := ExtensionString(doc.T.Extensions, ExtSnykApiStability)
 	if err != nil {
-		return nil, err
+		return nil, doc.Diagnostic(err, ExtSnykApiStability)
 	}
 	if stabilityStr != "ga" {
 		versionStr = versionStr + "~" + stabilityStr
 	}
-	version, err := ParseVersion(versionStr)
+	version, err := policy.ParseVersion(versionStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid version %q", versionStr)
+		return nil, doc.Diagnostic(fmt.Errorf("invalid version %q", versionStr), ExtSnykApiStability)
+	}
+	if !policy.Allows(version.Stability) {
+		return nil, doc.Diagnostic(
+			fmt.Errorf("stability %q is not allowed by the versioning policy", version.Stability),
+			ExtSnykApiStability,
+		)
 	}
 
 	if doc.Paths.Len() == 0 {
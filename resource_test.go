@@ -2,8 +2,11 @@ package vervet_test
 
 import (
 	"context"
+	"io/fs"
 	"net/url"
+	"os"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	qt "github.com/frankban/quicktest"
@@ -203,3 +206,68 @@ func (m mockComponentRef) RefPath() *url.URL {
 func (m mockComponentRef) CollectionName() string {
 	return ""
 }
+
+func TestLoadResourceVersionsFS(t *testing.T) {
+	c := qt.New(t)
+	fsys := os.DirFS(testdata.Path("."))
+	eps, err := LoadResourceVersionsFS(fsys, "sunset-specs")
+	c.Assert(err, qt.IsNil)
+	c.Assert(eps.Versions(), qt.ContentEquals, VersionSlice{
+		MustParseVersion("2023-01-01~experimental"),
+		MustParseVersion("2023-02-01~experimental"),
+	})
+	e, err := eps.At("2023-01-10~experimental")
+	c.Assert(err, qt.IsNil)
+	c.Assert(e.Version.String(), qt.Equals, "2023-01-01~experimental")
+	c.Assert(e.Name, qt.Equals, "sunset-specs")
+
+	_, err = LoadResourceVersionsFS(fsys, "duplicate-specs")
+	c.Assert(err, qt.ErrorMatches, "duplicate spec found in duplicate-specs/2022-08-31")
+}
+
+func TestLoadResourceVersionsFSCodeowners(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{
+		"resources/things/2024-01-01/spec.yaml": {Data: []byte(`
+openapi: 3.0.3
+x-snyk-api-stability: beta
+info: {title: things, version: 3.0.0}
+paths:
+  /things:
+    get:
+      responses:
+        '204': {description: No things}
+`)},
+	}
+
+	// CODEOWNERS are optional.
+	eps, err := LoadResourceVersionsFS(fsys, "resources/things")
+	c.Assert(err, qt.IsNil)
+	e, err := eps.At("2024-01-01~beta")
+	c.Assert(err, qt.IsNil)
+	c.Assert(e.Paths.Value("/things").Get.Extensions[ExtSnykApiOwner], qt.IsNil)
+
+	fsys[".github/CODEOWNERS"] = &fstest.MapFile{Data: []byte("/resources/things/ @snyk/things\n")}
+	eps, err = LoadResourceVersionsFS(fsys, "resources/things")
+	c.Assert(err, qt.IsNil)
+	e, err = eps.At("2024-01-01~beta")
+	c.Assert(err, qt.IsNil)
+	c.Assert(e.Paths.Value("/things").Get.Extensions[ExtSnykApiOwner], qt.DeepEquals, []string{"@snyk/things"})
+
+	// A CODEOWNERS file which cannot be read is an error.
+	_, err = LoadResourceVersionsFS(unreadableFS{fsys, "CODEOWNERS"}, "resources/things")
+	c.Assert(err, qt.ErrorMatches, "failed to load CODEOWNERS: .*permission denied")
+}
+
+// unreadableFS is a filesystem in which a file cannot be opened.
+type unreadableFS struct {
+	fs.FS
+	name string
+}
+
+func (u unreadableFS) Open(name string) (fs.File, error) {
+	if name == u.name {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	return u.FS.Open(name)
+}
@@ -22,7 +22,7 @@ func (s resourceVersionsSlice) validate() error {
 			}
 			for _, path := range ep.Paths.InMatchingOrder() {
 				if conflict, ok := resourcePaths[path]; ok {
-					return fmt.Errorf("conflict: %q %q", conflict, ep.sourcePrefix)
+					return ep.Diagnostic(fmt.Errorf("conflict: %q %q", conflict, ep.sourcePrefix), "paths", path)
 				}
 				resourcePaths[path] = ep.sourcePrefix
 			}
@@ -46,7 +46,7 @@ func (s resourceVersionsSlice) versions() VersionSlice {
 	return versions
 }
 
-func (s resourceVersionsSlice) at(v Version) (*openapi3.T, error) {
+func (s resourceVersionsSlice) at(v Version, policy *VersioningPolicy) (*openapi3.T, error) {
 	coll := NewCollator()
 	for _, eps := range s {
 		ep, err := eps.At(v.String())
@@ -67,6 +67,6 @@ func (s resourceVersionsSlice) at(v Version) (*openapi3.T, error) {
 	if result.Extensions == nil {
 		result.Extensions = map[string]any{}
 	}
-	result.Extensions[ExtSnykApiLifecycle] = v.LifecycleAt(time.Time{}).String()
+	result.Extensions[ExtSnykApiLifecycle] = policy.LifecycleAt(v, time.Time{}).String()
 	return result, nil
 }
@@ -0,0 +1,176 @@
+package vervet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VersionScheme defines how the versions of an API are written. Versions of
+// every scheme are ordered by release, then by stability, so that they may be
+// compared and resolved in the same way.
+type VersionScheme interface {
+	// Name returns the name of the scheme, as configured in a project.
+	Name() string
+
+	// ParseVersion parses a version string in this scheme, returning an
+	// error if the string is invalid.
+	ParseVersion(s string) (Version, error)
+
+	// DirPattern returns a glob pattern matching the names of resource
+	// version directories in this scheme.
+	DirPattern() string
+}
+
+var (
+	// DateScheme writes versions as release dates of the form
+	// "YYYY-mm-dd~stability". This is the default scheme.
+	DateScheme VersionScheme = dateScheme{}
+
+	// DateSequenceScheme writes versions as release dates, optionally
+	// followed by a sequence number for further releases on the same date, of
+	// the form "YYYY-mm-dd.N~stability".
+	DateSequenceScheme VersionScheme = dateSequenceScheme{}
+
+	// SemverScheme writes versions as semantic versions of the form
+	// "major.minor.patch~stability".
+	SemverScheme VersionScheme = semverScheme{}
+)
+
+// VersionSchemes are all the version schemes supported.
+var VersionSchemes = []VersionScheme{DateScheme, DateSequenceScheme, SemverScheme}
+
+// ParseVersionScheme returns the version scheme with the given name.
+func ParseVersionScheme(name string) (VersionScheme, error) {
+	for _, scheme := range VersionSchemes {
+		if scheme.Name() == name {
+			return scheme, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid version scheme %q", name)
+}
+
+// SchemeSpecGlobPattern returns the expected directory structure for the
+// versioned OpenAPI specs of a single resource, as SpecGlobPattern does for
+// the date scheme, with subdirectories named by version in the given scheme.
+// A nil scheme is the date scheme.
+func SchemeSpecGlobPattern(scheme VersionScheme) string {
+	if scheme == nil {
+		return SpecGlobPattern
+	}
+	return "**/" + scheme.DirPattern() + "/spec.yaml"
+}
+
+// SemanticVersion is the release of a version in the semver scheme.
+type SemanticVersion struct {
+	Major, Minor, Patch int
+}
+
+// String returns the string representation of the semantic version in
+// major.minor.patch form.
+func (sv SemanticVersion) String() string {
+	return strconv.Itoa(sv.Major) + "." + strconv.Itoa(sv.Minor) + "." + strconv.Itoa(sv.Patch)
+}
+
+// Compare returns -1 if the given semantic version is less than, 0 if equal
+// to, and 1 if greater than the caller target semantic version.
+func (sv SemanticVersion) Compare(svr SemanticVersion) int {
+	for _, cmp := range [][2]int{{sv.Major, svr.Major}, {sv.Minor, svr.Minor}, {sv.Patch, svr.Patch}} {
+		if cmp[0] < cmp[1] {
+			return -1
+		} else if cmp[0] > cmp[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// splitStability splits a version string into its release and stability.
+// The stability is GA if not specified.
+func splitStability(s string) (string, Stability, error) {
+	release, stabilityStr, ok := strings.Cut(s, "~")
+	if !ok {
+		return release, StabilityGA, nil
+	}
+	stability, err := ParseStability(stabilityStr)
+	if err != nil {
+		return "", stabilityUndefined, err
+	}
+	return release, stability, nil
+}
+
+type dateScheme struct{}
+
+// Name implements VersionScheme.
+func (dateScheme) Name() string { return "date" }
+
+// DirPattern implements VersionScheme.
+func (dateScheme) DirPattern() string { return datePattern }
+
+const datePattern = "[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]"
+
+// ParseVersion implements VersionScheme.
+func (dateScheme) ParseVersion(s string) (Version, error) {
+	return ParseVersion(s)
+}
+
+type dateSequenceScheme struct{}
+
+// Name implements VersionScheme.
+func (dateSequenceScheme) Name() string { return "date-sequence" }
+
+// DirPattern implements VersionScheme.
+func (dateSequenceScheme) DirPattern() string { return datePattern + "{,.[0-9]*}" }
+
+// ParseVersion implements VersionScheme.
+func (dateSequenceScheme) ParseVersion(s string) (Version, error) {
+	release, stability, err := splitStability(s)
+	if err != nil {
+		return Version{}, err
+	}
+	dateStr, seqStr, hasSeq := strings.Cut(release, ".")
+	d, err := time.ParseInLocation("2006-01-02", dateStr, time.UTC)
+	if err != nil {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	v := Version{Date: d.UTC(), Stability: stability}
+	if hasSeq {
+		v.Sequence, err = strconv.Atoi(seqStr)
+		if err != nil || v.Sequence < 1 || strconv.Itoa(v.Sequence) != seqStr {
+			return Version{}, fmt.Errorf("invalid version %q: sequence must be a positive integer", s)
+		}
+	}
+	return v, nil
+}
+
+type semverScheme struct{}
+
+// Name implements VersionScheme.
+func (semverScheme) Name() string { return "semver" }
+
+// DirPattern implements VersionScheme.
+func (semverScheme) DirPattern() string { return "[0-9]*.[0-9]*.[0-9]*" }
+
+// ParseVersion implements VersionScheme.
+func (semverScheme) ParseVersion(s string) (Version, error) {
+	release, stability, err := splitStability(s)
+	if err != nil {
+		return Version{}, err
+	}
+	parts := strings.Split(release, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	var nums [3]int
+	for i := range parts {
+		nums[i], err = strconv.Atoi(parts[i])
+		if err != nil || nums[i] < 0 || strconv.Itoa(nums[i]) != parts[i] {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+	}
+	return Version{
+		Semantic:  SemanticVersion{Major: nums[0], Minor: nums[1], Patch: nums[2]},
+		Stability: stability,
+	}, nil
+}
@@ -0,0 +1,209 @@
+package vervet_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	. "github.com/snyk/vervet/v8"
+)
+
+func TestParseVersionScheme(t *testing.T) {
+	c := qt.New(t)
+	for _, scheme := range VersionSchemes {
+		parsed, err := ParseVersionScheme(scheme.Name())
+		c.Assert(err, qt.IsNil)
+		c.Assert(parsed, qt.Equals, scheme)
+	}
+	_, err := ParseVersionScheme("calver")
+	c.Assert(err, qt.ErrorMatches, `invalid version scheme "calver"`)
+}
+
+func TestVersionSchemeParseVersion(t *testing.T) {
+	c := qt.New(t)
+	tests := []struct {
+		scheme VersionScheme
+		s      string
+		want   Version
+		err    string
+	}{{
+		scheme: DateScheme,
+		s:      "2024-01-01~beta",
+		want:   Version{Date: MustParseVersion("2024-01-01").Date, Stability: StabilityBeta},
+	}, {
+		scheme: DateScheme,
+		s:      "2024-01-01.2",
+		err:    `invalid version "2024-01-01.2"`,
+	}, {
+		scheme: DateSequenceScheme,
+		s:      "2024-01-01",
+		want:   MustParseVersion("2024-01-01"),
+	}, {
+		scheme: DateSequenceScheme,
+		s:      "2024-01-01.2~experimental",
+		want:   Version{Date: MustParseVersion("2024-01-01").Date, Sequence: 2, Stability: StabilityExperimental},
+	}, {
+		scheme: DateSequenceScheme,
+		s:      "2024-01-01.0",
+		err:    `invalid version "2024-01-01.0": sequence must be a positive integer`,
+	}, {
+		scheme: DateSequenceScheme,
+		s:      "2024-01-01.02",
+		err:    `invalid version "2024-01-01.02": sequence must be a positive integer`,
+	}, {
+		scheme: SemverScheme,
+		s:      "1.12.0~beta",
+		want:   Version{Semantic: SemanticVersion{Major: 1, Minor: 12}, Stability: StabilityBeta},
+	}, {
+		scheme: SemverScheme,
+		s:      "1.2",
+		err:    `invalid version "1.2"`,
+	}, {
+		scheme: SemverScheme,
+		s:      "1.2.3~stable",
+		err:    `invalid stability "stable"`,
+	}}
+	for _, test := range tests {
+		c.Run(test.scheme.Name()+" "+test.s, func(c *qt.C) {
+			v, err := test.scheme.ParseVersion(test.s)
+			if test.err != "" {
+				c.Assert(err, qt.ErrorMatches, test.err)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(v, qt.Equals, test.want)
+			c.Assert(v.String(), qt.Equals, test.s)
+		})
+	}
+}
+
+func TestParseAnyVersion(t *testing.T) {
+	c := qt.New(t)
+	tests := []struct {
+		s       string
+		want    Version
+		dirName string
+	}{{
+		s:       "2024-01-01~beta",
+		want:    Version{Date: MustParseVersion("2024-01-01").Date, Stability: StabilityBeta},
+		dirName: "2024-01-01",
+	}, {
+		s:       "2024-01-01.2~beta",
+		want:    Version{Date: MustParseVersion("2024-01-01").Date, Sequence: 2, Stability: StabilityBeta},
+		dirName: "2024-01-01.2",
+	}, {
+		s:       "1.2.3~experimental",
+		want:    Version{Semantic: SemanticVersion{Major: 1, Minor: 2, Patch: 3}, Stability: StabilityExperimental},
+		dirName: "1.2.3",
+	}}
+	for _, test := range tests {
+		c.Run(test.s, func(c *qt.C) {
+			v, err := ParseAnyVersion(test.s)
+			c.Assert(err, qt.IsNil)
+			c.Assert(v, qt.Equals, test.want)
+			c.Assert(v.DirName(), qt.Equals, test.dirName)
+		})
+	}
+	_, err := ParseAnyVersion("2024-13-01")
+	c.Assert(err, qt.ErrorMatches, `invalid version "2024-13-01"`)
+}
+
+func mustParseVersions(c *qt.C, scheme VersionScheme, ss ...string) VersionSlice {
+	vs := make(VersionSlice, len(ss))
+	for i := range ss {
+		var err error
+		vs[i], err = scheme.ParseVersion(ss[i])
+		c.Assert(err, qt.IsNil)
+	}
+	return vs
+}
+
+func TestVersionIndexSchemes(t *testing.T) {
+	c := qt.New(t)
+	tests := []struct {
+		scheme   VersionScheme
+		versions []string
+		resolve  map[string]string
+	}{{
+		scheme:   DateSequenceScheme,
+		versions: []string{"2024-01-01", "2024-01-01.2~beta", "2024-01-01.3", "2024-02-01~beta"},
+		resolve: map[string]string{
+			"2024-01-01":        "2024-01-01",
+			"2024-01-01.2":      "2024-01-01",
+			"2024-01-01.2~beta": "2024-01-01.2~beta",
+			"2024-01-01.4":      "2024-01-01.3",
+			"2024-01-15~beta":   "2024-01-01.3",
+			"2024-02-01~beta":   "2024-02-01~beta",
+		},
+	}, {
+		scheme:   SemverScheme,
+		versions: []string{"1.0.0", "1.1.0~beta", "1.10.0", "2.0.0~experimental"},
+		resolve: map[string]string{
+			"1.0.5":              "1.0.0",
+			"1.2.0~beta":         "1.1.0~beta",
+			"1.9.0":              "1.0.0",
+			"1.10.0~beta":        "1.10.0",
+			"3.0.0~experimental": "2.0.0~experimental",
+		},
+	}}
+	for _, test := range tests {
+		c.Run(test.scheme.Name(), func(c *qt.C) {
+			index := NewVersionIndex(mustParseVersions(c, test.scheme, test.versions...))
+			c.Assert(index.Versions().Strings(), qt.DeepEquals, test.versions)
+			for query, want := range test.resolve {
+				q, err := test.scheme.ParseVersion(query)
+				c.Assert(err, qt.IsNil)
+				v, err := index.ResolveForBuild(q)
+				c.Assert(err, qt.IsNil, qt.Commentf("%s", query))
+				c.Assert(v.String(), qt.Equals, want, qt.Commentf("%s", query))
+			}
+			_, err := index.Resolve(Version{Date: MustParseVersion("2000-01-01").Date, Stability: StabilityGA})
+			if test.scheme == SemverScheme {
+				// Dated versions are released after all semantic versions.
+				c.Assert(err, qt.IsNil)
+			} else {
+				c.Assert(err, qt.Equals, ErrNoMatchingVersion)
+			}
+		})
+	}
+}
+
+func TestVersionIndexDeprecatesSequence(t *testing.T) {
+	c := qt.New(t)
+	index := NewVersionIndex(mustParseVersions(c, DateSequenceScheme, "2024-01-01", "2024-01-01.2"))
+	deprecatedBy, ok := index.Deprecates(MustParseVersion("2024-01-01"))
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(deprecatedBy.String(), qt.Equals, "2024-01-01.2")
+}
+
+func TestLoadSpecVersionsScheme(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{}
+	for _, version := range []string{"2024-01-01", "2024-01-01.2"} {
+		fsys["resources/things/"+version+"/spec.yaml"] = &fstest.MapFile{Data: []byte(`
+openapi: 3.0.3
+x-snyk-api-stability: ga
+info: {title: things, version: 3.0.0}
+paths:
+  /things:
+    get:
+      description: ` + version + `
+      responses:
+        '204': {description: No content}
+`)}
+	}
+	policy := DefaultVersioningPolicy()
+	policy.Scheme = DateSequenceScheme
+	specs, err := LoadSpecVersionsFS(fsys, "resources", WithVersioningPolicy(policy))
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs.Versions().Strings(), qt.Contains, "2024-01-01.2")
+	doc, err := specs.At(mustParseVersions(c, DateSequenceScheme, "2024-01-01.5")[0])
+	c.Assert(err, qt.IsNil)
+	c.Assert(doc.Paths.Value("/things").Get.Description, qt.Equals, "2024-01-01.2")
+
+	// Versions of other schemes are not found with the default scheme.
+	specs, err = LoadSpecVersionsFS(fsys, "resources")
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs.Versions().Strings(), qt.Not(qt.Contains), "2024-01-01.2")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"time"
@@ -26,17 +27,50 @@ type SpecVersions struct {
 
 // LoadSpecVersions returns SpecVersions loaded from a directory structure
 // containing one or more Resource subdirectories.
-func LoadSpecVersions(root string) (*SpecVersions, error) {
-	epPaths, err := findResources(root)
+func LoadSpecVersions(root string, options ...LoadOption) (*SpecVersions, error) {
+	epPaths, err := findResources(root, newLoadOptions(options).policy.Scheme)
 	if err != nil {
 		return nil, err
 	}
-	return LoadSpecVersionsFileset(epPaths)
+	return LoadSpecVersionsFileset(epPaths, options...)
+}
+
+// LoadSpecVersionsFS returns SpecVersions loaded from a directory structure
+// containing one or more Resource subdirectories, at root within fsys.
+func LoadSpecVersionsFS(fsys fs.FS, root string, options ...LoadOption) (*SpecVersions, error) {
+	root = path.Clean(root)
+	var epPaths []string
+	pattern := SchemeSpecGlobPattern(newLoadOptions(options).policy.Scheme)
+	err := doublestar.GlobWalk(fsys, path.Join(root, pattern),
+		func(p string, d fs.DirEntry) error {
+			epPaths = append(epPaths, p)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return LoadSpecVersionsFilesetFS(fsys, epPaths, options...)
 }
 
 // LoadSpecVersionsFileset returns SpecVersions loaded from a set of spec
 // files.
-func LoadSpecVersionsFileset(epPaths []string) (*SpecVersions, error) {
+func LoadSpecVersionsFileset(epPaths []string, options ...LoadOption) (*SpecVersions, error) {
+	return loadSpecVersionsFileset(epPaths, newLoadOptions(options), func(specFiles []string) (*ResourceVersions, error) {
+		return LoadResourceVersionsFileset(specFiles, options...)
+	})
+}
+
+// LoadSpecVersionsFilesetFS returns SpecVersions loaded from a set of spec
+// files within fsys.
+func LoadSpecVersionsFilesetFS(fsys fs.FS, epPaths []string, options ...LoadOption) (*SpecVersions, error) {
+	return loadSpecVersionsFileset(epPaths, newLoadOptions(options), func(specFiles []string) (*ResourceVersions, error) {
+		return LoadResourceVersionsFilesetFS(fsys, specFiles, options...)
+	})
+}
+
+func loadSpecVersionsFileset(
+	epPaths []string, opts loadOptions, loadResourceVersions func([]string) (*ResourceVersions, error),
+) (*SpecVersions, error) {
 	resourceMap := map[string][]string{}
 	for i := range epPaths {
 		resourcePath := filepath.Dir(filepath.Dir(epPaths[i]))
@@ -53,7 +87,7 @@ func LoadSpecVersionsFileset(epPaths []string) (*SpecVersions, error) {
 	var resourceVersions resourceVersionsSlice
 	for _, resourcePath := range resourceNames {
 		specFiles := resourceMap[resourcePath]
-		eps, err := LoadResourceVersionsFileset(specFiles)
+		eps, err := loadResourceVersions(specFiles)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load resource at %q: %w", resourcePath, err)
 		}
@@ -62,7 +96,7 @@ func LoadSpecVersionsFileset(epPaths []string) (*SpecVersions, error) {
 	if err := resourceVersions.validate(); err != nil {
 		return nil, err
 	}
-	return newSpecVersions(resourceVersions)
+	return newSpecVersions(resourceVersions, opts.policy)
 }
 
 // Versions returns the distinct API versions in this collection of OpenAPI
@@ -226,7 +260,7 @@ func setOperationByName(path *openapi3.PathItem, opName string, op *openapi3.Ope
 	}
 }
 
-func newSpecVersions(specs resourceVersionsSlice) (*SpecVersions, error) {
+func newSpecVersions(specs resourceVersionsSlice, policy *VersioningPolicy) (*SpecVersions, error) {
 	versions := specs.versions()
 	var versionDates []time.Time
 	for _, v := range versions {
@@ -238,9 +272,10 @@ func newSpecVersions(specs resourceVersionsSlice) (*SpecVersions, error) {
 	documentVersions := map[Version]*openapi3.T{}
 	for _, spec := range specs {
 		for _, doc := range spec.versions {
-			for _, stability := range doc.Version.Stability.Resolvable() {
-				v := Version{Date: doc.Version.Date, Stability: stability}
-				doc, err := specs.at(v)
+			for _, stability := range policy.Resolvable(doc.Version.Stability) {
+				v := doc.Version
+				v.Stability = stability
+				doc, err := specs.at(v, policy)
 				if err == ErrNoMatchingVersion {
 					continue
 				} else if err != nil {
@@ -263,9 +298,9 @@ func newSpecVersions(specs resourceVersionsSlice) (*SpecVersions, error) {
 	return sv, err
 }
 
-func findResources(root string) ([]string, error) {
+func findResources(root string, scheme VersionScheme) ([]string, error) {
 	var paths []string
-	err := doublestar.GlobWalk(os.DirFS(root), SpecGlobPattern,
+	err := doublestar.GlobWalk(os.DirFS(root), SchemeSpecGlobPattern(scheme),
 		func(path string, d fs.DirEntry) error {
 			paths = append(paths, filepath.Join(root, path))
 			return nil
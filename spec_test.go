@@ -2,6 +2,7 @@ package vervet_test
 
 import (
 	"testing"
+	"testing/fstest"
 
 	qt "github.com/frankban/quicktest"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -163,3 +164,42 @@ func TestDoesNotGenerateSpecsForStabilitiesWithNoChanges(t *testing.T) {
 		MustParseVersion("2023-11-15~experimental"),
 	})
 }
+
+func TestSpecsFS(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{
+		"resources/schemas/things.yaml": relativeRefsFS["resources/schemas/things.yaml"],
+		"resources/schemas/common.yaml": relativeRefsFS["resources/schemas/common.yaml"],
+	}
+	for _, version := range []string{"2024-01-01", "2024-02-01"} {
+		fsys["resources/things/"+version+"/spec.yaml"] = &fstest.MapFile{Data: []byte(`
+openapi: 3.0.3
+x-snyk-api-stability: beta
+info: {title: things, version: 3.0.0}
+paths:
+  /things/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        '200':
+          description: A thing
+          content:
+            application/json:
+              schema:
+                $ref: '../../schemas/things.yaml#/Thing'
+`)}
+	}
+	specs, err := LoadSpecVersionsFS(fsys, "resources")
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs.Versions(), qt.ContentEquals, VersionSlice{
+		MustParseVersion("2024-01-01~experimental"),
+		MustParseVersion("2024-01-01~beta"),
+		MustParseVersion("2024-02-01~experimental"),
+		MustParseVersion("2024-02-01~beta"),
+	})
+	doc, err := specs.At(MustParseVersion("2024-01-15~beta"))
+	c.Assert(err, qt.IsNil)
+	schema := doc.Paths.Value("/things/{id}").Get.Responses.Status(200).Value.Content.Get("application/json").Schema
+	c.Assert(schema.Value.Properties["id"].Value.Format, qt.Equals, "uuid")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -13,9 +14,21 @@ var timeNow = time.Now
 
 // Version defines an API version. API versions may be dates of the form
 // "YYYY-mm-dd", or stability tags "beta", "experimental".
+//
+// Versions of other schemes (see VersionScheme) further distinguish releases
+// on the same date with a sequence number, or are released as semantic
+// versions without a date.
 type Version struct {
 	Date      time.Time
 	Stability Stability
+
+	// Sequence orders releases on the same date, in the date-sequence scheme.
+	// The first release on a date has no sequence number.
+	Sequence int
+
+	// Semantic is the semantic version released, in the semver scheme, in
+	// which versions have no date.
+	Semantic SemanticVersion
 }
 
 // DateString returns the string representation of the version date in
@@ -25,15 +38,29 @@ func (v Version) DateString() string {
 }
 
 // String returns the string representation of the version in
-// YYYY-mm-dd~Stability form. This method will panic if the value is empty.
+// YYYY-mm-dd~Stability form, or in the form of the version's scheme. This
+// method will panic if the value is empty.
 func (v Version) String() string {
 	d := v.Date.Format("2006-01-02")
+	if v.Date.IsZero() {
+		d = v.Semantic.String()
+	} else if v.Sequence > 0 {
+		d += "." + strconv.Itoa(v.Sequence)
+	}
 	if v.Stability != StabilityGA {
 		return d + "~" + v.Stability.String()
 	}
 	return d
 }
 
+// DirName returns the name of the resource version directory of the version,
+// which is the version in the form of its scheme without its stability, such
+// as "2024-01-01", "2024-01-01.2" or "1.2.0".
+func (v Version) DirName() string {
+	v.Stability = StabilityGA
+	return v.String()
+}
+
 // AddDays returns the version corresponding to adding the given number of days
 // to the version date.
 func (v Version) AddDays(days int) Version {
@@ -104,6 +131,25 @@ func ParseVersion(s string) (Version, error) {
 	return Version{Date: d.UTC(), Stability: stab}, nil
 }
 
+// ParseAnyVersion parses a version string written in any of the
+// VersionSchemes, returning an error if the string is invalid. Versions of
+// each scheme are written in a distinct form, so the scheme need not be known,
+// as for versions reported by a service. Where the scheme of an API is known,
+// use VersioningPolicy.ParseVersion instead.
+func ParseAnyVersion(s string) (Version, error) {
+	var dateErr error
+	for _, scheme := range VersionSchemes {
+		v, err := scheme.ParseVersion(s)
+		if err == nil {
+			return v, nil
+		} else if dateErr == nil {
+			// The error of the default scheme is reported.
+			dateErr = err
+		}
+	}
+	return Version{}, dateErr
+}
+
 // MustParseVersion parses a version string into a Version type, panicking if
 // the string is invalid.
 func MustParseVersion(s string) Version {
@@ -152,23 +198,16 @@ func (s Stability) Compare(sr Stability) int {
 	return 0
 }
 
+// Resolvable returns the stabilities at which a version of this stability
+// may be requested, according to the default versioning policy.
 func (s Stability) Resolvable() []Stability {
-	// We do not route to WIP paths unless explicitly requested
-	switch s {
-	case StabilityExperimental:
-		return []Stability{StabilityExperimental}
-	case StabilityBeta:
-		return []Stability{StabilityExperimental, StabilityBeta}
-	case StabilityGA:
-		return []Stability{StabilityExperimental, StabilityBeta, StabilityGA}
-	}
-	return []Stability{s}
+	return defaultPolicy.Resolvable(s)
 }
 
 // Compare returns -1 if the given version is less than, 0 if equal to, and 1
 // if greater than the caller target version.
 func (v Version) Compare(vr Version) int {
-	dateCmp, stabilityCmp := v.compareDateStability(&vr)
+	dateCmp, stabilityCmp := v.compareReleaseStability(&vr)
 	if dateCmp != 0 {
 		return dateCmp
 	}
@@ -178,11 +217,12 @@ func (v Version) Compare(vr Version) int {
 // DeprecatedBy returns true if the given version deprecates the caller target
 // version.
 func (v Version) DeprecatedBy(vr Version) bool {
-	dateCmp, stabilityCmp := v.compareDateStability(&vr)
+	dateCmp, stabilityCmp := v.compareReleaseStability(&vr)
 	// A version is deprecated by a newer version of equal or greater stability.
 	return dateCmp == -1 && stabilityCmp <= 0
 }
 
+// Sunset periods of the default versioning policy.
 const (
 	// SunsetWIP is the duration past deprecation after which a work-in-progress version may be sunset.
 	SunsetWIP = 0
@@ -199,37 +239,46 @@ const (
 
 // Sunset returns, given a potentially deprecating version, the eligible sunset
 // date and whether the caller target version would actually be deprecated and
-// sunset by the given version.
+// sunset by the given version, according to the default versioning policy.
 func (v Version) Sunset(vr Version) (time.Time, bool) {
-	if !v.DeprecatedBy(vr) {
-		return time.Time{}, false
-	}
-	switch v.Stability {
-	case StabilityWIP:
-		return vr.Date.Add(SunsetWIP), true
-	case StabilityExperimental:
-		return vr.Date.Add(SunsetExperimental), true
-	case StabilityBeta:
-		return vr.Date.Add(SunsetBeta), true
-	case StabilityGA:
-		return vr.Date.Add(SunsetGA), true
-	default:
-		return time.Time{}, false
-	}
+	return defaultPolicy.Sunset(v, vr)
 }
 
-// compareDateStability returns the comparison of both the date and stability
-// between two versions. Used internally where these need to be evaluated
-// independently, such as when searching for the best matching version.
-func (v *Version) compareDateStability(vr *Version) (int, int) {
-	dateCmp := 0
+// compareReleaseStability returns the comparison of both the release and
+// stability between two versions. Used internally where these need to be
+// evaluated independently, such as when searching for the best matching
+// version.
+func (v *Version) compareReleaseStability(vr *Version) (int, int) {
+	return v.compareRelease(vr), v.Stability.Compare(vr.Stability)
+}
+
+// compareRelease returns the comparison of the releases of two versions,
+// regardless of their stability: by date, then by sequence number on the same
+// date, then by semantic version.
+func (v *Version) compareRelease(vr *Version) int {
 	if v.Date.Before(vr.Date) {
-		dateCmp = -1
+		return -1
 	} else if v.Date.After(vr.Date) {
-		dateCmp = 1
+		return 1
 	}
-	stabilityCmp := v.Stability.Compare(vr.Stability)
-	return dateCmp, stabilityCmp
+	if v.Sequence < vr.Sequence {
+		return -1
+	} else if v.Sequence > vr.Sequence {
+		return 1
+	}
+	return v.Semantic.Compare(vr.Semantic)
+}
+
+// release returns the release of the version, without its stability.
+func (v Version) release() Version {
+	v.Stability = stabilityUndefined
+	return v
+}
+
+// defined returns whether the version is defined, as a version without a
+// stability is not.
+func (v *Version) defined() bool {
+	return v.Stability != stabilityUndefined
 }
 
 // VersionDateStrings returns a slice of distinct version date strings for a
@@ -249,15 +298,17 @@ func VersionDateStrings(vs []Version) []string {
 type VersionSlice []Version
 
 // VersionIndex provides a search over versions, resolving which version is in
-// effect for a given date and stability level.
+// effect for a given release and stability level.
 type VersionIndex struct {
 	effectiveVersions []effectiveVersion
 	versions          VersionSlice
 }
 
+// effectiveVersion is the latest version of each stability in effect at a
+// release.
 type effectiveVersion struct {
-	date        time.Time
-	stabilities [numStabilityLevels]time.Time
+	release     Version
+	stabilities [numStabilityLevels]Version
 }
 
 // NewVersionIndex returns a new VersionIndex of the given versions. The given
@@ -268,17 +319,18 @@ func NewVersionIndex(vs VersionSlice) (vi VersionIndex) {
 	copy(vi.versions, vs)
 
 	evIndex := -1
-	currentStabilities := [numStabilityLevels]time.Time{}
+	currentStabilities := [numStabilityLevels]Version{}
 	for i := range vi.versions {
-		if evIndex == -1 || !vi.effectiveVersions[evIndex].date.Equal(vi.versions[i].Date) {
+		v := &vi.versions[i]
+		if evIndex == -1 || vi.effectiveVersions[evIndex].release.compareRelease(v) != 0 {
 			vi.effectiveVersions = append(vi.effectiveVersions, effectiveVersion{
-				date:        vi.versions[i].Date,
+				release:     v.release(),
 				stabilities: currentStabilities,
 			})
 			evIndex++
 		}
-		vi.effectiveVersions[evIndex].stabilities[vi.versions[i].Stability] = vi.versions[i].Date
-		currentStabilities[vi.versions[i].Stability] = vi.versions[i].Date
+		vi.effectiveVersions[evIndex].stabilities[v.Stability] = *v
+		currentStabilities[v.Stability] = *v
 	}
 	return vi
 }
@@ -286,7 +338,7 @@ func NewVersionIndex(vs VersionSlice) (vi VersionIndex) {
 // Deprecates returns the version that deprecates the given version in the
 // slice.
 func (vi *VersionIndex) Deprecates(q Version) (Version, bool) {
-	match, err := vi.resolveIndex(q.Date)
+	match, err := vi.resolveIndex(q)
 	if err == ErrNoMatchingVersion {
 		return Version{}, false
 	}
@@ -295,11 +347,10 @@ func (vi *VersionIndex) Deprecates(q Version) (Version, bool) {
 	}
 	for i := match + 1; i < len(vi.effectiveVersions); i++ {
 		for stab := q.Stability; stab < numStabilityLevels; stab++ {
-			if stabDate := vi.effectiveVersions[i].stabilities[stab]; stabDate.After(q.Date) {
-				return Version{
-					Date:      vi.effectiveVersions[i].date,
-					Stability: stab,
-				}, true
+			if sv := vi.effectiveVersions[i].stabilities[stab]; sv.defined() && sv.compareRelease(&q) > 0 {
+				v := vi.effectiveVersions[i].release
+				v.Stability = stab
+				return v, true
 			}
 		}
 	}
@@ -312,13 +363,13 @@ func (vi *VersionIndex) Deprecates(q Version) (Version, bool) {
 // Resolve should be used on a collection of already "compiled" or
 // "collated" API versions.
 func (vi *VersionIndex) Resolve(query Version) (Version, error) {
-	i, err := vi.resolveIndex(query.Date)
+	i, err := vi.resolveIndex(query)
 	if err != nil {
 		return Version{}, err
 	}
 	for stab := query.Stability; stab < numStabilityLevels; stab++ {
-		if stabDate := vi.effectiveVersions[i].stabilities[stab]; !stabDate.IsZero() {
-			return Version{Date: stabDate, Stability: stab}, nil
+		if sv := vi.effectiveVersions[i].stabilities[stab]; sv.defined() {
+			return sv, nil
 		}
 	}
 	return Version{}, ErrNoMatchingVersion
@@ -328,18 +379,18 @@ func (vi *VersionIndex) Resolve(query Version) (Version, error) {
 // the given version date. Returns ErrNoMatchingVersion if no version matches or if query
 // stability is not GA.
 func (vi *VersionIndex) ResolveGAorBetaStability(query Version) (Version, error) {
-	i, err := vi.resolveIndex(query.Date)
+	i, err := vi.resolveIndex(query)
 	if query.Stability != StabilityGA {
 		return Version{}, ErrNoMatchingVersion
 	}
 	if err != nil {
 		return Version{}, err
 	}
-	if stabDate := vi.effectiveVersions[i].stabilities[StabilityGA]; !stabDate.IsZero() {
-		return Version{Date: stabDate, Stability: StabilityGA}, nil
+	if sv := vi.effectiveVersions[i].stabilities[StabilityGA]; sv.defined() {
+		return sv, nil
 	}
-	if stabDate := vi.effectiveVersions[i].stabilities[StabilityBeta]; !stabDate.IsZero() {
-		return Version{Date: stabDate, Stability: StabilityBeta}, nil
+	if sv := vi.effectiveVersions[i].stabilities[StabilityBeta]; sv.defined() {
+		return sv, nil
 	}
 	return Version{}, ErrNoMatchingVersion
 }
@@ -352,14 +403,14 @@ func (vi *VersionIndex) Versions() VersionSlice {
 }
 
 // resolveIndex performs a binary search on the stability versions in effect on
-// the query date.
-func (vi *VersionIndex) resolveIndex(query time.Time) (int, error) {
-	if len(vi.effectiveVersions) == 0 || vi.effectiveVersions[0].date.After(query) {
+// the query release.
+func (vi *VersionIndex) resolveIndex(query Version) (int, error) {
+	if len(vi.effectiveVersions) == 0 || vi.effectiveVersions[0].release.compareRelease(&query) > 0 {
 		return -1, ErrNoMatchingVersion
 	}
 	lower, curr, upper := 0, len(vi.effectiveVersions)/2, len(vi.effectiveVersions)
 	for lower < upper-1 {
-		if vi.effectiveVersions[curr].date.After(query) {
+		if vi.effectiveVersions[curr].release.compareRelease(&query) > 0 {
 			upper = curr
 		} else {
 			lower = curr
@@ -376,22 +427,21 @@ func (vi *VersionIndex) resolveIndex(query time.Time) (int, error) {
 // Use ResolveForBuild when resolving version deprecation and effective releases
 // _within a single resource_ during the "compilation" or "collation" process.
 func (vi *VersionIndex) ResolveForBuild(query Version) (Version, error) {
-	i, err := vi.resolveIndex(query.Date)
+	i, err := vi.resolveIndex(query)
 	if err != nil {
 		return Version{}, err
 	}
-	var matchDate time.Time
-	var matchStab Stability
+	var match Version
 	for stab := query.Stability; stab < numStabilityLevels; stab++ {
-		stabDate := vi.effectiveVersions[i].stabilities[stab]
-		if !stabDate.IsZero() && !stabDate.Before(matchDate) && !stabDate.After(query.Date) {
-			matchDate, matchStab = stabDate, stab
+		sv := vi.effectiveVersions[i].stabilities[stab]
+		if sv.defined() && (!match.defined() || sv.compareRelease(&match) >= 0) && sv.compareRelease(&query) <= 0 {
+			match = sv
 		}
 	}
-	if matchDate.IsZero() {
+	if !match.defined() {
 		return Version{}, ErrNoMatchingVersion
 	}
-	return Version{Date: matchDate, Stability: matchStab}, nil
+	return match, nil
 }
 
 // Len implements sort.Interface.
@@ -433,7 +483,7 @@ const (
 	LifecycleSunset Lifecycle = iota
 
 	// ExperimentalTTL is the duration after which experimental releases expire
-	// and should be considered sunset.
+	// and should be considered sunset, in the default versioning policy.
 	ExperimentalTTL = 90 * 24 * time.Hour
 )
 
@@ -488,26 +538,10 @@ func (l Lifecycle) Valid() bool {
 // Otherwise `time.Now().UTC()` is used for the reference time.
 //
 // The current time is always used for determining whether a version is unreleased.
+//
+// Experimental versions expire according to the default versioning policy.
 func (v *Version) LifecycleAt(t time.Time) Lifecycle {
-	if t.IsZero() {
-		t = defaultLifecycleAt()
-	}
-	deprecationDelta := t.Sub(v.Date)
-	releaseDelta := timeNow().UTC().Sub(v.Date)
-	if releaseDelta < 0 {
-		return LifecycleUnreleased
-	}
-	if v.Stability.Compare(StabilityExperimental) <= 0 {
-		if v.Stability == StabilityWIP {
-			return LifecycleSunset
-		}
-		// experimental
-		if deprecationDelta > ExperimentalTTL {
-			return LifecycleSunset
-		}
-		return LifecycleDeprecated
-	}
-	return LifecycleReleased
+	return defaultPolicy.LifecycleAt(*v, t)
 }
 
 func defaultLifecycleAt() time.Time {
@@ -520,4 +554,5 @@ func defaultLifecycleAt() time.Time {
 }
 
 // DefaultPivotDate is the default pivot date after which the versioning strategy changes.
+// It is the pivot date of the default versioning policy.
 var DefaultPivotDate = MustParseVersion("2024-10-15")
@@ -18,14 +18,24 @@ import (
 	"github.com/snyk/vervet/v8"
 	"github.com/snyk/vervet/v8/versionware"
 	. "github.com/snyk/vervet/v8/versionware/example"
+	"github.com/snyk/vervet/v8/versionware/example/jsonapi"
 	"github.com/snyk/vervet/v8/versionware/example/releases"
 	release_2021_11_01 "github.com/snyk/vervet/v8/versionware/example/resources/things/2021-11-01"
 	release_2021_11_08 "github.com/snyk/vervet/v8/versionware/example/resources/things/2021-11-08"
 	release_2021_11_20 "github.com/snyk/vervet/v8/versionware/example/resources/things/2021-11-20"
+	release_2021_12_01 "github.com/snyk/vervet/v8/versionware/example/resources/things/2021-12-01"
 	"github.com/snyk/vervet/v8/versionware/example/store"
 )
 
-func Example() {
+// newHandler returns a versioned handler which responds with JSON:API error
+// documents when the requested version is invalid or not found.
+func newHandler(handlers ...versionware.VersionHandler) *versionware.Handler {
+	h := versionware.NewHandler(handlers...)
+	h.HandleErrors(jsonapi.VersionError)
+	return h
+}
+
+func Example() { //nolint:lll // acked
 	// Set up a test HTTP server
 	var h http.Handler
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -60,7 +70,12 @@ func Example() {
 	validator, err := versionware.NewValidator(&versionware.ValidatorConfig{
 		// We're going to mount our API at /api below...
 		ServerURL: srv.URL + "/api",
-		Options:   []openapi3filter.ValidatorOption{openapi3filter.Strict(true)},
+		// Respond to invalid requests with JSON:API error documents.
+		VersionError: jsonapi.VersionError,
+		Options: []openapi3filter.ValidatorOption{
+			openapi3filter.Strict(true),
+			openapi3filter.OnErr(jsonapi.ValidationError),
+		},
 	}, specs...)
 	if err != nil {
 		log.Fatal(err)
@@ -74,19 +89,22 @@ func Example() {
 	// Router for the "things" resource
 	// As the service grows, these could be pulled out into per-resource sub-packages...
 	thingsRouter := chi.NewRouter()
-	thingsRouter.Get("/{id}", versionware.NewHandler([]versionware.VersionHandler{{
+	thingsRouter.Get("/{id}", newHandler([]versionware.VersionHandler{{
 		Version: release_2021_11_01.Version,
 		Handler: release_2021_11_01.GetThing(s),
 	}}...).ServeHTTP)
-	thingsRouter.Get("/", versionware.NewHandler([]versionware.VersionHandler{{
+	thingsRouter.Get("/", newHandler([]versionware.VersionHandler{{
 		Version: release_2021_11_08.Version,
 		Handler: release_2021_11_08.ListThings(s),
+	}, {
+		Version: release_2021_12_01.Version,
+		Handler: release_2021_12_01.ListThings(s),
 	}}...).ServeHTTP)
-	thingsRouter.Post("/", versionware.NewHandler([]versionware.VersionHandler{{
+	thingsRouter.Post("/", newHandler([]versionware.VersionHandler{{
 		Version: release_2021_11_01.Version,
 		Handler: release_2021_11_01.CreateThing(s),
 	}}...).ServeHTTP)
-	thingsRouter.Delete("/{id}", versionware.NewHandler([]versionware.VersionHandler{{
+	thingsRouter.Delete("/{id}", newHandler([]versionware.VersionHandler{{
 		Version: release_2021_11_20.Version,
 		Handler: release_2021_11_20.DeleteThing(s),
 	}}...).ServeHTTP)
@@ -143,12 +161,41 @@ func Example() {
 	// 200: get a thing
 	PrintResp(srv.Client().Get(srv.URL + "/api/things/1?version=2021-11-10~experimental"))
 
+	// 400: not a valid version
+	PrintResp(srv.Client().Get(srv.URL + "/api/things/1?version=latest-please"))
+
+	// 200: list things a page at a time, following the next link
+	list := srv.URL + "/api/things?version=2021-12-01~experimental&limit=2"
+	PrintResp(srv.Client().Get(list))
+	PrintResp(srv.Client().Get(srv.URL + "/api/things?version=2021-12-01~experimental&limit=2&starting_after=Mg"))
+
+	// 200: filter things by color
+	PrintResp(srv.Client().Get(srv.URL + "/api/things?version=2021-12-01~experimental&color=green"))
+
+	// 400: invalid query parameters
+	PrintResp(srv.Client().Get(srv.URL + "/api/things?version=2021-12-01~experimental&limit=500"))
+	PrintResp(srv.Client().Get(srv.URL + "/api/things?version=2021-12-01~experimental&starting_after=!"))
+
+	// 404: delete a thing that does not exist
+	del, err := http.NewRequest(http.MethodDelete, srv.URL+"/api/things/42?version=2021-11-20~experimental", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	PrintResp(srv.Client().Do(del))
+
 	// Output:
 	// 200 OK
 	// 200 {"id":"1","created":"2022-01-14T00:23:50Z","attributes":{"name":"foo","color":"blue","strangeness":32}}
 	// 200 {"id":"2","created":"2022-01-14T00:23:50Z","attributes":{"name":"shiny","color":"green","strangeness":99}}
 	// 200 {"id":"3","created":"2022-01-14T00:23:50Z","attributes":{"name":"cochineal","color":"red","strangeness":5}}
-	// 404 Not Found
-	// 400 bad request
+	// 404 {"jsonapi":{"version":"1.0"},"errors":[{"status":"404","code":"version-not-found","detail":"no matching version","source":{"parameter":"version"}}]}
+	// 400 {"jsonapi":{"version":"1.0"},"errors":[{"status":"400","code":"invalid-request","detail":"value is not one of the allowed values [\"red\",\"green\",\"blue\"]","source":{"pointer":"/color"}}]}
 	// 200 {"id":"1","created":"2022-01-14T00:23:50Z","attributes":{"name":"foo","color":"blue","strangeness":32}}
+	// 400 {"jsonapi":{"version":"1.0"},"errors":[{"status":"400","code":"invalid-version","detail":"invalid version \"latest-please\"","source":{"parameter":"version"}}]}
+	// 200 {"jsonapi":{"version":"1.0"},"data":[{"id":"1","type":"thing","attributes":{"name":"foo","color":"blue","strangeness":32}},{"id":"2","type":"thing","attributes":{"name":"shiny","color":"green","strangeness":99}}],"links":{"self":"/api/things?version=2021-12-01~experimental&limit=2","first":"/api/things?limit=2&version=2021-12-01~experimental","next":"/api/things?limit=2&starting_after=Mg&version=2021-12-01~experimental"}}
+	// 200 {"jsonapi":{"version":"1.0"},"data":[{"id":"3","type":"thing","attributes":{"name":"cochineal","color":"red","strangeness":5}}],"links":{"self":"/api/things?version=2021-12-01~experimental&limit=2&starting_after=Mg","first":"/api/things?limit=2&version=2021-12-01~experimental","prev":"/api/things?ending_before=Mw&limit=2&version=2021-12-01~experimental"}}
+	// 200 {"jsonapi":{"version":"1.0"},"data":[{"id":"2","type":"thing","attributes":{"name":"shiny","color":"green","strangeness":99}}],"links":{"self":"/api/things?color=green&limit=10&version=2021-12-01~experimental","first":"/api/things?color=green&limit=10&version=2021-12-01~experimental"}}
+	// 400 {"jsonapi":{"version":"1.0"},"errors":[{"status":"400","code":"invalid-request","detail":"number must be at most 100","source":{"parameter":"limit"}}]}
+	// 400 {"jsonapi":{"version":"1.0"},"errors":[{"status":"400","code":"invalid-request","detail":"invalid cursor \"!\"","source":{"parameter":"starting_after"}}]}
+	// 404 {"jsonapi":{"version":"1.0"},"errors":[{"status":"404","code":"not-found","detail":"Thing \"42\" was not found"}]}
 }
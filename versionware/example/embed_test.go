@@ -14,7 +14,7 @@ func TestEmbedding(t *testing.T) {
 
 	specs, err := vervet.LoadVersions(releases.Versions)
 	c.Assert(err, qt.IsNil)
-	c.Assert(specs, qt.HasLen, 3)
+	c.Assert(specs, qt.HasLen, 4)
 	versions := []string{}
 	for i := range specs {
 		version, err := vervet.ExtensionString(specs[i].Extensions, vervet.ExtSnykApiVersion)
@@ -25,5 +25,6 @@ func TestEmbedding(t *testing.T) {
 		"2021-11-01~experimental",
 		"2021-11-08~experimental",
 		"2021-11-20~experimental",
+		"2021-12-01~experimental",
 	})
 }
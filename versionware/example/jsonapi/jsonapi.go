@@ -0,0 +1,166 @@
+// Package jsonapi renders JSON:API error documents for the example API.
+//
+// Errors are written in the same form wherever they are raised: by the
+// versionware handlers and validator, when a requested version is invalid or
+// cannot be found, by the OpenAPI validator, when a request is invalid, and
+// by the API's own handlers.
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// ContentType is the media type of JSON:API documents.
+const ContentType = "application/vnd.api+json"
+
+func init() {
+	// Validate JSON:API request and response bodies as JSON.
+	openapi3filter.RegisterBodyDecoder(ContentType, openapi3filter.JSONBodyDecoder)
+}
+
+// JSONAPI describes the JSON:API version of a document.
+type JSONAPI struct {
+	Version string `json:"version"`
+}
+
+// Version is the JSON:API version of documents rendered by the API.
+var Version = JSONAPI{Version: "1.0"}
+
+// ErrorDocument is a JSON:API document containing errors.
+type ErrorDocument struct {
+	JSONAPI JSONAPI `json:"jsonapi"`
+	Errors  []Error `json:"errors"`
+}
+
+// Error is a JSON:API error object.
+type Error struct {
+	// Status is the HTTP status code of the error, as a string.
+	Status string `json:"status"`
+
+	// Code is an application-specific error code, which clients may use to
+	// handle particular errors.
+	Code string `json:"code,omitempty"`
+
+	// Detail is a human-readable explanation of the error.
+	Detail string `json:"detail"`
+
+	// Source identifies the part of the request which caused the error.
+	Source *Source `json:"source,omitempty"`
+}
+
+// Source identifies the part of the request which caused an error.
+type Source struct {
+	// Pointer is a JSON pointer to the field of the request body which
+	// caused the error.
+	Pointer string `json:"pointer,omitempty"`
+
+	// Parameter is the query parameter which caused the error.
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// Error codes of errors raised by the API.
+const (
+	CodeInvalidRequest  = "invalid-request"
+	CodeInvalidVersion  = "invalid-version"
+	CodeVersionNotFound = "version-not-found"
+	CodeNotFound        = "not-found"
+	CodeInternal        = "internal-error"
+)
+
+// NewError returns an error with the given status, code and detail.
+func NewError(status int, code, detail string) Error {
+	return Error{Status: strconv.Itoa(status), Code: code, Detail: detail}
+}
+
+// ParameterError returns an error caused by a request parameter.
+func ParameterError(status int, code, parameter, detail string) Error {
+	e := NewError(status, code, detail)
+	e.Source = &Source{Parameter: parameter}
+	return e
+}
+
+// WriteErrors writes a JSON:API error document containing the given errors
+// to the response.
+func WriteErrors(w http.ResponseWriter, status int, errs ...Error) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(ErrorDocument{JSONAPI: Version, Errors: errs})
+	if err != nil {
+		log.Println("failed to encode response", err)
+	}
+}
+
+// VersionError is a versionware.VersionErrorHandler which responds with a
+// JSON:API error document when the version requested is invalid or cannot be
+// found.
+func VersionError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	code := CodeInvalidVersion
+	if status == http.StatusNotFound {
+		code = CodeVersionNotFound
+	}
+	WriteErrors(w, status, ParameterError(status, code, "version", err.Error()))
+}
+
+// ValidationError is an openapi3filter.ErrFunc which responds with a JSON:API
+// error document when a request or response fails validation against the
+// OpenAPI spec of the version requested.
+func ValidationError(_ context.Context, w http.ResponseWriter, status int, code openapi3filter.ErrCode, err error) {
+	switch code {
+	case openapi3filter.ErrCodeCannotFindRoute:
+		WriteErrors(w, status, NewError(status, CodeNotFound, "The requested resource was not found"))
+	case openapi3filter.ErrCodeRequestInvalid:
+		var errs []Error
+		var multiErr openapi3.MultiError
+		if errors.As(err, &multiErr) {
+			for i := range multiErr {
+				errs = append(errs, requestError(status, multiErr[i]))
+			}
+		} else {
+			errs = append(errs, requestError(status, err))
+		}
+		WriteErrors(w, status, errs...)
+	default:
+		// Invalid responses are a problem with the service, not the request,
+		// and are not described to the client.
+		WriteErrors(w, status, NewError(status, CodeInternal, "The server encountered an internal error"))
+	}
+}
+
+// requestError returns an error describing why a request is invalid, with the
+// source of the error in the request if it is known.
+func requestError(status int, err error) Error {
+	e := NewError(status, CodeInvalidRequest, err.Error())
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) && schemaErr.Reason != "" {
+		e.Detail = schemaErr.Reason
+	}
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) {
+		return e
+	}
+	switch {
+	case reqErr.Parameter != nil:
+		e.Source = &Source{Parameter: reqErr.Parameter.Name}
+	case reqErr.RequestBody != nil && schemaErr != nil && len(schemaErr.JSONPointer()) > 0:
+		e.Source = &Source{Pointer: jsonPointer(schemaErr.JSONPointer())}
+	}
+	return e
+}
+
+// jsonPointer returns the JSON pointer of a path of tokens.
+func jsonPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteString("/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return sb.String()
+}
@@ -5,8 +5,10 @@ import (
 	"log"
 	"net/http"
 	"path"
+	"strconv"
 
 	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/versionware/example/jsonapi"
 	"github.com/snyk/vervet/v8/versionware/example/resources/things"
 	"github.com/snyk/vervet/v8/versionware/example/store"
 )
@@ -21,7 +23,8 @@ func CreateThing(s *store.Store) http.HandlerFunc {
 		var thingReq things.Attributes
 		err := json.NewDecoder(r.Body).Decode(&thingReq)
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			jsonapi.WriteErrors(w, http.StatusBadRequest, jsonapi.NewError(http.StatusBadRequest,
+				jsonapi.CodeInvalidRequest, "The request body is not a valid thing"))
 			return
 		}
 		id, thing := s.InsertThing(things.FromAttributes(thingReq))
@@ -43,7 +46,8 @@ func GetThing(s *store.Store) http.HandlerFunc {
 		id := path.Base(r.URL.Path)
 		thing, ok := s.SelectThing(id)
 		if !ok {
-			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			jsonapi.WriteErrors(w, http.StatusNotFound, jsonapi.NewError(http.StatusNotFound,
+				jsonapi.CodeNotFound, "Thing "+strconv.Quote(id)+" was not found"))
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -3,8 +3,10 @@ package release_2021_11_20
 import (
 	"net/http"
 	"path"
+	"strconv"
 
 	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/versionware/example/jsonapi"
 	"github.com/snyk/vervet/v8/versionware/example/store"
 )
 
@@ -21,7 +23,8 @@ func DeleteThing(s *store.Store) http.HandlerFunc {
 		id := path.Base(r.URL.Path)
 		ok := s.DeleteThing(id)
 		if !ok {
-			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			jsonapi.WriteErrors(w, http.StatusNotFound, jsonapi.NewError(http.StatusNotFound,
+				jsonapi.CodeNotFound, "Thing "+strconv.Quote(id)+" was not found"))
 			return
 		}
 		w.WriteHeader(http.StatusNoContent)
@@ -0,0 +1,91 @@
+package release_2021_12_01
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/versionware/example/jsonapi"
+	"github.com/snyk/vervet/v8/versionware/example/resources/things"
+	"github.com/snyk/vervet/v8/versionware/example/store"
+)
+
+// Version is the resource release version of handlers in this package.
+var Version = vervet.MustParseVersion("2021-12-01~experimental")
+
+const defaultLimit = 10
+
+// ListThings returns a request handler that uses the given data store. It
+// lists things a page at a time, optionally filtered by name and color, as a
+// JSON:API document.
+func ListThings(s *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		q := store.ThingQuery{
+			Name:  params.Get("name"),
+			Color: params.Get("color"),
+			Limit: defaultLimit,
+		}
+		if limit := params.Get("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil || n < 1 {
+				jsonapi.WriteErrors(w, http.StatusBadRequest, jsonapi.ParameterError(http.StatusBadRequest,
+					jsonapi.CodeInvalidRequest, "limit", "limit must be a positive integer"))
+				return
+			}
+			q.Limit = n
+		}
+		for _, cursor := range []struct {
+			param string
+			id    *string
+		}{{"starting_after", &q.After}, {"ending_before", &q.Before}} {
+			if v := params.Get(cursor.param); v != "" {
+				id, err := things.DecodeCursor(v)
+				if err != nil {
+					jsonapi.WriteErrors(w, http.StatusBadRequest, jsonapi.ParameterError(http.StatusBadRequest,
+						jsonapi.CodeInvalidRequest, cursor.param, err.Error()))
+					return
+				}
+				*cursor.id = id
+			}
+		}
+
+		page := s.SelectThings(q)
+		doc := things.ToListDocument(page.IDs, page.Things)
+		doc.Links = things.Links{
+			Self:  r.URL.RequestURI(),
+			First: pageLink(r.URL, "", ""),
+		}
+		if page.HasPrev && len(page.IDs) > 0 {
+			doc.Links.Prev = pageLink(r.URL, "ending_before", things.EncodeCursor(page.IDs[0]))
+		}
+		if page.HasNext && len(page.IDs) > 0 {
+			doc.Links.Next = pageLink(r.URL, "starting_after", things.EncodeCursor(page.IDs[len(page.IDs)-1]))
+		}
+
+		w.Header().Set("Content-Type", jsonapi.ContentType)
+		enc := json.NewEncoder(w)
+		// Links contain query strings, which need not be escaped for HTML.
+		enc.SetEscapeHTML(false)
+		err := enc.Encode(doc)
+		if err != nil {
+			log.Println("failed to encode response", err)
+		}
+	}
+}
+
+// pageLink returns a link to another page of the requested list, with the
+// request's cursors replaced by the given cursor parameter, if any.
+func pageLink(u *url.URL, param, cursor string) string {
+	params := u.Query()
+	params.Del("starting_after")
+	params.Del("ending_before")
+	if param != "" {
+		params.Set(param, cursor)
+	}
+	link := url.URL{Path: u.Path, RawQuery: params.Encode()}
+	return link.RequestURI()
+}
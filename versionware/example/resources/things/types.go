@@ -2,8 +2,11 @@
 package things
 
 import (
+	"encoding/base64"
+	"fmt"
 	"time"
 
+	"github.com/snyk/vervet/v8/versionware/example/jsonapi"
 	"github.com/snyk/vervet/v8/versionware/example/store"
 )
 
@@ -26,6 +29,47 @@ type CollectionResponse struct {
 	Things []Response `json:"things"`
 }
 
+// ResourceType is the JSON:API resource type of things.
+const ResourceType = "thing"
+
+// Resource represents a thing as a JSON:API resource object.
+type Resource struct {
+	Id         string     `json:"id"`
+	Type       string     `json:"type"`
+	Attributes Attributes `json:"attributes"`
+}
+
+// Links contains the pagination links of a JSON:API collection document.
+type Links struct {
+	Self  string `json:"self"`
+	First string `json:"first"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+}
+
+// ListDocument represents a page of things as a JSON:API document.
+type ListDocument struct {
+	JSONAPI jsonapi.JSONAPI `json:"jsonapi"`
+	Data    []Resource      `json:"data"`
+	Links   Links           `json:"links"`
+}
+
+// EncodeCursor returns the opaque pagination cursor of a thing ID. Clients
+// should not depend on how cursors are formed, so that the API is free to
+// change how results are paged.
+func EncodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// DecodeCursor returns the thing ID of a pagination cursor.
+func DecodeCursor(cursor string) (string, error) {
+	id, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return string(id), nil
+}
+
 // FromAttributes converts Attributes from wire-format to a Thing data model.
 func FromAttributes(attrs Attributes) store.Thing {
 	return store.Thing{
@@ -64,3 +108,21 @@ func ToCollectionResponse(ids []string, things []store.Thing) *CollectionRespons
 	}
 	return coll
 }
+
+// ToListDocument renders a page of Thing models to a JSON:API document,
+// without its links.
+func ToListDocument(ids []string, things []store.Thing) *ListDocument {
+	doc := &ListDocument{JSONAPI: jsonapi.Version, Data: make([]Resource, len(ids))}
+	for i := range ids {
+		doc.Data[i] = Resource{
+			Id:   ids[i],
+			Type: ResourceType,
+			Attributes: Attributes{
+				Name:        things[i].Name,
+				Color:       things[i].Color,
+				Strangeness: things[i].Strangeness,
+			},
+		}
+	}
+	return doc
+}
@@ -2,6 +2,7 @@
 package store
 
 import (
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -51,20 +52,100 @@ func (s *Store) SelectThing(id string) (Thing, bool) {
 	return t, ok
 }
 
-// ListThings lists all the things.
-// TODO: search, pagination or something.
+// ListThings lists all the things, in the order they were created.
 func (s *Store) ListThings() ([]string, []Thing) {
-	ids := make([]string, len(s.things))
-	things := make([]Thing, len(s.things))
-	var i int
+	page := s.SelectThings(ThingQuery{})
+	return page.IDs, page.Things
+}
+
+// ThingQuery selects a page of things, in the order they were created.
+type ThingQuery struct {
+	// Name selects only things with this name, if set.
+	Name string
+
+	// Color selects only things of this color, if set.
+	Color string
+
+	// After selects the things created after the thing with this ID, if set.
+	After string
+
+	// Before selects the things created before the thing with this ID, if
+	// set.
+	Before string
+
+	// Limit is the maximum number of things selected. If zero, there is no
+	// limit.
+	Limit int
+}
+
+// ThingPage is a page of things selected by a ThingQuery.
+type ThingPage struct {
+	IDs    []string
+	Things []Thing
+
+	// HasPrev is whether there are things matching the query before this
+	// page.
+	HasPrev bool
+
+	// HasNext is whether there are things matching the query after this
+	// page.
+	HasNext bool
+}
+
+// SelectThings returns the page of things matching the given query.
+func (s *Store) SelectThings(q ThingQuery) ThingPage {
 	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var ids []string
 	for id, thing := range s.things {
-		ids[i] = id
-		things[i] = thing
-		i++
+		if (q.Name == "" || thing.Name == q.Name) && (q.Color == "" || thing.Color == q.Color) {
+			ids = append(ids, id)
+		}
 	}
-	s.mu.RUnlock()
-	return ids, things
+	// IDs are sequential, so sorting them orders things by creation.
+	sort.Slice(ids, func(i, j int) bool {
+		return seq(ids[i]) < seq(ids[j])
+	})
+
+	start, end := 0, len(ids)
+	if q.After != "" {
+		start = sort.Search(len(ids), func(i int) bool { return seq(ids[i]) > seq(q.After) })
+	}
+	if q.Before != "" {
+		end = sort.Search(len(ids), func(i int) bool { return seq(ids[i]) >= seq(q.Before) })
+	}
+	if end < start {
+		end = start
+	}
+	if q.Limit > 0 && end-start > q.Limit {
+		if q.Before != "" && q.After == "" {
+			// Paging backwards selects the things closest to the cursor.
+			start = end - q.Limit
+		} else {
+			end = start + q.Limit
+		}
+	}
+
+	page := ThingPage{
+		IDs:     ids[start:end],
+		Things:  make([]Thing, end-start),
+		HasPrev: start > 0,
+		HasNext: end < len(ids),
+	}
+	for i, id := range page.IDs {
+		page.Things[i] = s.things[id]
+	}
+	return page
+}
+
+// seq returns the sequence number of a thing ID, or zero if the ID is not
+// valid.
+func seq(id string) int {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return 0
+	}
+	return n
 }
 
 // DeleteThing deletes a thing, returning whether that thing was found and
@@ -0,0 +1,182 @@
+package versionware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrMissingVersion is returned by a VersionExtractor when the request does
+// not supply a version.
+var ErrMissingVersion = errors.New("missing required version")
+
+// VersionExtractor extracts the version requested from a request, such as
+// from a query parameter, a header or a path segment, and locates the same
+// request at another version.
+type VersionExtractor interface {
+	// Extract returns the version requested. Returns an error wrapping
+	// ErrMissingVersion if the request does not supply a version.
+	Extract(req *http.Request) (string, error)
+
+	// VersionURL returns the URL of the request at the given version, or
+	// false if the version cannot be requested with a URL alone, such as
+	// when it is supplied in a header.
+	VersionURL(req *http.Request, version string) (*url.URL, bool)
+}
+
+// VersionExtractorFunc adapts a function to a VersionExtractor. The request
+// cannot be located at another version with a URL.
+type VersionExtractorFunc func(req *http.Request) (string, error)
+
+// Extract implements VersionExtractor.
+func (f VersionExtractorFunc) Extract(req *http.Request) (string, error) {
+	return f(req)
+}
+
+// VersionURL implements VersionExtractor.
+func (f VersionExtractorFunc) VersionURL(req *http.Request, version string) (*url.URL, bool) {
+	return nil, false
+}
+
+// DefaultVersionExtractor extracts the version requested from the "version"
+// query parameter.
+var DefaultVersionExtractor = QueryVersion("version")
+
+type queryVersion string
+
+// QueryVersion returns a VersionExtractor which extracts the version
+// requested from the named query parameter.
+func QueryVersion(name string) VersionExtractor {
+	return queryVersion(name)
+}
+
+// Extract implements VersionExtractor.
+func (name queryVersion) Extract(req *http.Request) (string, error) {
+	if version := req.URL.Query().Get(string(name)); version != "" {
+		return version, nil
+	}
+	return "", fmt.Errorf("%w: query parameter '%s'", ErrMissingVersion, name)
+}
+
+// VersionURL implements VersionExtractor, setting the query parameter to the
+// given version.
+func (name queryVersion) VersionURL(req *http.Request, version string) (*url.URL, bool) {
+	u := *req.URL
+	query := u.Query()
+	query.Set(string(name), version)
+	u.RawQuery = query.Encode()
+	return &u, true
+}
+
+type headerVersion string
+
+// HeaderVersion returns a VersionExtractor which extracts the version
+// requested from the named request header.
+func HeaderVersion(name string) VersionExtractor {
+	return headerVersion(name)
+}
+
+// Extract implements VersionExtractor.
+func (name headerVersion) Extract(req *http.Request) (string, error) {
+	if version := req.Header.Get(string(name)); version != "" {
+		return version, nil
+	}
+	return "", fmt.Errorf("%w: header '%s'", ErrMissingVersion, name)
+}
+
+// VersionURL implements VersionExtractor. A version requested in a header
+// cannot be located with a URL.
+func (name headerVersion) VersionURL(req *http.Request, version string) (*url.URL, bool) {
+	return nil, false
+}
+
+type pathVersion int
+
+// PathVersion returns a VersionExtractor which extracts the version requested
+// from a segment of the request path, counting from zero. For example,
+// PathVersion(0) extracts "2024-06-01" from "/2024-06-01/orgs". The path is
+// not modified, so the handler serving the request sees the version segment.
+func PathVersion(segment int) VersionExtractor {
+	return pathVersion(segment)
+}
+
+func (segment pathVersion) segments(req *http.Request) ([]string, bool) {
+	segments := strings.Split(strings.TrimPrefix(req.URL.Path, "/"), "/")
+	if segment < 0 || int(segment) >= len(segments) || segments[segment] == "" {
+		return nil, false
+	}
+	return segments, true
+}
+
+// Extract implements VersionExtractor.
+func (segment pathVersion) Extract(req *http.Request) (string, error) {
+	if segments, ok := segment.segments(req); ok {
+		return segments[segment], nil
+	}
+	return "", fmt.Errorf("%w: path segment %d", ErrMissingVersion, segment)
+}
+
+// VersionURL implements VersionExtractor, replacing the path segment with the
+// given version.
+func (segment pathVersion) VersionURL(req *http.Request, version string) (*url.URL, bool) {
+	segments, ok := segment.segments(req)
+	if !ok {
+		return nil, false
+	}
+	segments[segment] = version
+	u := *req.URL
+	u.Path = "/" + strings.Join(segments, "/")
+	u.RawPath = ""
+	return &u, true
+}
+
+// DefaultVersion returns a VersionExtractor which always extracts the given
+// version. It may be used last in a chain, for requests which do not supply
+// a version.
+func DefaultVersion(version string) VersionExtractor {
+	return VersionExtractorFunc(func(req *http.Request) (string, error) {
+		return version, nil
+	})
+}
+
+type chainVersion []VersionExtractor
+
+// ChainVersion returns a VersionExtractor which extracts the version
+// requested with each of the given extractors in turn, until one supplies a
+// version. Errors other than ErrMissingVersion are returned immediately. The
+// request is located at another version by the extractor which supplied the
+// version.
+func ChainVersion(extractors ...VersionExtractor) VersionExtractor {
+	return chainVersion(extractors)
+}
+
+// Extract implements VersionExtractor.
+func (extractors chainVersion) Extract(req *http.Request) (string, error) {
+	var errs []error
+	for _, extractor := range extractors {
+		version, err := extractor.Extract(req)
+		if err == nil {
+			return version, nil
+		}
+		if !errors.Is(err, ErrMissingVersion) {
+			return "", err
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return "", ErrMissingVersion
+	}
+	return "", errors.Join(errs...)
+}
+
+// VersionURL implements VersionExtractor.
+func (extractors chainVersion) VersionURL(req *http.Request, version string) (*url.URL, bool) {
+	for _, extractor := range extractors {
+		if _, err := extractor.Extract(req); err == nil {
+			return extractor.VersionURL(req, version)
+		}
+	}
+	return nil, false
+}
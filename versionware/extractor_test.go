@@ -0,0 +1,220 @@
+package versionware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/versionware"
+)
+
+func TestVersionExtractors(t *testing.T) {
+	c := qt.New(t)
+	tests := []struct {
+		name    string
+		extract versionware.VersionExtractor
+		target  string
+		header  string
+		version string
+		err     string
+	}{{
+		name:    "query",
+		extract: versionware.DefaultVersionExtractor,
+		target:  "/orgs?version=2024-06-01",
+		version: "2024-06-01",
+	}, {
+		name:    "missing query",
+		extract: versionware.DefaultVersionExtractor,
+		target:  "/orgs",
+		err:     `missing required version: query parameter 'version'`,
+	}, {
+		name:    "header",
+		extract: versionware.HeaderVersion("Snyk-Version"),
+		target:  "/orgs",
+		header:  "2024-06-01~beta",
+		version: "2024-06-01~beta",
+	}, {
+		name:    "missing header",
+		extract: versionware.HeaderVersion("Snyk-Version"),
+		target:  "/orgs",
+		err:     `missing required version: header 'Snyk-Version'`,
+	}, {
+		name:    "path",
+		extract: versionware.PathVersion(1),
+		target:  "/rest/2024-06-01/orgs",
+		version: "2024-06-01",
+	}, {
+		name:    "missing path",
+		extract: versionware.PathVersion(3),
+		target:  "/rest/2024-06-01/orgs",
+		err:     `missing required version: path segment 3`,
+	}, {
+		name: "chain prefers first",
+		extract: versionware.ChainVersion(
+			versionware.HeaderVersion("Snyk-Version"),
+			versionware.DefaultVersionExtractor,
+		),
+		target:  "/orgs?version=2024-01-01",
+		header:  "2024-06-01",
+		version: "2024-06-01",
+	}, {
+		name: "chain falls back",
+		extract: versionware.ChainVersion(
+			versionware.HeaderVersion("Snyk-Version"),
+			versionware.DefaultVersionExtractor,
+		),
+		target:  "/orgs?version=2024-01-01",
+		version: "2024-01-01",
+	}, {
+		name: "chain default",
+		extract: versionware.ChainVersion(
+			versionware.HeaderVersion("Snyk-Version"),
+			versionware.DefaultVersion("latest"),
+		),
+		target:  "/orgs",
+		version: "latest",
+	}, {
+		name: "chain missing",
+		extract: versionware.ChainVersion(
+			versionware.HeaderVersion("Snyk-Version"),
+			versionware.DefaultVersionExtractor,
+		),
+		target: "/orgs",
+		err: `missing required version: header 'Snyk-Version'
+missing required version: query parameter 'version'`,
+	}}
+	for _, test := range tests {
+		c.Run(test.name, func(c *qt.C) {
+			req := httptest.NewRequest("GET", test.target, nil)
+			if test.header != "" {
+				req.Header.Set("Snyk-Version", test.header)
+			}
+			version, err := test.extract.Extract(req)
+			if test.err != "" {
+				c.Assert(err, qt.ErrorMatches, test.err)
+				c.Assert(err, qt.ErrorIs, versionware.ErrMissingVersion)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(version, qt.Equals, test.version)
+		})
+	}
+}
+
+func TestHandlerUseVersionExtractor(t *testing.T) {
+	c := qt.New(t)
+	h := versionware.NewHandler([]versionware.VersionHandler{{
+		Version: vervet.MustParseVersion("2021-10-01"),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte("oct"))
+			c.Assert(err, qt.IsNil)
+		}),
+	}}...)
+	h.UseVersionExtractor(versionware.ChainVersion(
+		versionware.HeaderVersion("Snyk-Version"),
+		versionware.DefaultVersion("2021-10-15"),
+	))
+	s := httptest.NewServer(h)
+	c.Cleanup(s.Close)
+
+	for _, requested := range []string{"2021-10-20", ""} {
+		req, err := http.NewRequest("GET", s.URL, nil)
+		c.Assert(err, qt.IsNil)
+		if requested != "" {
+			req.Header.Set("Snyk-Version", requested)
+		}
+		resp, err := s.Client().Do(req)
+		c.Assert(err, qt.IsNil)
+		c.Assert(resp.StatusCode, qt.Equals, 200)
+		contents, err := io.ReadAll(resp.Body)
+		c.Assert(err, qt.IsNil)
+		c.Assert(resp.Body.Close(), qt.IsNil)
+		c.Assert(string(contents), qt.Equals, "oct")
+		if requested == "" {
+			requested = "2021-10-15"
+		}
+		c.Assert(resp.Header.Get(versionware.HeaderSnykVersionRequested), qt.Equals, requested)
+	}
+}
+
+func TestVersionExtractorVersionURL(t *testing.T) {
+	c := qt.New(t)
+	tests := []struct {
+		name    string
+		extract versionware.VersionExtractor
+		target  string
+		header  string
+		url     string
+	}{{
+		name:    "query",
+		extract: versionware.DefaultVersionExtractor,
+		target:  "/orgs?limit=10&version=2024-01-01",
+		url:     "/orgs?limit=10&version=2024-06-01",
+	}, {
+		name:    "header",
+		extract: versionware.HeaderVersion("Snyk-Version"),
+		target:  "/orgs",
+		header:  "2024-01-01",
+	}, {
+		name:    "path",
+		extract: versionware.PathVersion(1),
+		target:  "/rest/2024-01-01/orgs?limit=10",
+		url:     "/rest/2024-06-01/orgs?limit=10",
+	}, {
+		name: "chain locates with the extractor supplying the version",
+		extract: versionware.ChainVersion(
+			versionware.HeaderVersion("Snyk-Version"),
+			versionware.PathVersion(0),
+		),
+		target: "/2024-01-01/orgs",
+		url:    "/2024-06-01/orgs",
+	}, {
+		name: "chain default",
+		extract: versionware.ChainVersion(
+			versionware.DefaultVersionExtractor,
+			versionware.DefaultVersion("2024-01-01"),
+		),
+		target: "/orgs",
+	}}
+	for _, test := range tests {
+		c.Run(test.name, func(c *qt.C) {
+			req := httptest.NewRequest("GET", test.target, nil)
+			if test.header != "" {
+				req.Header.Set("Snyk-Version", test.header)
+			}
+			u, ok := test.extract.VersionURL(req, "2024-06-01")
+			if test.url == "" {
+				c.Assert(ok, qt.IsFalse)
+				return
+			}
+			c.Assert(ok, qt.IsTrue)
+			c.Assert(u.RequestURI(), qt.Equals, test.url)
+			c.Assert(req.URL.RequestURI(), qt.Equals, test.target)
+		})
+	}
+}
+
+func TestHandlerSuccessorPathVersion(t *testing.T) {
+	c := qt.New(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := versionware.NewHandler([]versionware.VersionHandler{{
+		Version: vervet.MustParseVersion("2021-10-01"),
+		Handler: handler,
+	}, {
+		Version: vervet.MustParseVersion("2021-12-01"),
+		Handler: handler,
+	}}...)
+	h.UseVersionExtractor(versionware.PathVersion(0))
+	s := httptest.NewServer(h)
+	c.Cleanup(s.Close)
+
+	resp, err := s.Client().Get(s.URL + "/2021-10-15/orgs?limit=10")
+	c.Assert(err, qt.IsNil)
+	c.Assert(resp.Body.Close(), qt.IsNil)
+	c.Assert(resp.StatusCode, qt.Equals, 200)
+	c.Assert(resp.Header.Get(versionware.HeaderLink), qt.Equals, `</2021-12-01/orgs?limit=10>; rel="successor-version"`)
+}
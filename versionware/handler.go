@@ -3,8 +3,11 @@
 package versionware
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/snyk/vervet/v8"
 )
@@ -17,6 +20,19 @@ const (
 	// HeaderSnykVersionServed is a response header indicating the actual API
 	// version that was matched and served the response.
 	HeaderSnykVersionServed = "snyk-version-served"
+
+	// HeaderDeprecation is a response header indicating when the version
+	// served was deprecated, as defined in RFC 9745.
+	HeaderDeprecation = "Deprecation"
+
+	// HeaderSunset is a response header indicating when the version served
+	// may be sunset, as defined in RFC 8594.
+	HeaderSunset = "Sunset"
+
+	// HeaderLink is a response header linking to related resources, as
+	// defined in RFC 8288. The version which deprecates the version served is
+	// linked with the "successor-version" relation.
+	HeaderLink = "Link"
 )
 
 // Handler is a multiplexing http.Handler that dispatches requests based on the
@@ -25,6 +41,8 @@ type Handler struct {
 	handlers map[vervet.Version]http.Handler
 	index    vervet.VersionIndex
 	errFunc  VersionErrorHandler
+	policy   *vervet.VersioningPolicy
+	extract  VersionExtractor
 }
 
 // VersionErrorHandler defines a function which handles versioning error
@@ -43,6 +61,8 @@ func NewHandler(vhs ...VersionHandler) *Handler {
 	h := &Handler{
 		handlers: map[vervet.Version]http.Handler{},
 		errFunc:  DefaultVersionError,
+		policy:   vervet.DefaultVersioningPolicy(),
+		extract:  DefaultVersionExtractor,
 	}
 	versions := make([]vervet.Version, len(vhs))
 	for i := range vhs {
@@ -66,12 +86,32 @@ func (h *Handler) HandleErrors(errFunc VersionErrorHandler) {
 	h.errFunc = errFunc
 }
 
+// UsePolicy changes the versioning policy used to resolve requested versions
+// from the default policy. The policy determines the scheme requested versions
+// are written in, the aliases which may be requested, the pivot version on
+// which simplified versioning begins and the stabilities which may be
+// requested.
+func (h *Handler) UsePolicy(policy *vervet.VersioningPolicy) {
+	h.policy = policy
+}
+
+// UseVersionExtractor changes how the version requested is extracted from
+// requests, which is from the "version" query parameter by default. The
+// extractor also locates the successor-version Link of deprecated versions.
+func (h *Handler) UseVersionExtractor(extract VersionExtractor) {
+	h.extract = extract
+}
+
 // Resolve returns the resolved version and its associated http.Handler for the
-// requested version.
+// requested version. Versions before the pivot version of the versioning
+// policy are resolved to the most stable version released at the requested
+// stability or above; otherwise the latest GA or beta version released is
+// resolved. Versions without a date, such as semantic versions, are resolved
+// in the latter way.
 func (h *Handler) Resolve(requested vervet.Version) (*vervet.Version, http.Handler, error) {
 	var resolvedVersion vervet.Version
 	var err error
-	if requested.Date.Compare(vervet.DefaultPivotDate.Date) < 0 {
+	if !requested.Date.IsZero() && requested.Date.Compare(h.policy.PivotDate.Date) < 0 {
 		resolvedVersion, err = h.index.ResolveForBuild(requested)
 		if err != nil {
 			return nil, nil, err
@@ -85,26 +125,89 @@ func (h *Handler) Resolve(requested vervet.Version) (*vervet.Version, http.Handl
 	return &resolvedVersion, h.handlers[resolvedVersion], nil
 }
 
+// resolveRequested resolves a version requested as Resolve does, except that
+// the latest version at a stability other than GA, as in "latest~beta", is
+// always resolved as it would be before the pivot version: to the latest
+// version released at that stability or above. Otherwise it could not be
+// resolved at all on or after the pivot version, where only GA versions are
+// resolved.
+func (h *Handler) resolveRequested(
+	versionParam string, requested vervet.Version,
+) (*vervet.Version, http.Handler, error) {
+	if requested.Stability == vervet.StabilityGA || !strings.HasPrefix(versionParam, vervet.VersionAliasLatest+"~") {
+		return h.Resolve(requested)
+	}
+	resolvedVersion, err := h.index.ResolveForBuild(requested)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resolvedVersion, h.handlers[resolvedVersion], nil
+}
+
 // ServeHTTP implements http.Handler with the handler matching the version
-// query parameter on the request. If no matching version is found, responds
-// 404.
+// requested, as extracted from the request. If no matching version is found, responds
+// 404. If the version served is deprecated by a later version, the response
+// has Deprecation, Sunset and successor-version Link headers.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	versionParam := req.URL.Query().Get("version")
-	if versionParam == "" {
-		h.errFunc(w, req, http.StatusBadRequest, fmt.Errorf("missing required query parameter 'version'"))
+	versionParam, err := h.extract.Extract(req)
+	if err != nil {
+		h.errFunc(w, req, http.StatusBadRequest, err)
 		return
 	}
-	requested, err := vervet.ParseVersion(versionParam)
-	if err != nil {
+	requested, err := h.policy.ParseRequestedVersion(versionParam, &h.index)
+	if errors.Is(err, vervet.ErrNoMatchingVersion) {
+		h.errFunc(w, req, http.StatusNotFound, err)
+		return
+	} else if err != nil {
 		h.errFunc(w, req, http.StatusBadRequest, err)
 		return
 	}
-	resolved, handler, err := h.Resolve(requested)
+	if !h.policy.Allows(requested.Stability) {
+		h.errFunc(w, req, http.StatusBadRequest, fmt.Errorf("unsupported stability %q", requested.Stability))
+		return
+	}
+	resolved, handler, err := h.resolveRequested(versionParam, requested)
 	if err != nil {
 		h.errFunc(w, req, http.StatusNotFound, err)
 		return
 	}
-	w.Header().Set(HeaderSnykVersionRequested, requested.String())
+	if h.policy.IsAlias(versionParam) {
+		w.Header().Set(HeaderSnykVersionRequested, versionParam)
+	} else {
+		w.Header().Set(HeaderSnykVersionRequested, requested.String())
+	}
 	w.Header().Set(HeaderSnykVersionServed, resolved.String())
+	h.setDeprecationHeaders(w, req, *resolved)
+	if vs, ok := handler.(versionServer); ok {
+		vs.serveVersion(w, req, h.errFunc)
+		return
+	}
 	handler.ServeHTTP(w, req)
 }
+
+// versionServer is implemented by version handlers which handle their own
+// errors with the error handler of the Handler serving them.
+type versionServer interface {
+	serveVersion(w http.ResponseWriter, req *http.Request, errFunc VersionErrorHandler)
+}
+
+// setDeprecationHeaders sets the Deprecation, Sunset and successor-version
+// Link headers on the response if the resolved version is deprecated by a
+// later version. Versions without a date, such as semantic versions, are
+// deprecated on no date, so only the successor is linked. The successor is
+// not linked if the version extractor cannot locate it with a URL.
+func (h *Handler) setDeprecationHeaders(w http.ResponseWriter, req *http.Request, resolved vervet.Version) {
+	deprecatedBy, ok := h.index.Deprecates(resolved)
+	if !ok {
+		return
+	}
+	if !deprecatedBy.Date.IsZero() {
+		w.Header().Set(HeaderDeprecation, "@"+strconv.FormatInt(deprecatedBy.Date.Unix(), 10))
+	}
+	if sunset, ok := h.policy.Sunset(resolved, deprecatedBy); ok {
+		w.Header().Set(HeaderSunset, sunset.UTC().Format(http.TimeFormat))
+	}
+	if successor, ok := h.extract.VersionURL(req, deprecatedBy.String()); ok {
+		w.Header().Add(HeaderLink, fmt.Sprintf(`<%s>; rel="successor-version"`, successor.RequestURI()))
+	}
+}
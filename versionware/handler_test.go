@@ -235,3 +235,267 @@ func TestHandler_BetaEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerUsePolicy(t *testing.T) {
+	c := qt.New(t)
+	handler := func(contents string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(contents))
+			c.Assert(err, qt.IsNil)
+		})
+	}
+	h := versionware.NewHandler([]versionware.VersionHandler{{
+		Version: vervet.MustParseVersion("2023-01-01~beta"),
+		Handler: handler("jan beta"),
+	}, {
+		Version: vervet.MustParseVersion("2023-03-01"),
+		Handler: handler("mar"),
+	}}...)
+	policy := vervet.DefaultVersioningPolicy()
+	policy.PivotDate = vervet.MustParseVersion("2023-02-01")
+	policy.Stabilities = []vervet.Stability{vervet.StabilityBeta, vervet.StabilityGA}
+	h.UsePolicy(policy)
+	tests := []struct {
+		requested, resolved string
+		contents            string
+		status              int
+	}{{
+		"2023-01-15~beta", "2023-01-01~beta", "jan beta", 200,
+	}, {
+		// Simplified versioning from the policy's pivot date only resolves
+		// exact stability matches.
+		"2023-03-15~beta", "", "Not Found\n", 404,
+	}, {
+		"2023-03-15", "2023-03-01", "mar", 200,
+	}, {
+		"2023-03-15~experimental", "", "Bad Request\n", 400,
+	}}
+	for i, test := range tests {
+		c.Run(fmt.Sprintf("%d requested %s resolved %s", i, test.requested, test.resolved), func(c *qt.C) {
+			s := httptest.NewServer(h)
+			c.Cleanup(s.Close)
+			resp, err := s.Client().Get(s.URL + "?version=" + test.requested)
+			c.Assert(err, qt.IsNil)
+			defer resp.Body.Close()
+			c.Assert(resp.StatusCode, qt.Equals, test.status)
+			contents, err := io.ReadAll(resp.Body)
+			c.Assert(err, qt.IsNil)
+			c.Assert(string(contents), qt.Equals, test.contents)
+			if test.resolved != "" {
+				c.Assert(resp.Header.Get(versionware.HeaderSnykVersionServed), qt.Equals, test.resolved)
+			}
+		})
+	}
+}
+
+func TestHandlerAliases(t *testing.T) {
+	c := qt.New(t)
+	handler := func(contents string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(contents))
+			c.Assert(err, qt.IsNil)
+		})
+	}
+	h := versionware.NewHandler([]versionware.VersionHandler{{
+		Version: vervet.MustParseVersion("2021-10-01"),
+		Handler: handler("oct"),
+	}, {
+		Version: vervet.MustParseVersion("2021-11-01~beta"),
+		Handler: handler("nov beta"),
+	}, {
+		Version: vervet.MustParseVersion("2021-12-01"),
+		Handler: handler("dec"),
+	}}...)
+	policy := vervet.DefaultVersioningPolicy()
+	var err error
+	policy.Aliases, err = vervet.ParseVersionAliases(policy.Scheme, map[string]string{
+		"sdk-v1": "2021-10-15",
+		"sdk-v0": "2021-01-01",
+	})
+	c.Assert(err, qt.IsNil)
+	h.UsePolicy(policy)
+	tests := []struct {
+		requested, resolved string
+		contents            string
+		status              int
+	}{{
+		"latest", "2021-12-01", "dec", 200,
+	}, {
+		"latest~beta", "2021-12-01", "dec", 200,
+	}, {
+		"sdk-v1", "2021-10-01", "oct", 200,
+	}, {
+		"sdk-v0", "", "Not Found\n", 404,
+	}, {
+		"sdk-v2", "", "Bad Request\n", 400,
+	}}
+	s := httptest.NewServer(h)
+	c.Cleanup(s.Close)
+	for _, test := range tests {
+		c.Run(test.requested, func(c *qt.C) {
+			resp, err := s.Client().Get(s.URL + "?version=" + test.requested)
+			c.Assert(err, qt.IsNil)
+			defer resp.Body.Close()
+			c.Assert(resp.StatusCode, qt.Equals, test.status)
+			contents, err := io.ReadAll(resp.Body)
+			c.Assert(err, qt.IsNil)
+			c.Assert(string(contents), qt.Equals, test.contents)
+			if test.status == 200 {
+				c.Assert(resp.Header.Get(versionware.HeaderSnykVersionRequested), qt.Equals, test.requested)
+				c.Assert(resp.Header.Get(versionware.HeaderSnykVersionServed), qt.Equals, test.resolved)
+			}
+		})
+	}
+}
+
+func TestHandlerAliasesAfterPivot(t *testing.T) {
+	c := qt.New(t)
+	handler := func(contents string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(contents))
+			c.Assert(err, qt.IsNil)
+		})
+	}
+	h := versionware.NewHandler([]versionware.VersionHandler{{
+		Version: vervet.MustParseVersion("2024-11-01"),
+		Handler: handler("nov"),
+	}, {
+		Version: vervet.MustParseVersion("2024-12-01~beta"),
+		Handler: handler("dec beta"),
+	}, {
+		Version: vervet.MustParseVersion("2025-01-01~experimental"),
+		Handler: handler("jan experimental"),
+	}}...)
+	tests := []struct {
+		requested, resolved string
+		contents            string
+	}{{
+		"latest", "2024-11-01", "nov",
+	}, {
+		"latest~beta", "2024-12-01~beta", "dec beta",
+	}, {
+		"latest~experimental", "2025-01-01~experimental", "jan experimental",
+	}}
+	s := httptest.NewServer(h)
+	c.Cleanup(s.Close)
+	for _, test := range tests {
+		c.Run(test.requested, func(c *qt.C) {
+			resp, err := s.Client().Get(s.URL + "?version=" + test.requested)
+			c.Assert(err, qt.IsNil)
+			defer resp.Body.Close()
+			c.Assert(resp.StatusCode, qt.Equals, http.StatusOK)
+			contents, err := io.ReadAll(resp.Body)
+			c.Assert(err, qt.IsNil)
+			c.Assert(string(contents), qt.Equals, test.contents)
+			c.Assert(resp.Header.Get(versionware.HeaderSnykVersionServed), qt.Equals, test.resolved)
+		})
+	}
+
+	// Versions requested at stabilities other than GA are not resolved after
+	// the pivot version.
+	resp, err := s.Client().Get(s.URL + "?version=2025-01-01~experimental")
+	c.Assert(err, qt.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, qt.Equals, http.StatusNotFound)
+}
+
+func TestHandlerDeprecation(t *testing.T) {
+	c := qt.New(t)
+	handler := func(contents string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(contents))
+			c.Assert(err, qt.IsNil)
+		})
+	}
+	h := versionware.NewHandler([]versionware.VersionHandler{{
+		Version: vervet.MustParseVersion("2021-10-01"),
+		Handler: handler("oct"),
+	}, {
+		Version: vervet.MustParseVersion("2021-11-01~beta"),
+		Handler: handler("nov beta"),
+	}, {
+		Version: vervet.MustParseVersion("2021-12-01"),
+		Handler: handler("dec"),
+	}}...)
+	tests := []struct {
+		requested, resolved       string
+		deprecation, sunset, link string
+	}{{
+		"2021-10-15", "2021-10-01",
+		"@1638316800", "Tue, 31 May 2022 00:00:00 GMT", `</?limit=10&version=2021-12-01>; rel="successor-version"`,
+	}, {
+		"2021-11-15~beta", "2021-11-01~beta",
+		"@1638316800", "Wed, 02 Mar 2022 00:00:00 GMT", `</?limit=10&version=2021-12-01>; rel="successor-version"`,
+	}, {
+		"2021-12-15", "2021-12-01",
+		"", "", "",
+	}}
+	s := httptest.NewServer(h)
+	c.Cleanup(s.Close)
+	for _, test := range tests {
+		c.Run(test.requested, func(c *qt.C) {
+			resp, err := s.Client().Get(s.URL + "?limit=10&version=" + test.requested)
+			c.Assert(err, qt.IsNil)
+			defer resp.Body.Close()
+			c.Assert(resp.StatusCode, qt.Equals, 200)
+			c.Assert(resp.Header.Get(versionware.HeaderSnykVersionServed), qt.Equals, test.resolved)
+			c.Assert(resp.Header.Get(versionware.HeaderDeprecation), qt.Equals, test.deprecation)
+			c.Assert(resp.Header.Get(versionware.HeaderSunset), qt.Equals, test.sunset)
+			c.Assert(resp.Header.Get(versionware.HeaderLink), qt.Equals, test.link)
+		})
+	}
+}
+
+func TestHandlerSemverScheme(t *testing.T) {
+	c := qt.New(t)
+	vhs := []versionware.VersionHandler{}
+	for _, s := range []string{"1.0.0", "1.1.0~beta", "1.10.0"} {
+		v, err := vervet.SemverScheme.ParseVersion(s)
+		c.Assert(err, qt.IsNil)
+		contents := s
+		vhs = append(vhs, versionware.VersionHandler{
+			Version: v,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, err := w.Write([]byte(contents))
+				c.Assert(err, qt.IsNil)
+			}),
+		})
+	}
+	h := versionware.NewHandler(vhs...)
+	policy := vervet.DefaultVersioningPolicy()
+	policy.Scheme = vervet.SemverScheme
+	h.UsePolicy(policy)
+	tests := []struct {
+		requested, resolved string
+		status              int
+	}{{
+		"1.0.1", "1.0.0", 200,
+	}, {
+		// GA versions take precedence over beta versions.
+		"1.2.0", "1.0.0", 200,
+	}, {
+		"1.10.2", "1.10.0", 200,
+	}, {
+		"1.2.0~beta", "", 404,
+	}, {
+		"0.9.0", "", 404,
+	}, {
+		"2024-01-01", "", 400,
+	}}
+	s := httptest.NewServer(h)
+	c.Cleanup(s.Close)
+	for _, test := range tests {
+		c.Run(test.requested, func(c *qt.C) {
+			resp, err := s.Client().Get(s.URL + "?version=" + test.requested)
+			c.Assert(err, qt.IsNil)
+			defer resp.Body.Close()
+			c.Assert(resp.StatusCode, qt.Equals, test.status)
+			if test.status == 200 {
+				contents, err := io.ReadAll(resp.Body)
+				c.Assert(err, qt.IsNil)
+				c.Assert(string(contents), qt.Equals, test.resolved)
+				c.Assert(resp.Header.Get(versionware.HeaderSnykVersionServed), qt.Equals, test.resolved)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package versionware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// ContentTypeJSONAPI is the media type of JSON:API documents.
+const ContentTypeJSONAPI = "application/vnd.api+json"
+
+// Error codes of the JSON:API error objects written by JSONAPIVersionError
+// and JSONAPIValidationError.
+const (
+	// ErrorCodeInvalidVersion is the code of errors caused by an invalid or
+	// unsupported version being requested.
+	ErrorCodeInvalidVersion = "invalid-version"
+
+	// ErrorCodeVersionNotFound is the code of errors caused by no version
+	// matching the version requested.
+	ErrorCodeVersionNotFound = "version-not-found"
+
+	// ErrorCodeNotFound is the code of errors caused by a request for a
+	// route which is not in the OpenAPI spec of the version requested.
+	ErrorCodeNotFound = "not-found"
+
+	// ErrorCodeInvalidRequest is the code of errors caused by a request which
+	// does not conform to the OpenAPI spec of the version requested.
+	ErrorCodeInvalidRequest = "invalid-request"
+
+	// ErrorCodeInternal is the code of errors which are a fault of the
+	// service rather than the request, such as a response which does not
+	// conform to the OpenAPI spec of the version requested.
+	ErrorCodeInternal = "internal-error"
+)
+
+// JSONAPIErrorDocument is a JSON:API document containing errors.
+type JSONAPIErrorDocument struct {
+	JSONAPI JSONAPIObject        `json:"jsonapi"`
+	Errors  []JSONAPIErrorObject `json:"errors"`
+}
+
+// JSONAPIObject describes the JSON:API version of a document.
+type JSONAPIObject struct {
+	Version string `json:"version"`
+}
+
+// JSONAPIErrorObject is a JSON:API error object.
+type JSONAPIErrorObject struct {
+	// Status is the HTTP status code of the error, as a string.
+	Status string `json:"status"`
+
+	// Code is an application-specific error code, one of the ErrorCode
+	// constants.
+	Code string `json:"code"`
+
+	// Title is a short summary of the error, which is the text of its status.
+	Title string `json:"title"`
+
+	// Detail explains the error.
+	Detail string `json:"detail"`
+
+	// Source identifies the part of the request which caused the error, if
+	// it is known.
+	Source *JSONAPIErrorSource `json:"source,omitempty"`
+}
+
+// JSONAPIErrorSource identifies the part of a request which caused an error.
+type JSONAPIErrorSource struct {
+	// Pointer is a JSON pointer to the field of the request body which
+	// caused the error.
+	Pointer string `json:"pointer,omitempty"`
+
+	// Parameter is the query parameter which caused the error.
+	Parameter string `json:"parameter,omitempty"`
+
+	// Header is the request header which caused the error.
+	Header string `json:"header,omitempty"`
+}
+
+// WriteJSONAPIErrors responds with a JSON:API document containing the given
+// error objects.
+func WriteJSONAPIErrors(w http.ResponseWriter, status int, errs ...JSONAPIErrorObject) {
+	w.Header().Set("Content-Type", ContentTypeJSONAPI)
+	w.WriteHeader(status)
+	// The response status has been written, so there is nothing more to be
+	// done if the document cannot be.
+	_ = json.NewEncoder(w).Encode(JSONAPIErrorDocument{
+		JSONAPI: JSONAPIObject{Version: "1.0"},
+		Errors:  errs,
+	})
+}
+
+// JSONAPIVersionError is a VersionErrorHandler which responds with a JSON:API
+// error document. When the version was requested with the "version" query
+// parameter, the error's source is that parameter. Server errors, such as a
+// response which cannot be migrated to the version requested, are not
+// described to the client.
+func JSONAPIVersionError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		WriteJSONAPIErrors(w, status, newJSONAPIError(status, ErrorCodeInternal,
+			"the request could not be served at the version requested"))
+		return
+	}
+	code := ErrorCodeInvalidVersion
+	if status == http.StatusNotFound {
+		code = ErrorCodeVersionNotFound
+	}
+	obj := newJSONAPIError(status, code, err.Error())
+	if r.URL.Query().Has("version") {
+		obj.Source = &JSONAPIErrorSource{Parameter: "version"}
+	}
+	WriteJSONAPIErrors(w, status, obj)
+}
+
+// JSONAPIValidationError is an openapi3filter.ErrFunc which responds with a
+// JSON:API error document. It may be used with a Validator by adding
+// openapi3filter.OnErr(JSONAPIValidationError) to ValidatorConfig.Options.
+//
+// An invalid request is described with an error object for each way in which
+// it is invalid, when validated with the MultiError option, each with the
+// query parameter, header or request body field which caused it. An
+// invalid response is a fault of the service rather than the request, so it
+// is not described to the client.
+func JSONAPIValidationError(
+	_ context.Context, w http.ResponseWriter, status int, code openapi3filter.ErrCode, err error,
+) {
+	switch code {
+	case openapi3filter.ErrCodeCannotFindRoute:
+		WriteJSONAPIErrors(w, status, newJSONAPIError(status, ErrorCodeNotFound,
+			"the requested resource was not found in this version"))
+	case openapi3filter.ErrCodeRequestInvalid:
+		WriteJSONAPIErrors(w, status, jsonapiRequestErrors(status, nil, err)...)
+	default:
+		WriteJSONAPIErrors(w, status, newJSONAPIError(status, ErrorCodeInternal,
+			"the response does not conform to the API specification"))
+	}
+}
+
+func newJSONAPIError(status int, code, detail string) JSONAPIErrorObject {
+	return JSONAPIErrorObject{
+		Status: strconv.Itoa(status),
+		Code:   code,
+		Title:  http.StatusText(status),
+		Detail: detail,
+	}
+}
+
+// jsonapiRequestErrors returns error objects describing an invalid request
+// error, in the context of the request error which contains it, if any.
+func jsonapiRequestErrors(status int, reqErr *openapi3filter.RequestError, err error) []JSONAPIErrorObject {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var objs []JSONAPIErrorObject
+		for i := range e {
+			objs = append(objs, jsonapiRequestErrors(status, reqErr, e[i])...)
+		}
+		return objs
+	case *openapi3filter.RequestError:
+		if e.Err != nil {
+			return jsonapiRequestErrors(status, e, e.Err)
+		}
+		reqErr = e
+	}
+
+	obj := newJSONAPIError(status, ErrorCodeInvalidRequest, err.Error())
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) && schemaErr.Reason != "" {
+		obj.Detail = schemaErr.Reason
+	} else if reqErr != nil && reqErr.Err == nil && reqErr.Reason != "" {
+		obj.Detail = reqErr.Reason
+	}
+	if reqErr == nil {
+		return []JSONAPIErrorObject{obj}
+	}
+	switch {
+	case reqErr.Parameter != nil && reqErr.Parameter.In == openapi3.ParameterInQuery:
+		obj.Source = &JSONAPIErrorSource{Parameter: reqErr.Parameter.Name}
+	case reqErr.Parameter != nil && reqErr.Parameter.In == openapi3.ParameterInHeader:
+		obj.Source = &JSONAPIErrorSource{Header: reqErr.Parameter.Name}
+	case reqErr.RequestBody != nil && schemaErr != nil && len(schemaErr.JSONPointer()) > 0:
+		obj.Source = &JSONAPIErrorSource{Pointer: jsonPointer(schemaErr.JSONPointer())}
+	}
+	return []JSONAPIErrorObject{obj}
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// jsonPointer returns the JSON pointer of a path of reference tokens.
+func jsonPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteString("/" + jsonPointerEscaper.Replace(token))
+	}
+	return sb.String()
+}
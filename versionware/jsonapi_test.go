@@ -0,0 +1,201 @@
+package versionware_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/versionware"
+)
+
+func TestJSONAPIErrors(t *testing.T) {
+	c := qt.New(t)
+	docs := make([]*openapi3.T, 2)
+	for i, specStr := range []string{v20210820, v20210916} {
+		doc, err := openapi3.NewLoader().LoadFromData([]byte(specStr))
+		c.Assert(err, qt.IsNil)
+		c.Assert(doc.Validate(context.Background()), qt.IsNil)
+		docs[i] = doc
+	}
+
+	tests := []struct {
+		name               string
+		handler            validatorTestHandler
+		method, path, body string
+		status             int
+		errs               []versionware.JSONAPIErrorObject
+	}{{
+		name:    "invalid version",
+		handler: validatorTestHandler{}.withDefaults(),
+		method:  "GET",
+		path:    "/test/42?version=latest-please",
+		status:  400,
+		errs: []versionware.JSONAPIErrorObject{{
+			Status: "400",
+			Code:   versionware.ErrorCodeInvalidVersion,
+			Title:  "Bad Request",
+			Detail: `invalid version "latest-please"`,
+			Source: &versionware.JSONAPIErrorSource{Parameter: "version"},
+		}},
+	}, {
+		name:    "version not found",
+		handler: validatorTestHandler{}.withDefaults(),
+		method:  "GET",
+		path:    "/test/42?version=2021-01-01",
+		status:  404,
+		errs: []versionware.JSONAPIErrorObject{{
+			Status: "404",
+			Code:   versionware.ErrorCodeVersionNotFound,
+			Title:  "Not Found",
+			Detail: "no matching version",
+			Source: &versionware.JSONAPIErrorSource{Parameter: "version"},
+		}},
+	}, {
+		name:    "route not found",
+		handler: validatorTestHandler{}.withDefaults(),
+		method:  "GET",
+		path:    "/test?version=2021-09-17",
+		status:  404,
+		errs: []versionware.JSONAPIErrorObject{{
+			Status: "404",
+			Code:   versionware.ErrorCodeNotFound,
+			Title:  "Not Found",
+			Detail: "the requested resource was not found in this version",
+		}},
+	}, {
+		name:    "invalid request body",
+		handler: validatorTestHandler{}.withDefaults(),
+		method:  "POST",
+		path:    "/test?version=2021-09-17",
+		body:    `{"name": 42, "expected": 9, "actual": 10}`,
+		status:  400,
+		errs: []versionware.JSONAPIErrorObject{{
+			Status: "400",
+			Code:   versionware.ErrorCodeInvalidRequest,
+			Title:  "Bad Request",
+			Detail: "value must be a string",
+			Source: &versionware.JSONAPIErrorSource{Pointer: "/name"},
+		}, {
+			Status: "400",
+			Code:   versionware.ErrorCodeInvalidRequest,
+			Title:  "Bad Request",
+			Detail: `property "noodles" is missing`,
+			Source: &versionware.JSONAPIErrorSource{Pointer: "/noodles"},
+		}},
+	}, {
+		name:    "invalid response",
+		handler: validatorTestHandler{getBody: `{"id": "42"}`}.withDefaults(),
+		method:  "GET",
+		path:    "/test/42?version=2021-09-17",
+		status:  500,
+		errs: []versionware.JSONAPIErrorObject{{
+			Status: "500",
+			Code:   versionware.ErrorCodeInternal,
+			Title:  "Internal Server Error",
+			Detail: "the response does not conform to the API specification",
+		}},
+	}}
+	for _, test := range tests {
+		c.Run(test.name, func(c *qt.C) {
+			var h http.Handler
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h.ServeHTTP(w, r)
+			}))
+			defer s.Close()
+
+			v, err := versionware.NewValidator(&versionware.ValidatorConfig{
+				ServerURL:    s.URL,
+				VersionError: versionware.JSONAPIVersionError,
+				Options: []openapi3filter.ValidatorOption{
+					openapi3filter.Strict(true),
+					openapi3filter.ValidationOptions(openapi3filter.Options{MultiError: true}),
+					openapi3filter.OnErr(versionware.JSONAPIValidationError),
+				},
+			}, docs...)
+			c.Assert(err, qt.IsNil)
+			v.SetToday(func() time.Time {
+				return time.Date(2022, time.January, 21, 0, 0, 0, 0, time.UTC)
+			})
+			h = v.Middleware(&test.handler)
+
+			var body io.Reader
+			if test.body != "" {
+				body = bytes.NewBufferString(test.body)
+			}
+			req, err := http.NewRequest(test.method, s.URL+test.path, body)
+			c.Assert(err, qt.IsNil)
+			if test.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			resp, err := s.Client().Do(req)
+			c.Assert(err, qt.IsNil)
+			defer resp.Body.Close()
+			c.Assert(resp.StatusCode, qt.Equals, test.status)
+			c.Assert(resp.Header.Get("Content-Type"), qt.Equals, versionware.ContentTypeJSONAPI)
+
+			var doc versionware.JSONAPIErrorDocument
+			c.Assert(json.NewDecoder(resp.Body).Decode(&doc), qt.IsNil)
+			c.Assert(doc.JSONAPI.Version, qt.Equals, "1.0")
+			c.Assert(doc.Errors, qt.DeepEquals, test.errs)
+		})
+	}
+}
+
+func TestHandlerJSONAPIErrors(t *testing.T) {
+	c := qt.New(t)
+	h := versionware.NewHandler(versionware.VersionHandler{
+		Version: vervet.MustParseVersion("2021-08-20"),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	})
+	h.HandleErrors(versionware.JSONAPIVersionError)
+	h.UseVersionExtractor(versionware.HeaderVersion("Snyk-Version"))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Snyk-Version", "2021-01-01")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	c.Assert(w.Code, qt.Equals, http.StatusNotFound)
+	c.Assert(w.Header().Get("Content-Type"), qt.Equals, versionware.ContentTypeJSONAPI)
+	c.Assert(w.Body.String(), qt.Equals,
+		`{"jsonapi":{"version":"1.0"},"errors":[{"status":"404","code":"version-not-found",`+
+			`"title":"Not Found","detail":"no matching version"}]}`+"\n")
+}
+
+func TestHandlerJSONAPIMigrationError(t *testing.T) {
+	c := qt.New(t)
+	vhs, err := versionware.MigrationHandlers(versionware.VersionHandler{
+		Version: vervet.MustParseVersion("2021-09-16"),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		}),
+	}, versionware.Migration{
+		Version: vervet.MustParseVersion("2021-08-20"),
+		Response: func(req *http.Request, body any) (any, error) {
+			return nil, errors.New("cannot convert")
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	h := versionware.NewHandler(vhs...)
+	h.HandleErrors(versionware.JSONAPIVersionError)
+
+	req := httptest.NewRequest("GET", "/?version=2021-08-20", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	c.Assert(w.Code, qt.Equals, http.StatusInternalServerError)
+	c.Assert(w.Header().Get("Content-Type"), qt.Equals, versionware.ContentTypeJSONAPI)
+	c.Assert(w.Body.String(), qt.Equals,
+		`{"jsonapi":{"version":"1.0"},"errors":[{"status":"500","code":"internal-error",`+
+			`"title":"Internal Server Error","detail":"the request could not be served at the version requested"}]}`+"\n")
+}
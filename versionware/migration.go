@@ -0,0 +1,224 @@
+package versionware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/snyk/vervet/v8"
+)
+
+// Transformer rewrites the decoded JSON body of a request or response served
+// for the given request, returning the body rewritten.
+type Transformer func(req *http.Request, body any) (any, error)
+
+// Migration converts requests and responses between a version of an API and
+// the version after it, so that a single handler for the latest version may
+// serve earlier versions.
+type Migration struct {
+	// Version is the earlier version, from which requests are converted and
+	// to which responses are converted.
+	Version vervet.Version
+
+	// Request converts a JSON request body written for Version into the
+	// version after it. If nil, requests are not converted.
+	Request Transformer
+
+	// Response converts a JSON response body from the version after Version
+	// into Version. If nil, responses are not converted.
+	Response Transformer
+}
+
+// MigrationHandlers returns version handlers which serve the latest version
+// and each of the earlier versions of the given migrations, all with the
+// latest handler. Requests for an earlier version are converted up to the
+// latest version by each migration in turn, oldest first, and responses are
+// converted back down to the version requested, newest first. The version
+// handlers returned may be used with NewHandler.
+//
+// Only JSON bodies of requests and successful responses are converted; error
+// responses are written as served. Requests and responses which cannot be
+// converted are handled by the error handler of the Handler serving them.
+// Responses for earlier versions are buffered so that they may be converted,
+// so these responses may not be streamed.
+func MigrationHandlers(latest VersionHandler, migrations ...Migration) ([]VersionHandler, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Version.Compare(sorted[j].Version) < 0
+	})
+	for i := range sorted {
+		if sorted[i].Version.Compare(latest.Version) >= 0 {
+			return nil, fmt.Errorf("migration version %s is not before the latest version %s",
+				sorted[i].Version, latest.Version)
+		}
+		if i > 0 && sorted[i].Version.Compare(sorted[i-1].Version) == 0 {
+			return nil, fmt.Errorf("duplicate migration version %s", sorted[i].Version)
+		}
+	}
+	vhs := make([]VersionHandler, 0, len(sorted)+1)
+	for i := range sorted {
+		vhs = append(vhs, VersionHandler{
+			Version: sorted[i].Version,
+			Handler: &migrationHandler{handler: latest.Handler, migrations: sorted[i:]},
+		})
+	}
+	return append(vhs, latest), nil
+}
+
+// migrationHandler serves an earlier version with the handler of the latest
+// version, converting requests and responses with the migrations from the
+// earlier version onwards, in version order.
+type migrationHandler struct {
+	handler    http.Handler
+	migrations []Migration
+}
+
+// ServeHTTP implements http.Handler. Migration errors are handled with
+// DefaultVersionError, unless the handler is served by a Handler, which
+// handles them with its own error handler.
+func (h *migrationHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.serveVersion(w, req, DefaultVersionError)
+}
+
+// serveVersion implements versionServer. Requests which cannot be migrated
+// are handled as bad requests, and responses which cannot be migrated as
+// internal server errors. Only successful responses are migrated; other
+// responses are written as served.
+func (h *migrationHandler) serveVersion(w http.ResponseWriter, req *http.Request, errFunc VersionErrorHandler) {
+	if err := h.migrateRequest(req); err != nil {
+		errFunc(w, req, http.StatusBadRequest, err)
+		return
+	}
+	bw := &bufferedResponseWriter{header: w.Header(), status: http.StatusOK}
+	h.handler.ServeHTTP(bw, req)
+	body := bw.body.Bytes()
+	if bw.status >= 200 && bw.status < 300 {
+		var err error
+		body, err = h.migrateResponse(req, bw)
+		if err != nil {
+			errFunc(w, req, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	w.WriteHeader(bw.status)
+	_, _ = w.Write(body)
+}
+
+// migrateRequest converts a JSON request body up to the latest version.
+func (h *migrationHandler) migrateRequest(req *http.Request) error {
+	if req.Body == nil || !isJSON(req.Header.Get("Content-Type")) {
+		return nil
+	}
+	contents, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	_ = req.Body.Close()
+	if len(bytes.TrimSpace(contents)) > 0 {
+		body, err := decodeJSON(contents)
+		if err != nil {
+			return err
+		}
+		for i := range h.migrations {
+			if h.migrations[i].Request == nil {
+				continue
+			}
+			body, err = h.migrations[i].Request(req, body)
+			if err != nil {
+				return fmt.Errorf("failed to migrate request from %s: %w", h.migrations[i].Version, err)
+			}
+		}
+		contents, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		req.ContentLength = int64(len(contents))
+		req.Header.Set("Content-Length", strconv.Itoa(len(contents)))
+	}
+	req.Body = io.NopCloser(bytes.NewReader(contents))
+	return nil
+}
+
+// migrateResponse converts a buffered, successful JSON response body down to
+// the version requested, returning the response body to write.
+func (h *migrationHandler) migrateResponse(req *http.Request, bw *bufferedResponseWriter) ([]byte, error) {
+	contents := bw.body.Bytes()
+	if !isJSON(bw.header.Get("Content-Type")) || len(bytes.TrimSpace(contents)) == 0 {
+		return contents, nil
+	}
+	body, err := decodeJSON(contents)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(h.migrations) - 1; i >= 0; i-- {
+		if h.migrations[i].Response == nil {
+			continue
+		}
+		body, err = h.migrations[i].Response(req, body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate response to %s: %w", h.migrations[i].Version, err)
+		}
+	}
+	contents, err = json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bw.header.Del("Content-Length")
+	return contents, nil
+}
+
+// decodeJSON decodes a JSON body, preserving numbers as written.
+func decodeJSON(contents []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(contents))
+	dec.UseNumber()
+	var body any
+	if err := dec.Decode(&body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// isJSON returns whether a media type is JSON, including JSON-based media
+// types such as application/vnd.api+json.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// bufferedResponseWriter is an http.ResponseWriter which buffers the
+// response body and status, so that the body may be converted before it is
+// written. Headers are written to the underlying response directly.
+type bufferedResponseWriter struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// Header implements http.ResponseWriter.
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status, w.wroteHeader = status, true
+	}
+}
+
+// Write implements http.ResponseWriter.
+func (w *bufferedResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(p)
+}
@@ -0,0 +1,237 @@
+package versionware_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/snyk/vervet/v8"
+	"github.com/snyk/vervet/v8/versionware"
+)
+
+// renameField returns a Transformer which renames a field of a JSON object.
+func renameField(from, to string) versionware.Transformer {
+	return func(req *http.Request, body any) (any, error) {
+		obj, ok := body.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected object")
+		}
+		if v, ok := obj[from]; ok {
+			obj[to] = v
+			delete(obj, from)
+		}
+		return obj, nil
+	}
+}
+
+// thingsHandler serves the latest version of a thing, which has a color.
+func thingsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		thing := map[string]any{}
+		if r.Method == http.MethodPost {
+			if err := json.NewDecoder(r.Body).Decode(&thing); err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+		} else {
+			thing["color"] = "blue"
+		}
+		thing["id"] = "42"
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		if err := json.NewEncoder(w).Encode(thing); err != nil {
+			panic(err)
+		}
+	})
+}
+
+func ExampleMigrationHandlers() {
+	vhs, err := versionware.MigrationHandlers(versionware.VersionHandler{
+		Version: vervet.MustParseVersion("2021-11-01"),
+		Handler: thingsHandler(),
+	}, versionware.Migration{
+		// Before 2021-11-01, color was spelled colour.
+		Version:  vervet.MustParseVersion("2021-10-01"),
+		Request:  renameField("colour", "color"),
+		Response: renameField("color", "colour"),
+	})
+	if err != nil {
+		panic(err)
+	}
+	s := httptest.NewServer(versionware.NewHandler(vhs...))
+	defer s.Close()
+
+	resp, err := s.Client().Get(s.URL + "?version=2021-10-15")
+	if err != nil {
+		panic(err)
+	}
+	defer resp.Body.Close()
+	var thing map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&thing); err != nil {
+		panic(err)
+	}
+	fmt.Println(thing["colour"])
+	// Output: blue
+}
+
+func TestMigrationHandlers(t *testing.T) {
+	c := qt.New(t)
+	vhs, err := versionware.MigrationHandlers(versionware.VersionHandler{
+		Version: vervet.MustParseVersion("2021-12-01"),
+		Handler: thingsHandler(),
+	}, versionware.Migration{
+		// Before 2021-11-01, things had no id.
+		Version: vervet.MustParseVersion("2021-10-01"),
+		Response: func(req *http.Request, body any) (any, error) {
+			delete(body.(map[string]any), "id")
+			return body, nil
+		},
+	}, versionware.Migration{
+		// Before 2021-12-01, color was spelled colour.
+		Version:  vervet.MustParseVersion("2021-11-01"),
+		Request:  renameField("colour", "color"),
+		Response: renameField("color", "colour"),
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(vhs, qt.HasLen, 3)
+	s := httptest.NewServer(versionware.NewHandler(vhs...))
+	c.Cleanup(s.Close)
+
+	tests := []struct {
+		method, version, request string
+		status                   int
+		response                 string
+	}{{
+		"GET", "2021-12-15", "",
+		200, `{"color":"blue","id":"42"}`,
+	}, {
+		"GET", "2021-11-15", "",
+		200, `{"colour":"blue","id":"42"}`,
+	}, {
+		"GET", "2021-10-15", "",
+		200, `{"colour":"blue"}`,
+	}, {
+		"POST", "2021-11-15", `{"colour":"red","size":1.5}`,
+		201, `{"colour":"red","id":"42","size":1.5}`,
+	}, {
+		"POST", "2021-12-15", `{"color":"red"}`,
+		201, `{"color":"red","id":"42"}`,
+	}, {
+		"POST", "2021-11-15", `["red"]`,
+		400, "Bad Request",
+	}}
+	for i, test := range tests {
+		c.Run(fmt.Sprintf("%d %s %s", i, test.method, test.version), func(c *qt.C) {
+			req, err := http.NewRequest(test.method, s.URL+"?version="+test.version, strings.NewReader(test.request))
+			c.Assert(err, qt.IsNil)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := s.Client().Do(req)
+			c.Assert(err, qt.IsNil)
+			defer resp.Body.Close()
+			c.Assert(resp.StatusCode, qt.Equals, test.status)
+			contents, err := io.ReadAll(resp.Body)
+			c.Assert(err, qt.IsNil)
+			c.Assert(strings.TrimSpace(string(contents)), qt.Equals, test.response)
+		})
+	}
+}
+
+func TestMigrationHandlersErrors(t *testing.T) {
+	c := qt.New(t)
+	latest := versionware.VersionHandler{
+		Version: vervet.MustParseVersion("2021-12-01"),
+		Handler: thingsHandler(),
+	}
+	_, err := versionware.MigrationHandlers(latest, versionware.Migration{
+		Version: vervet.MustParseVersion("2021-12-01"),
+	})
+	c.Assert(err, qt.ErrorMatches, `migration version 2021-12-01 is not before the latest version 2021-12-01`)
+	_, err = versionware.MigrationHandlers(latest, versionware.Migration{
+		Version: vervet.MustParseVersion("2021-11-01"),
+	}, versionware.Migration{
+		Version: vervet.MustParseVersion("2021-11-01"),
+	})
+	c.Assert(err, qt.ErrorMatches, `duplicate migration version 2021-11-01`)
+}
+
+func TestMigrationHandlersErrorResponses(t *testing.T) {
+	c := qt.New(t)
+	vhs, err := versionware.MigrationHandlers(versionware.VersionHandler{
+		Version: vervet.MustParseVersion("2021-12-01"),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"color":"none"}`))
+			c.Assert(err, qt.IsNil)
+		}),
+	}, versionware.Migration{
+		Version:  vervet.MustParseVersion("2021-11-01"),
+		Response: renameField("color", "colour"),
+	})
+	c.Assert(err, qt.IsNil)
+	s := httptest.NewServer(versionware.NewHandler(vhs...))
+	c.Cleanup(s.Close)
+
+	resp, err := s.Client().Get(s.URL + "?version=2021-11-15")
+	c.Assert(err, qt.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, qt.Equals, 404)
+	contents, err := io.ReadAll(resp.Body)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(contents), qt.Equals, `{"color":"none"}`)
+}
+
+func TestMigrationHandlersHandleErrors(t *testing.T) {
+	c := qt.New(t)
+	vhs, err := versionware.MigrationHandlers(versionware.VersionHandler{
+		Version: vervet.MustParseVersion("2021-12-01"),
+		Handler: thingsHandler(),
+	}, versionware.Migration{
+		Version: vervet.MustParseVersion("2021-11-01"),
+		Request: renameField("colour", "color"),
+		Response: func(req *http.Request, body any) (any, error) {
+			return nil, fmt.Errorf("cannot convert")
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	h := versionware.NewHandler(vhs...)
+	h.HandleErrors(func(w http.ResponseWriter, r *http.Request, status int, err error) {
+		http.Error(w, err.Error(), status)
+	})
+	s := httptest.NewServer(h)
+	c.Cleanup(s.Close)
+
+	tests := []struct {
+		method, request string
+		status          int
+		response        string
+	}{{
+		"POST", `["red"]`,
+		400, "failed to migrate request from 2021-11-01: expected object",
+	}, {
+		"GET", "",
+		500, "failed to migrate response to 2021-11-01: cannot convert",
+	}}
+	for _, test := range tests {
+		c.Run(test.method, func(c *qt.C) {
+			req, err := http.NewRequest(test.method, s.URL+"?version=2021-11-15", strings.NewReader(test.request))
+			c.Assert(err, qt.IsNil)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := s.Client().Do(req)
+			c.Assert(err, qt.IsNil)
+			defer resp.Body.Close()
+			c.Assert(resp.StatusCode, qt.Equals, test.status)
+			contents, err := io.ReadAll(resp.Body)
+			c.Assert(err, qt.IsNil)
+			c.Assert(strings.TrimSpace(string(contents)), qt.Equals, test.response)
+		})
+	}
+}
@@ -21,6 +21,8 @@ type Validator struct {
 	versions   vervet.VersionIndex
 	validators map[vervet.Version]*openapi3filter.Validator
 	errFunc    VersionErrorHandler
+	policy     *vervet.VersioningPolicy
+	extract    VersionExtractor
 	today      func() time.Time
 }
 
@@ -35,6 +37,16 @@ type ValidatorConfig struct {
 	// API version.
 	VersionError VersionErrorHandler
 
+	// Policy is the versioning policy of the API, which determines the
+	// scheme requested versions are written in, the aliases and the
+	// stabilities which may be requested. If unset, the default versioning
+	// policy is used.
+	Policy *vervet.VersioningPolicy
+
+	// VersionExtractor extracts the version requested from requests. If
+	// unset, the version is extracted from the "version" query parameter.
+	VersionExtractor VersionExtractor
+
 	// Options further configure the request and response validation. See
 	// https://pkg.go.dev/github.com/getkin/kin-openapi/openapi3filter#ValidatorOption
 	// for available options.
@@ -95,8 +107,16 @@ func NewValidator(config *ValidatorConfig, docs ...*openapi3.T) (*Validator, err
 	v := &Validator{
 		validators: map[vervet.Version]*openapi3filter.Validator{},
 		errFunc:    config.VersionError,
+		policy:     config.Policy,
+		extract:    config.VersionExtractor,
 		today:      today,
 	}
+	if v.policy == nil {
+		v.policy = vervet.DefaultVersioningPolicy()
+	}
+	if v.extract == nil {
+		v.extract = DefaultVersionExtractor
+	}
 	serviceVersions := make(vervet.VersionSlice, len(docs))
 	for i := range docs {
 		if config.ServerURL != "" {
@@ -106,7 +126,7 @@ func NewValidator(config *ValidatorConfig, docs ...*openapi3.T) (*Validator, err
 		if err != nil {
 			return nil, err
 		}
-		version, err := vervet.ParseVersion(versionStr)
+		version, err := v.policy.ParseVersion(versionStr)
 		if err != nil {
 			return nil, err
 		}
@@ -129,17 +149,24 @@ func (v *Validator) Middleware(h http.Handler) http.Handler {
 		handlers[version] = validator.Middleware(h)
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		versionParam := req.URL.Query().Get("version")
-		if versionParam == "" {
-			v.errFunc(w, req, http.StatusBadRequest, errors.New("missing required query parameter 'version'"))
+		versionParam, err := v.extract.Extract(req)
+		if err != nil {
+			v.errFunc(w, req, http.StatusBadRequest, err)
 			return
 		}
-		requested, err := vervet.ParseVersion(versionParam)
-		if err != nil {
+		requested, err := v.policy.ParseRequestedVersion(versionParam, &v.versions)
+		if errors.Is(err, vervet.ErrNoMatchingVersion) {
+			v.errFunc(w, req, http.StatusNotFound, err)
+			return
+		} else if err != nil {
 			v.errFunc(w, req, http.StatusBadRequest, err)
 			return
 		}
-		if t := v.today(); requested.Date.After(t) {
+		if !v.policy.Allows(requested.Stability) {
+			v.errFunc(w, req, http.StatusBadRequest, fmt.Errorf("unsupported stability %q", requested.Stability))
+			return
+		}
+		if t := v.today(); !requested.Date.IsZero() && requested.Date.After(t) {
 			v.errFunc(w, req, http.StatusBadRequest,
 				fmt.Errorf("requested version newer than present date %s", t))
 			return
@@ -15,6 +15,7 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
 
+	"github.com/snyk/vervet/v8"
 	"github.com/snyk/vervet/v8/versionware"
 )
 
@@ -414,6 +415,39 @@ func TestValidator(t *testing.T) {
 			body:       `{"id": "42", "contents": {"name": "foo", "expected": 9, "actual": 10}, "extra": true}`,
 		},
 		strict: false,
+	}, {
+		name:    "valid GET of latest version",
+		handler: validatorTestHandler{}.withDefaults(),
+		request: testRequest{
+			method: "GET",
+			path:   "/test/42?version=latest",
+		},
+		response: testResponse{
+			200, v20210916_Body,
+		},
+		strict: true,
+	}, {
+		name:    "valid GET of version alias",
+		handler: validatorTestHandler{}.withDefaults(),
+		request: testRequest{
+			method: "GET",
+			path:   "/test/42?version=sdk-v1",
+		},
+		response: testResponse{
+			200, v20210916_Body,
+		},
+		strict: true,
+	}, {
+		name:    "invalid GET of version alias before any version",
+		handler: validatorTestHandler{}.withDefaults(),
+		request: testRequest{
+			method: "GET",
+			path:   "/test/42?version=sdk-v0",
+		},
+		response: testResponse{
+			404, "Not Found\n",
+		},
+		strict: true,
 	}, {
 		name:    "invalid GET for API in the future",
 		handler: validatorTestHandler{}.withDefaults(),
@@ -438,6 +472,13 @@ func TestValidator(t *testing.T) {
 			config := versionware.DefaultValidatorConfig
 			config.ServerURL = s.URL
 			config.Options = append(config.Options, append(test.options, openapi3filter.Strict(test.strict))...)
+			aliases, err := vervet.ParseVersionAliases(vervet.DateScheme, map[string]string{
+				"sdk-v0": "2021-01-01",
+				"sdk-v1": "2021-09-16",
+			})
+			c.Assert(err, qt.IsNil)
+			config.Policy = vervet.DefaultVersioningPolicy()
+			config.Policy.Aliases = aliases
 			v, err := versionware.NewValidator(&config, docs...)
 			c.Assert(err, qt.IsNil)
 			v.SetToday(func() time.Time {